@@ -6,9 +6,30 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/2rprbm/conta-med-backend/config"
 	httpserver "github.com/2rprbm/conta-med-backend/internal/adapters/primary/http"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/primary/http/handlers"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/cfm"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/faq"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/holidays"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/ibge"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/memory"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/mongodb"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/storage"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/whatsapp"
+	"github.com/2rprbm/conta-med-backend/internal/application/chatbot"
+	"github.com/2rprbm/conta-med-backend/internal/application/dedup"
+	"github.com/2rprbm/conta-med-backend/internal/application/delivery"
+	"github.com/2rprbm/conta-med-backend/internal/application/handoff"
+	"github.com/2rprbm/conta-med-backend/internal/application/history"
+	"github.com/2rprbm/conta-med-backend/internal/application/knowledge"
+	"github.com/2rprbm/conta-med-backend/internal/application/leads"
+	"github.com/2rprbm/conta-med-backend/internal/application/media"
+	"github.com/2rprbm/conta-med-backend/internal/application/worker"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
 	"github.com/2rprbm/conta-med-backend/pkg/logger"
 )
 
@@ -24,8 +45,121 @@ func main() {
 	log := logger.New(cfg.Logging.Level)
 	log.Info("ContaMed WhatsApp Chatbot - Starting server...")
 
+	// Initialize adapters
+	whatsappClient := whatsapp.NewClient(cfg, log)
+
+	// Persist conversations and the message history in the configured repository
+	var database *mongodb.Database
+	var conversations ports.ConversationRepository
+	var contacts ports.ContactRepository
+	var messages ports.MessageRepository
+	var leadRepository ports.LeadRepository
+	var handoffRepository ports.HandoffRepository
+	var faqRepository ports.FAQRepository
+	var statusRepository ports.StatusRepository
+	var dedupStore ports.DedupStore
+	switch cfg.Storage.Repository {
+	case config.RepositoryMongoDB:
+		database, err = mongodb.Connect(context.Background(), cfg.MongoDB)
+		if err != nil {
+			log.Fatal("Error connecting to MongoDB: %v", err)
+		}
+		if err := database.EnsureIndexes(context.Background()); err != nil {
+			log.Fatal("Error creating MongoDB indexes: %v", err)
+		}
+		log.Info("Connected to MongoDB database %s", cfg.MongoDB.Database)
+
+		conversations = mongodb.NewConversationRepository(database)
+		contacts = mongodb.NewContactRepository(database)
+		messages = mongodb.NewMessageRepository(database)
+		leadRepository = mongodb.NewLeadRepository(database)
+		handoffRepository = mongodb.NewHandoffRepository(database)
+		faqRepository = mongodb.NewFAQRepository(database)
+		statusRepository = mongodb.NewStatusRepository(database)
+		dedupStore = mongodb.NewDedupStore(database)
+	case config.RepositoryMemory:
+		log.Warn("Using in-memory repositories, conversations and messages are lost when the server stops")
+		conversations = memory.NewConversationRepository()
+		contacts = memory.NewContactRepository()
+		messages = memory.NewMessageRepository()
+		leadRepository = memory.NewLeadRepository()
+		handoffRepository = memory.NewHandoffRepository()
+		faqRepository = memory.NewFAQRepository()
+		statusRepository = memory.NewStatusRepository()
+
+		// A single instance remembers the handled webhooks across restarts in a file, if configured
+		dedupStore = memory.NewDedupStore()
+		if cfg.Storage.DedupFile != "" {
+			fileStore, err := storage.NewFileDedupStore(cfg.Storage.DedupFile)
+			if err != nil {
+				log.Fatal("Error opening dedup store: %v", err)
+			}
+			defer fileStore.Close()
+			dedupStore = fileStore
+		}
+	default:
+		log.Fatal("Unknown storage repository %q", cfg.Storage.Repository)
+	}
+	recorder := history.NewRecorder(contacts, messages, log)
+
+	// Announce qualified leads to the sales team
+	events := memory.NewEventBus()
+	events.Subscribe(domain.LeadQualifiedEvent, func(ctx context.Context, event domain.Event) error {
+		lead := event.(domain.LeadQualified).Lead
+		log.Info("Lead qualified: %s (%s) wants to open a company in %s/%s", lead.Phone, lead.Name, lead.Municipality, lead.State)
+		return nil
+	})
+	leadService := leads.NewService(leadRepository, events, log)
+
+	// Announce the conversations waiting for an agent
+	events.Subscribe(domain.HandoffOpenedEvent, func(ctx context.Context, event domain.Event) error {
+		opened := event.(domain.HandoffOpened).Handoff
+		log.Info("Handoff opened: %s (%s) waits for an agent, reason %s", opened.Phone, opened.Name, opened.Reason)
+		return nil
+	})
+	sender := recorder.Sender(whatsappClient)
+	handoffService := handoff.NewService(handoffRepository, conversations, messages, sender, events, log)
+
+	// Recognize the states and municipalities typed by the users
+	locations, err := ibge.NewCatalog()
+	if err != nil {
+		log.Fatal("Error loading the IBGE catalog: %v", err)
+	}
+	log.Debug("IBGE catalog loaded with %d municipalities", locations.Len())
+
+	// Answer the questions of the users from the knowledge base, seeded from the versioned file
+	knowledgeService := knowledge.NewService(faqRepository, log)
+	seeded, err := knowledgeService.Seed(context.Background(), loadFAQ(cfg.Storage.FAQFile, log))
+	if err != nil {
+		log.Fatal("Error seeding the knowledge base: %v", err)
+	}
+	if seeded > 0 {
+		log.Info("Knowledge base seeded with %d entries", seeded)
+	}
+
+	// Initialize the conversation engine
+	mediaService := media.NewService(whatsappClient, storage.NewLocalMediaStore(cfg.Storage.MediaDir), log)
+	engine := chatbot.NewEngine(sender, conversations, mediaService, leadService, handoffService, cfm.NewStubVerifier(), locations, knowledgeService, log)
+	engine.Hours = loadBusinessHours(cfg.Business, log)
+	tracker := delivery.NewTracker(statusRepository, log)
+	processor := recorder.Processor(engine)
+
+	// Process the messages in the background, so webhooks are acknowledged without waiting
+	pool := worker.NewPool(processor, cfg.Server.Workers, cfg.Server.QueueSize, log)
+
+	// Ignore redelivered webhooks. The filter is in front of the pool, so a message the pool
+	// refuses is released and its redelivery processed.
+	filter := dedup.NewFilter(pool, tracker, dedupStore, log)
+	if cfg.Storage.DedupTTL > 0 {
+		filter.TTL = cfg.Storage.DedupTTL
+	}
+
 	// Initialize HTTP server
-	server := httpserver.NewServer(cfg, log)
+	server := httpserver.NewServer(cfg, log, filter, filter, pool)
+	server.MountAPI("/leads", handlers.NewLeadHandler(leadService, log).Routes())
+	server.MountAPI("/handoffs", handlers.NewHandoffHandler(handoffService, log).Routes())
+	server.MountAPI("/faq", handlers.NewFAQHandler(knowledgeService, log).Routes())
+	server.MountAPI("/deliveries", handlers.NewDeliveryHandler(tracker, log).Routes())
 
 	// Start server
 	server.Start()
@@ -44,5 +178,53 @@ func main() {
 		log.Error("Server shutdown error: %v", err)
 	}
 
+	// Disconnect from MongoDB once no message is being processed
+	if database != nil {
+		if err := database.Close(ctx); err != nil {
+			log.Error("MongoDB disconnect error: %v", err)
+		}
+	}
+
 	log.Info("Server stopped")
 }
+
+// loadBusinessHours builds the business hours of the team with the national, state and configured holidays
+func loadBusinessHours(cfg config.BusinessConfig, log logger.Logger) *domain.BusinessHours {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Fatal("Invalid business timezone %q: %v", cfg.Timezone, err)
+	}
+	hours, err := domain.ParseBusinessHours(cfg.Hours, cfg.Days, loc)
+	if err != nil {
+		log.Fatal("Invalid business hours: %v", err)
+	}
+
+	observed, err := holidays.StateHolidays()
+	if err != nil {
+		log.Fatal("Error loading the state holidays: %v", err)
+	}
+	if cfg.HolidaysFile != "" {
+		extra, err := holidays.LoadFile(cfg.HolidaysFile)
+		if err != nil {
+			log.Fatal("Error loading the holidays: %v", err)
+		}
+		observed = append(observed, extra...)
+	}
+
+	hours.Calendar = domain.NewCalendar(observed...)
+	hours.State = cfg.State
+	hours.MunicipalityCode = cfg.Municipality
+	return hours
+}
+
+// loadFAQ reads the entries of the configured FAQ file, or the ones embedded in the binary
+func loadFAQ(path string, log logger.Logger) []domain.FAQEntry {
+	entries, err := faq.Entries()
+	if path != "" {
+		entries, err = faq.LoadFile(path)
+	}
+	if err != nil {
+		log.Fatal("Error loading the FAQ: %v", err)
+	}
+	return entries
+}
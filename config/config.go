@@ -13,6 +13,8 @@ type Config struct {
 	Server   ServerConfig
 	MongoDB  MongoDBConfig
 	WhatsApp WhatsAppConfig
+	Storage  StorageConfig
+	Business BusinessConfig
 	Logging  LoggingConfig
 }
 
@@ -25,6 +27,9 @@ type ServerConfig struct {
 	WriteTimeout   time.Duration
 	IdleTimeout    time.Duration
 	ShutdownPeriod time.Duration
+	Workers        int    // number of workers processing inbound messages
+	QueueSize      int    // inbound messages waiting for a worker before the webhook is refused
+	APIKey         string // bearer token of the internal API, which refuses every request when empty
 }
 
 // MongoDBConfig holds MongoDB configuration
@@ -41,6 +46,37 @@ type WhatsAppConfig struct {
 	AccessToken        string
 	PhoneNumberID      string
 	WebhookVerifyToken string
+	RetryMaxAttempts   int
+	RetryBaseDelay     time.Duration
+	RetryMaxDelay      time.Duration
+	RetryJitter        float64 // fraction of the delay, between 0 and 1
+}
+
+// Repository drivers
+const (
+	// RepositoryMongoDB persists the conversations and the message history in MongoDB
+	RepositoryMongoDB = "mongodb"
+	// RepositoryMemory keeps the conversations and the message history while the server runs
+	RepositoryMemory = "memory"
+)
+
+// StorageConfig holds storage configuration
+type StorageConfig struct {
+	Repository string // RepositoryMongoDB or RepositoryMemory
+	MediaDir   string
+	DedupFile  string // file remembering the handled webhook events with RepositoryMemory, kept in memory when empty
+	DedupTTL   time.Duration
+	FAQFile    string // file seeding the knowledge base, the embedded one is used when empty
+}
+
+// BusinessConfig holds the business hours of the team answering the users
+type BusinessConfig struct {
+	Hours        string // opening and closing time of the working days, like "08:00-18:00"
+	Days         string // working days, like "mon-fri" or "mon,wed,fri"
+	Timezone     string
+	State        string // state (UF) whose holidays are observed
+	Municipality string // IBGE code of the municipality whose holidays are observed
+	HolidaysFile string // file with more holidays, only the national and state ones are observed when empty
 }
 
 // LoggingConfig holds logging configuration
@@ -53,6 +89,13 @@ func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
 	godotenv.Load()
 
+	// Development servers without MongoDB keep the data in memory
+	mongoURI := getEnv("MONGODB_URI", "")
+	repository := RepositoryMongoDB
+	if mongoURI == "" {
+		repository = RepositoryMemory
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
 			Port:        getEnv("SERVER_PORT", "8080"),
@@ -63,9 +106,12 @@ func LoadConfig() (*Config, error) {
 			WriteTimeout:   time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 10)) * time.Second,
 			IdleTimeout:    time.Duration(getEnvAsInt("SERVER_IDLE_TIMEOUT", 60)) * time.Second,
 			ShutdownPeriod: time.Duration(getEnvAsInt("SERVER_SHUTDOWN_PERIOD", 10)) * time.Second,
+			Workers:        getEnvAsInt("SERVER_WORKERS", 4),
+			QueueSize:      getEnvAsInt("SERVER_QUEUE_SIZE", 100),
+			APIKey:         getEnv("API_KEY", ""),
 		},
 		MongoDB: MongoDBConfig{
-			URI:      getEnv("MONGODB_URI", ""),
+			URI:      mongoURI,
 			Database: getEnv("MONGODB_DATABASE", "medical_scheduler"),
 			Timeout:  time.Duration(getEnvAsInt("MONGODB_TIMEOUT", 10)) * time.Second,
 		},
@@ -75,6 +121,25 @@ func LoadConfig() (*Config, error) {
 			AccessToken:        getEnv("WHATSAPP_ACCESS_TOKEN", ""),
 			PhoneNumberID:      getEnv("WHATSAPP_PHONE_NUMBER_ID", ""),
 			WebhookVerifyToken: getEnv("WHATSAPP_WEBHOOK_VERIFY_TOKEN", ""),
+			RetryMaxAttempts:   getEnvAsInt("WHATSAPP_RETRY_MAX_ATTEMPTS", 3),
+			RetryBaseDelay:     time.Duration(getEnvAsInt("WHATSAPP_RETRY_BASE_DELAY_MS", 500)) * time.Millisecond,
+			RetryMaxDelay:      time.Duration(getEnvAsInt("WHATSAPP_RETRY_MAX_DELAY_MS", 10000)) * time.Millisecond,
+			RetryJitter:        float64(getEnvAsInt("WHATSAPP_RETRY_JITTER_PERCENT", 20)) / 100,
+		},
+		Storage: StorageConfig{
+			Repository: getEnv("STORAGE_REPOSITORY", repository),
+			MediaDir:   getEnv("MEDIA_STORAGE_DIR", "./data/media"),
+			DedupFile:  getEnv("DEDUP_STORE_FILE", "./data/dedup.log"),
+			DedupTTL:   time.Duration(getEnvAsInt("DEDUP_TTL_HOURS", 168)) * time.Hour,
+			FAQFile:    getEnv("FAQ_FILE", ""),
+		},
+		Business: BusinessConfig{
+			Hours:        getEnv("BUSINESS_HOURS", "08:00-18:00"),
+			Days:         getEnv("BUSINESS_DAYS", "mon-fri"),
+			Timezone:     getEnv("BUSINESS_TIMEZONE", "America/Sao_Paulo"),
+			State:        getEnv("BUSINESS_STATE", ""),
+			Municipality: getEnv("BUSINESS_MUNICIPALITY", ""),
+			HolidaysFile: getEnv("HOLIDAYS_FILE", ""),
 		},
 		Logging: LoggingConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
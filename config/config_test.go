@@ -28,6 +28,9 @@ func TestLoadConfig(t *testing.T) {
 		assert.Equal(t, 10*time.Second, cfg.Server.WriteTimeout)
 		assert.Equal(t, 60*time.Second, cfg.Server.IdleTimeout)
 		assert.Equal(t, 10*time.Second, cfg.Server.ShutdownPeriod)
+		assert.Equal(t, 4, cfg.Server.Workers)
+		assert.Equal(t, 100, cfg.Server.QueueSize)
+		assert.Equal(t, "", cfg.Server.APIKey)
 		assert.Equal(t, "", cfg.MongoDB.URI)
 		assert.Equal(t, "medical_scheduler", cfg.MongoDB.Database)
 		assert.Equal(t, 10*time.Second, cfg.MongoDB.Timeout)
@@ -36,6 +39,15 @@ func TestLoadConfig(t *testing.T) {
 		assert.Equal(t, "", cfg.WhatsApp.AccessToken)
 		assert.Equal(t, "", cfg.WhatsApp.PhoneNumberID)
 		assert.Equal(t, "", cfg.WhatsApp.WebhookVerifyToken)
+		assert.Equal(t, 3, cfg.WhatsApp.RetryMaxAttempts)
+		assert.Equal(t, 500*time.Millisecond, cfg.WhatsApp.RetryBaseDelay)
+		assert.Equal(t, 10*time.Second, cfg.WhatsApp.RetryMaxDelay)
+		assert.Equal(t, 0.2, cfg.WhatsApp.RetryJitter)
+		assert.Equal(t, RepositoryMemory, cfg.Storage.Repository)
+		assert.Equal(t, "./data/media", cfg.Storage.MediaDir)
+		assert.Equal(t, "./data/dedup.log", cfg.Storage.DedupFile)
+		assert.Equal(t, 7*24*time.Hour, cfg.Storage.DedupTTL)
+		assert.Equal(t, BusinessConfig{Hours: "08:00-18:00", Days: "mon-fri", Timezone: "America/Sao_Paulo"}, cfg.Business)
 		assert.Equal(t, "info", cfg.Logging.Level)
 	})
 
@@ -46,10 +58,24 @@ func TestLoadConfig(t *testing.T) {
 		os.Setenv("SERVER_HOST", "api.example.com")
 		os.Setenv("ENV", "production")
 		os.Setenv("SERVER_READ_TIMEOUT", "30")
+		os.Setenv("SERVER_WORKERS", "16")
+		os.Setenv("SERVER_QUEUE_SIZE", "1000")
+		os.Setenv("API_KEY", "api-secret")
 		os.Setenv("MONGODB_URI", "mongodb://localhost:27017")
 		os.Setenv("MONGODB_DATABASE", "test_db")
 		os.Setenv("WHATSAPP_APP_ID", "12345")
 		os.Setenv("WHATSAPP_WEBHOOK_VERIFY_TOKEN", "secret_token")
+		os.Setenv("WHATSAPP_RETRY_MAX_ATTEMPTS", "5")
+		os.Setenv("WHATSAPP_RETRY_BASE_DELAY_MS", "250")
+		os.Setenv("WHATSAPP_RETRY_JITTER_PERCENT", "50")
+		os.Setenv("MEDIA_STORAGE_DIR", "/var/lib/contamed/media")
+		os.Setenv("DEDUP_STORE_FILE", "")
+		os.Setenv("DEDUP_TTL_HOURS", "48")
+		os.Setenv("BUSINESS_HOURS", "09:00-17:30")
+		os.Setenv("BUSINESS_DAYS", "mon-sat")
+		os.Setenv("BUSINESS_STATE", "PR")
+		os.Setenv("BUSINESS_MUNICIPALITY", "4115200")
+		os.Setenv("HOLIDAYS_FILE", "/etc/contamed/feriados.csv")
 		os.Setenv("LOG_LEVEL", "debug")
 
 		// act
@@ -62,13 +88,45 @@ func TestLoadConfig(t *testing.T) {
 		assert.Equal(t, "api.example.com", cfg.Server.Host)
 		assert.Equal(t, "production", cfg.Server.Environment)
 		assert.Equal(t, 30*time.Second, cfg.Server.ReadTimeout)
+		assert.Equal(t, 16, cfg.Server.Workers)
+		assert.Equal(t, 1000, cfg.Server.QueueSize)
+		assert.Equal(t, "api-secret", cfg.Server.APIKey)
 		assert.Equal(t, "mongodb://localhost:27017", cfg.MongoDB.URI)
 		assert.Equal(t, "test_db", cfg.MongoDB.Database)
 		assert.Equal(t, "12345", cfg.WhatsApp.AppID)
 		assert.Equal(t, "secret_token", cfg.WhatsApp.WebhookVerifyToken)
+		assert.Equal(t, 5, cfg.WhatsApp.RetryMaxAttempts)
+		assert.Equal(t, 250*time.Millisecond, cfg.WhatsApp.RetryBaseDelay)
+		assert.Equal(t, 0.5, cfg.WhatsApp.RetryJitter)
+		assert.Equal(t, RepositoryMongoDB, cfg.Storage.Repository)
+		assert.Equal(t, "/var/lib/contamed/media", cfg.Storage.MediaDir)
+		assert.Equal(t, "", cfg.Storage.DedupFile)
+		assert.Equal(t, 48*time.Hour, cfg.Storage.DedupTTL)
+		assert.Equal(t, BusinessConfig{
+			Hours:        "09:00-17:30",
+			Days:         "mon-sat",
+			Timezone:     "America/Sao_Paulo",
+			State:        "PR",
+			Municipality: "4115200",
+			HolidaysFile: "/etc/contamed/feriados.csv",
+		}, cfg.Business)
 		assert.Equal(t, "debug", cfg.Logging.Level)
 	})
 
+	t.Run("should use the configured repository regardless of the MongoDB URI", func(t *testing.T) {
+		// arrange
+		os.Clearenv()
+		os.Setenv("MONGODB_URI", "mongodb://localhost:27017")
+		os.Setenv("STORAGE_REPOSITORY", "memory")
+
+		// act
+		cfg, err := LoadConfig()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, RepositoryMemory, cfg.Storage.Repository)
+	})
+
 	t.Run("should handle invalid numeric values in environment variables", func(t *testing.T) {
 		// arrange
 		os.Clearenv()
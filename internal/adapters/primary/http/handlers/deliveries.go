@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// DeliveryHandler serves the API used by the team to follow the delivery of the messages sent to the users
+type DeliveryHandler struct {
+	deliveries ports.DeliveryTracker
+	logger     logger.Logger
+}
+
+// NewDeliveryHandler creates a new delivery handler
+func NewDeliveryHandler(deliveries ports.DeliveryTracker, log logger.Logger) *DeliveryHandler {
+	return &DeliveryHandler{
+		deliveries: deliveries,
+		logger:     log,
+	}
+}
+
+// Routes returns the routes of the deliveries API
+func (h *DeliveryHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/{id}", h.GetTimeline)
+	return r
+}
+
+// statusEventResponse is the JSON representation of a status notification
+type statusEventResponse struct {
+	Status          string                `json:"status"`
+	Timestamp       time.Time             `json:"timestamp"`
+	RecipientID     string                `json:"recipient_id,omitempty"`
+	ConversationID  string                `json:"conversation_id,omitempty"`
+	PricingCategory string                `json:"pricing_category,omitempty"`
+	Errors          []statusErrorResponse `json:"errors,omitempty"`
+}
+
+// statusErrorResponse is the JSON representation of the reason a message failed
+type statusErrorResponse struct {
+	Code    int    `json:"code"`
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message,omitempty"`
+	Details string `json:"details,omitempty"`
+}
+
+// timelineResponse is the JSON representation of the delivery timeline of a message
+type timelineResponse struct {
+	MessageID string                `json:"message_id"`
+	Status    string                `json:"status"`
+	Events    []statusEventResponse `json:"events"`
+}
+
+// GetTimeline handles GET requests for the delivery timeline of an outbound message
+func (h *DeliveryHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	timeline, err := h.deliveries.Timeline(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "message not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Error loading delivery timeline: %v", err)
+		writeError(w, http.StatusInternalServerError, "error loading delivery timeline")
+		return
+	}
+
+	resp := timelineResponse{
+		MessageID: timeline.MessageID,
+		Status:    string(timeline.Current()),
+		Events:    make([]statusEventResponse, len(timeline.Events)),
+	}
+	for i, event := range timeline.Events {
+		resp.Events[i] = statusEventResponse{
+			Status:          string(event.Status),
+			Timestamp:       event.Timestamp,
+			RecipientID:     event.RecipientID,
+			ConversationID:  event.ConversationID,
+			PricingCategory: event.PricingCategory,
+		}
+		for _, statusErr := range event.Errors {
+			resp.Events[i].Errors = append(resp.Events[i].Errors, statusErrorResponse(statusErr))
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeliveryHandler(t *testing.T) {
+	sentAt := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	timeline := &domain.DeliveryTimeline{MessageID: "wamid.1", Events: []domain.StatusEvent{
+		{MessageID: "wamid.1", RecipientID: "554499887766", Status: domain.StatusSent, Timestamp: sentAt},
+		{MessageID: "wamid.1", RecipientID: "554499887766", Status: domain.StatusFailed, Timestamp: sentAt.Add(time.Minute),
+			Errors: []domain.StatusError{{Code: 131047, Title: "Re-engagement message"}}},
+	}}
+
+	t.Run("should return the timeline of a message", func(t *testing.T) {
+		// arrange
+		tracker := mocks.NewMockDeliveryTracker(gomock.NewController(t))
+		tracker.EXPECT().Timeline(gomock.Any(), "wamid.1").Return(timeline, nil)
+		handler := NewDeliveryHandler(tracker, newMockLogger()).Routes()
+
+		// act
+		rec := serve(handler, http.MethodGet, "/wamid.1", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{
+			"message_id": "wamid.1",
+			"status": "failed",
+			"events": [
+				{"status": "sent", "timestamp": "2024-03-10T12:00:00Z", "recipient_id": "554499887766"},
+				{"status": "failed", "timestamp": "2024-03-10T12:01:00Z", "recipient_id": "554499887766",
+					"errors": [{"code": 131047, "title": "Re-engagement message"}]}
+			]
+		}`, rec.Body.String())
+	})
+
+	t.Run("should return not found for unknown messages", func(t *testing.T) {
+		// arrange
+		tracker := mocks.NewMockDeliveryTracker(gomock.NewController(t))
+		tracker.EXPECT().Timeline(gomock.Any(), "wamid.unknown").Return(nil, domain.ErrNotFound)
+		handler := NewDeliveryHandler(tracker, newMockLogger()).Routes()
+
+		// act
+		rec := serve(handler, http.MethodGet, "/wamid.unknown", "")
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should hide internal errors", func(t *testing.T) {
+		// arrange
+		tracker := mocks.NewMockDeliveryTracker(gomock.NewController(t))
+		tracker.EXPECT().Timeline(gomock.Any(), "wamid.1").Return(nil, errors.New("database down"))
+		handler := NewDeliveryHandler(tracker, newMockLogger()).Routes()
+
+		// act
+		rec := serve(handler, http.MethodGet, "/wamid.1", "")
+
+		// assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "database down")
+	})
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// FAQHandler serves the API used by the team to edit the knowledge base answering the users
+type FAQHandler struct {
+	faq    ports.FAQManager
+	logger logger.Logger
+}
+
+// NewFAQHandler creates a new FAQ handler
+func NewFAQHandler(faq ports.FAQManager, log logger.Logger) *FAQHandler {
+	return &FAQHandler{
+		faq:    faq,
+		logger: log,
+	}
+}
+
+// Routes returns the routes of the FAQ API
+func (h *FAQHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", h.ListEntries)
+	r.Get("/search", h.Search)
+	r.Get("/{id}", h.GetEntry)
+	r.Put("/{id}", h.SaveEntry)
+	r.Delete("/{id}", h.DeleteEntry)
+	return r
+}
+
+// faqResponse is the JSON representation of an entry of the knowledge base
+type faqResponse struct {
+	ID        string    `json:"id"`
+	Category  string    `json:"category,omitempty"`
+	Question  string    `json:"question"`
+	Answer    string    `json:"answer"`
+	Keywords  []string  `json:"keywords"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// newFAQResponse converts an entry to its JSON representation
+func newFAQResponse(entry domain.FAQEntry) faqResponse {
+	keywords := entry.Keywords
+	if keywords == nil {
+		keywords = []string{}
+	}
+	return faqResponse{
+		ID:        entry.ID,
+		Category:  entry.Category,
+		Question:  entry.Question,
+		Answer:    entry.Answer,
+		Keywords:  keywords,
+		UpdatedAt: entry.UpdatedAt,
+	}
+}
+
+// faqRequest is the body of the requests creating or replacing an entry
+type faqRequest struct {
+	Category string   `json:"category"`
+	Question string   `json:"question"`
+	Answer   string   `json:"answer"`
+	Keywords []string `json:"keywords"`
+}
+
+// searchResponse is the result of a search: the answer, or the candidates offered to the user
+type searchResponse struct {
+	Answer     *faqResponse  `json:"answer"`
+	Candidates []faqResponse `json:"candidates"`
+}
+
+// ListEntries handles GET requests listing the entries, optionally of a single category
+func (h *FAQHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.faq.ListFAQ(r.Context(), r.URL.Query().Get("category"))
+	if err != nil {
+		h.writeFAQError(w, err)
+		return
+	}
+
+	resp := make([]faqResponse, len(entries))
+	for i, entry := range entries {
+		resp[i] = newFAQResponse(entry)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Search handles GET requests looking a question up the way the bot does
+func (h *FAQHandler) Search(w http.ResponseWriter, r *http.Request) {
+	question := strings.TrimSpace(r.URL.Query().Get("q"))
+	if question == "" {
+		writeError(w, http.StatusBadRequest, "question required")
+		return
+	}
+
+	match, err := h.faq.SearchFAQ(r.Context(), question)
+	if err != nil {
+		h.writeFAQError(w, err)
+		return
+	}
+
+	resp := searchResponse{Candidates: make([]faqResponse, len(match.Candidates))}
+	if match.Found() {
+		answer := newFAQResponse(*match.Entry)
+		resp.Answer = &answer
+	}
+	for i, entry := range match.Candidates {
+		resp.Candidates[i] = newFAQResponse(entry)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetEntry handles GET requests for a single entry
+func (h *FAQHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.faq.FindFAQ(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeFAQError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newFAQResponse(*entry))
+}
+
+// SaveEntry handles PUT requests creating an entry or replacing the entry with the same ID
+func (h *FAQHandler) SaveEntry(w http.ResponseWriter, r *http.Request) {
+	var req faqRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	entry, err := h.faq.SaveFAQ(r.Context(), domain.FAQEntry{
+		ID:       chi.URLParam(r, "id"),
+		Category: req.Category,
+		Question: req.Question,
+		Answer:   req.Answer,
+		Keywords: req.Keywords,
+	})
+	if err != nil {
+		h.writeFAQError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newFAQResponse(*entry))
+}
+
+// DeleteEntry handles DELETE requests removing an entry
+func (h *FAQHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	if err := h.faq.DeleteFAQ(r.Context(), chi.URLParam(r, "id")); err != nil {
+		h.writeFAQError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeFAQError writes the response of a failed FAQ operation
+func (h *FAQHandler) writeFAQError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, "FAQ entry not found")
+	case errors.Is(err, domain.ErrInvalidFAQEntry):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error("Error handling FAQ entry: %v", err)
+		writeError(w, http.StatusInternalServerError, "error handling FAQ entry")
+	}
+}
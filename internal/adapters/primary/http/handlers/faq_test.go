@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	fatorREntry    = domain.FAQEntry{ID: "fator-r", Category: "simples-nacional", Question: "O que é o Fator R?", Answer: "É a razão entre a folha e o faturamento.", Keywords: []string{"anexo"}}
+	carneLeaoEntry = domain.FAQEntry{ID: "carne-leao", Category: "irpf", Question: "O que é o carnê-leão?", Answer: "É o recolhimento mensal do IR."}
+)
+
+func newTestFAQHandler(t *testing.T) (http.Handler, *mocks.MockFAQManager) {
+	manager := mocks.NewMockFAQManager(gomock.NewController(t))
+	return NewFAQHandler(manager, newMockLogger()).Routes(), manager
+}
+
+func TestFAQHandler(t *testing.T) {
+	t.Run("should list the entries of a category", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		manager.EXPECT().ListFAQ(gomock.Any(), "irpf").Return([]domain.FAQEntry{carneLeaoEntry}, nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/?category=irpf", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Len(t, body, 1)
+		assert.Equal(t, "carne-leao", body[0]["id"])
+		assert.Equal(t, []interface{}{}, body[0]["keywords"])
+	})
+
+	t.Run("should return an entry by ID", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		manager.EXPECT().FindFAQ(gomock.Any(), "fator-r").Return(&fatorREntry, nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/fator-r", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"question":"O que é o Fator R?"`)
+		assert.Contains(t, rec.Body.String(), `"keywords":["anexo"]`)
+	})
+
+	t.Run("should return not found for unknown entries", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		manager.EXPECT().FindFAQ(gomock.Any(), "mei").Return(nil, domain.ErrNotFound)
+		manager.EXPECT().DeleteFAQ(gomock.Any(), "mei").Return(fmt.Errorf("error deleting FAQ entry: %w", domain.ErrNotFound))
+
+		// act
+		get := serve(handler, http.MethodGet, "/mei", "")
+		del := serve(handler, http.MethodDelete, "/mei", "")
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, get.Code)
+		assert.JSONEq(t, `{"error":"FAQ entry not found"}`, get.Body.String())
+		assert.Equal(t, http.StatusNotFound, del.Code)
+	})
+
+	t.Run("should create and replace entries", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		expected := domain.FAQEntry{ID: "mei", Category: "pessoa-juridica", Question: "Médico pode ser MEI?", Answer: "Não.", Keywords: []string{"mei"}}
+		manager.EXPECT().SaveFAQ(gomock.Any(), expected).DoAndReturn(func(ctx context.Context, entry domain.FAQEntry) (*domain.FAQEntry, error) {
+			entry.UpdatedAt = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+			return &entry, nil
+		})
+
+		// act
+		rec := serve(handler, http.MethodPut, "/mei", `{"category":"pessoa-juridica","question":"Médico pode ser MEI?","answer":"Não.","keywords":["mei"]}`)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"updated_at":"2024-03-10T12:00:00Z"`)
+		assert.Contains(t, rec.Body.String(), `"keywords":["mei"]`)
+	})
+
+	t.Run("should reject invalid entries", func(t *testing.T) {
+		for target, body := range map[string]string{
+			"/mei":     `{"question":"Médico pode ser MEI?"}`,
+			"/Not-Ok!": `{"question":"?","answer":"!"}`,
+			"/fator-r": `not json`,
+		} {
+			// arrange
+			handler, manager := newTestFAQHandler(t)
+			manager.EXPECT().SaveFAQ(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry domain.FAQEntry) (*domain.FAQEntry, error) {
+				return nil, entry.Validate()
+			}).MaxTimes(1)
+
+			// act
+			rec := serve(handler, http.MethodPut, target, body)
+
+			// assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+	})
+
+	t.Run("should delete an entry", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		manager.EXPECT().DeleteFAQ(gomock.Any(), "fator-r").Return(nil)
+
+		// act
+		rec := serve(handler, http.MethodDelete, "/fator-r", "")
+
+		// assert
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("should search the answer of a question", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		manager.EXPECT().SearchFAQ(gomock.Any(), "fator r").Return(domain.FAQMatch{Entry: &fatorREntry}, nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/search?q=fator+r", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body searchResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "fator-r", body.Answer.ID)
+		assert.Empty(t, body.Candidates)
+	})
+
+	t.Run("should search the candidates of an ambiguous question", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		manager.EXPECT().SearchFAQ(gomock.Any(), "imposto").Return(domain.FAQMatch{Candidates: []domain.FAQEntry{fatorREntry, carneLeaoEntry}}, nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/search?q=imposto", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"answer":null`)
+		var body searchResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Len(t, body.Candidates, 2)
+	})
+
+	t.Run("should require the question to search", func(t *testing.T) {
+		// arrange
+		handler, _ := newTestFAQHandler(t)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/search?q=+", "")
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should hide internal errors", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestFAQHandler(t)
+		manager.EXPECT().ListFAQ(gomock.Any(), "").Return(nil, errors.New("database down"))
+
+		// act
+		rec := serve(handler, http.MethodGet, "/", "")
+
+		// assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "database down")
+	})
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// maxHandoffsLimit bounds the number of handoffs returned by a single request
+	maxHandoffsLimit = 500
+	// maxMessagesLimit bounds the number of messages returned by a single request
+	maxMessagesLimit = 500
+	// maxReplyLength is the maximum length of a text message accepted by WhatsApp
+	maxReplyLength = 4096
+)
+
+// HandoffHandler serves the inbox API used by the agents to answer the users handed off by the bot
+type HandoffHandler struct {
+	handoffs ports.HandoffManager
+	logger   logger.Logger
+}
+
+// NewHandoffHandler creates a new handoff handler
+func NewHandoffHandler(handoffs ports.HandoffManager, log logger.Logger) *HandoffHandler {
+	return &HandoffHandler{
+		handoffs: handoffs,
+		logger:   log,
+	}
+}
+
+// Routes returns the routes of the handoffs API
+func (h *HandoffHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", h.ListHandoffs)
+	r.Get("/{phone}", h.GetHandoff)
+	r.Get("/{phone}/messages", h.ListMessages)
+	r.Post("/{phone}/messages", h.Reply)
+	r.Post("/{phone}/assign", h.Assign)
+	r.Post("/{phone}/close", h.Close)
+	return r
+}
+
+// handoffResponse is the JSON representation of a handoff
+type handoffResponse struct {
+	Phone         string     `json:"phone"`
+	Name          string     `json:"name,omitempty"`
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`
+	Agent         string     `json:"agent,omitempty"`
+	OpenedAt      time.Time  `json:"opened_at"`
+	LastMessageAt time.Time  `json:"last_message_at"`
+	LastReplyAt   *time.Time `json:"last_reply_at,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty"`
+}
+
+// newHandoffResponse converts a handoff to its JSON representation
+func newHandoffResponse(handoff domain.Handoff) handoffResponse {
+	resp := handoffResponse{
+		Phone:         handoff.Phone,
+		Name:          handoff.Name,
+		Reason:        string(handoff.Reason),
+		Status:        string(handoff.Status),
+		Agent:         handoff.Agent,
+		OpenedAt:      handoff.OpenedAt,
+		LastMessageAt: handoff.LastMessageAt,
+		UpdatedAt:     handoff.UpdatedAt,
+	}
+	if !handoff.LastReplyAt.IsZero() {
+		resp.LastReplyAt = &handoff.LastReplyAt
+	}
+	if !handoff.ClosedAt.IsZero() {
+		resp.ClosedAt = &handoff.ClosedAt
+	}
+	return resp
+}
+
+// messageResponse is the JSON representation of a message of the history
+type messageResponse struct {
+	ID        string    `json:"id"`
+	Direction string    `json:"direction"`
+	Type      string    `json:"type"`
+	Text      string    `json:"text,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// replyRequest is the body of the requests answering a user
+type replyRequest struct {
+	Agent string `json:"agent"`
+	Text  string `json:"text"`
+}
+
+// replyResponse identifies the message sent to the user
+type replyResponse struct {
+	MessageID string `json:"message_id"`
+}
+
+// assignRequest is the body of the requests assigning a handoff
+type assignRequest struct {
+	Agent string `json:"agent"`
+}
+
+// ListHandoffs handles GET requests listing the open handoffs, optionally filtered by status and agent
+func (h *HandoffHandler) ListHandoffs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.HandoffFilter{
+		Status: domain.HandoffStatus(query.Get("status")),
+		Agent:  query.Get("agent"),
+		Limit:  100,
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		writeError(w, http.StatusBadRequest, "invalid status")
+		return
+	}
+	limit, ok := parseLimit(query.Get("limit"), filter.Limit, maxHandoffsLimit)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	filter.Limit = limit
+
+	handoffs, err := h.handoffs.ListHandoffs(r.Context(), filter)
+	if err != nil {
+		h.logger.Error("Error listing handoffs: %v", err)
+		writeError(w, http.StatusInternalServerError, "error listing handoffs")
+		return
+	}
+
+	resp := make([]handoffResponse, len(handoffs))
+	for i, handoff := range handoffs {
+		resp[i] = newHandoffResponse(handoff)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetHandoff handles GET requests for a single handoff
+func (h *HandoffHandler) GetHandoff(w http.ResponseWriter, r *http.Request) {
+	handoff, err := h.handoffs.FindHandoff(r.Context(), chi.URLParam(r, "phone"))
+	if err != nil {
+		h.writeHandoffError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newHandoffResponse(*handoff))
+}
+
+// ListMessages handles GET requests for the message history of the user of a handoff, oldest first
+func (h *HandoffHandler) ListMessages(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(r.URL.Query().Get("limit"), 50, maxMessagesLimit)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	messages, err := h.handoffs.ListMessages(r.Context(), chi.URLParam(r, "phone"), limit)
+	if err != nil {
+		h.writeHandoffError(w, err)
+		return
+	}
+
+	resp := make([]messageResponse, len(messages))
+	for i, message := range messages {
+		resp[i] = messageResponse{
+			ID:        message.ID,
+			Direction: string(message.Direction),
+			Type:      string(message.Type),
+			Text:      message.Text,
+			Timestamp: message.Timestamp,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Reply handles POST requests sending a text message of an agent to the user of a handoff
+func (h *HandoffHandler) Reply(w http.ResponseWriter, r *http.Request) {
+	var req replyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len([]rune(req.Text)) > maxReplyLength {
+		writeError(w, http.StatusBadRequest, "text too long")
+		return
+	}
+
+	result, err := h.handoffs.Reply(r.Context(), chi.URLParam(r, "phone"), req.Agent, req.Text)
+	if err != nil {
+		h.writeHandoffError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, replyResponse{MessageID: result.MessageID})
+}
+
+// Assign handles POST requests assigning a handoff to an agent or transferring it to another one
+func (h *HandoffHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	var req assignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	handoff, err := h.handoffs.Assign(r.Context(), chi.URLParam(r, "phone"), req.Agent)
+	if err != nil {
+		h.writeHandoffError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newHandoffResponse(*handoff))
+}
+
+// Close handles POST requests giving a conversation back to the bot
+func (h *HandoffHandler) Close(w http.ResponseWriter, r *http.Request) {
+	handoff, err := h.handoffs.Close(r.Context(), chi.URLParam(r, "phone"))
+	if err != nil {
+		h.writeHandoffError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newHandoffResponse(*handoff))
+}
+
+// writeHandoffError writes the response of a failed handoff operation
+func (h *HandoffHandler) writeHandoffError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, "handoff not found")
+	case errors.Is(err, domain.ErrAgentRequired):
+		writeError(w, http.StatusBadRequest, "agent required")
+	case errors.Is(err, domain.ErrHandoffClosed), errors.Is(err, domain.ErrHandoffAssigned):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrTemplateRequired):
+		writeError(w, http.StatusUnprocessableEntity, "the user has not written in the last 24 hours, only template messages can be sent")
+	case errors.Is(err, domain.ErrRecipientUnavailable):
+		writeError(w, http.StatusUnprocessableEntity, "the user can not receive messages")
+	default:
+		h.logger.Error("Error handling handoff: %v", err)
+		writeError(w, http.StatusInternalServerError, "error handling handoff")
+	}
+}
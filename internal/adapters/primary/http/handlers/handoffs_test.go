@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestHandoff returns a handoff of the test phone waiting for an agent since a fixed time
+func newTestHandoff() *domain.Handoff {
+	handoff := domain.NewHandoff("554499887766", "Dra. Ana", domain.HandoffReasonOther, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC))
+	handoff.PullEvents()
+	return handoff
+}
+
+func newTestHandoffHandler(t *testing.T) (http.Handler, *mocks.MockHandoffManager) {
+	manager := mocks.NewMockHandoffManager(gomock.NewController(t))
+	return NewHandoffHandler(manager, newMockLogger()).Routes(), manager
+}
+
+// serve sends the request to the handler and returns the response
+func serve(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandoffHandler(t *testing.T) {
+	t.Run("should list the handoffs filtered by status and agent", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().ListHandoffs(gomock.Any(), domain.HandoffFilter{Status: domain.HandoffWaiting, Agent: "maria", Limit: 10}).Return([]domain.Handoff{*newTestHandoff()}, nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/?status=waiting&agent=maria&limit=10", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Len(t, body, 1)
+		assert.Equal(t, "554499887766", body[0]["phone"])
+		assert.Equal(t, "Dra. Ana", body[0]["name"])
+		assert.Equal(t, "other", body[0]["reason"])
+		assert.Equal(t, "waiting", body[0]["status"])
+		assert.Equal(t, "2024-03-10T12:00:00Z", body[0]["opened_at"])
+		assert.NotContains(t, body[0], "closed_at")
+	})
+
+	t.Run("should list the open handoffs by default", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().ListHandoffs(gomock.Any(), domain.HandoffFilter{Limit: 100}).Return(nil, nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		for _, query := range []string{"?status=open", "?limit=0", "?limit=abc", "?limit=1000"} {
+			// arrange
+			handler, _ := newTestHandoffHandler(t)
+
+			// act
+			rec := serve(handler, http.MethodGet, "/"+query, "")
+
+			// assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("should return a handoff by phone", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().FindHandoff(gomock.Any(), "554499887766").Return(newTestHandoff(), nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/554499887766", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"waiting"`)
+	})
+
+	t.Run("should return not found for unknown handoffs", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().ListMessages(gomock.Any(), "554400000000", gomock.Any()).Return(nil, fmt.Errorf("error loading handoff: %w", domain.ErrNotFound))
+
+		// act
+		rec := serve(handler, http.MethodGet, "/554400000000/messages", "")
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"error":"handoff not found"}`, rec.Body.String())
+	})
+
+	t.Run("should return the message history of the user", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+		manager.EXPECT().ListMessages(gomock.Any(), "554499887766", 20).Return([]domain.MessageRecord{
+			{ID: "wamid.in.1", Phone: "554499887766", Direction: domain.DirectionInbound, Type: domain.MessageTypeText, Text: "4", Timestamp: now},
+			{ID: "wamid.out.1", Phone: "554499887766", Direction: domain.DirectionOutbound, Type: domain.MessageTypeText, Text: "Certo!", Timestamp: now},
+		}, nil)
+
+		// act
+		rec := serve(handler, http.MethodGet, "/554499887766/messages?limit=20", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Len(t, body, 2)
+		assert.Equal(t, "inbound", body[0]["direction"])
+		assert.Equal(t, "Certo!", body[1]["text"])
+	})
+
+	t.Run("should send the reply of the agent", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().Reply(gomock.Any(), "554499887766", "maria", "Olá, sou a Maria!").Return(&domain.SendResult{MessageID: "wamid.out.1"}, nil)
+
+		// act
+		rec := serve(handler, http.MethodPost, "/554499887766/messages", `{"agent":"maria","text":"Olá, sou a Maria!"}`)
+
+		// assert
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"message_id":"wamid.out.1"}`, rec.Body.String())
+	})
+
+	t.Run("should reject invalid replies", func(t *testing.T) {
+		for _, body := range []string{`{"agent":"maria"}`, `{"agent":"maria","text":"  "}`, `not json`, `{"agent":"maria","text":"` + strings.Repeat("a", 4097) + `"}`} {
+			// arrange
+			handler, _ := newTestHandoffHandler(t)
+
+			// act
+			rec := serve(handler, http.MethodPost, "/554499887766/messages", body)
+
+			// assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("should require the agent", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().Reply(gomock.Any(), "554499887766", "", "Olá!").Return(nil, domain.ErrAgentRequired)
+
+		// act
+		rec := serve(handler, http.MethodPost, "/554499887766/messages", `{"text":"Olá!"}`)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":"agent required"}`, rec.Body.String())
+	})
+
+	t.Run("should refuse replies of agents not assigned to the handoff", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().Reply(gomock.Any(), "554499887766", "maria", "Olá!").Return(nil, fmt.Errorf("%w: 554499887766 is answered by joao", domain.ErrHandoffAssigned))
+
+		// act
+		rec := serve(handler, http.MethodPost, "/554499887766/messages", `{"agent":"maria","text":"Olá!"}`)
+
+		// assert
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("should tell when the user is outside the customer service window", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().Reply(gomock.Any(), "554499887766", "maria", "Olá!").Return(nil, fmt.Errorf("error sending reply: whatsapp error 131047: %w", domain.ErrTemplateRequired))
+
+		// act
+		rec := serve(handler, http.MethodPost, "/554499887766/messages", `{"agent":"maria","text":"Olá!"}`)
+
+		// assert
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "24 hours")
+	})
+
+	t.Run("should transfer the handoff to another agent", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		handoff := newTestHandoff()
+		handoff.Assign("joao", time.Date(2024, 3, 10, 12, 5, 0, 0, time.UTC))
+		manager.EXPECT().Assign(gomock.Any(), "554499887766", "joao").Return(handoff, nil)
+
+		// act
+		rec := serve(handler, http.MethodPost, "/554499887766/assign", `{"agent":"joao"}`)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"agent":"joao"`)
+		assert.Contains(t, rec.Body.String(), `"status":"assigned"`)
+	})
+
+	t.Run("should close the handoff once", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		closed := newTestHandoff()
+		closed.Close(time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC))
+		gomock.InOrder(
+			manager.EXPECT().Close(gomock.Any(), "554499887766").Return(closed, nil),
+			manager.EXPECT().Close(gomock.Any(), "554499887766").Return(nil, fmt.Errorf("%w: 554499887766", domain.ErrHandoffClosed)),
+		)
+
+		// act
+		rec := serve(handler, http.MethodPost, "/554499887766/close", "")
+		again := serve(handler, http.MethodPost, "/554499887766/close", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"closed"`)
+		assert.Contains(t, rec.Body.String(), `"closed_at"`)
+		assert.Equal(t, http.StatusConflict, again.Code)
+	})
+
+	t.Run("should hide internal errors", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestHandoffHandler(t)
+		manager.EXPECT().FindHandoff(gomock.Any(), "554499887766").Return(nil, errors.New("database down"))
+
+		// act
+		rec := serve(handler, http.MethodGet, "/554499887766", "")
+
+		// assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "database down")
+	})
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxLeadsLimit bounds the number of leads returned by a single request
+const maxLeadsLimit = 500
+
+// LeadHandler serves the leads API used by the sales team
+type LeadHandler struct {
+	leads  ports.LeadManager
+	logger logger.Logger
+}
+
+// NewLeadHandler creates a new lead handler
+func NewLeadHandler(leads ports.LeadManager, log logger.Logger) *LeadHandler {
+	return &LeadHandler{
+		leads:  leads,
+		logger: log,
+	}
+}
+
+// Routes returns the routes of the leads API
+func (h *LeadHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", h.ListLeads)
+	r.Get("/{phone}", h.GetLead)
+	r.Patch("/{phone}", h.UpdateLead)
+	return r
+}
+
+// leadResponse is the JSON representation of a lead
+type leadResponse struct {
+	Phone            string     `json:"phone"`
+	Name             string     `json:"name,omitempty"`
+	HasCRM           bool       `json:"has_crm"`
+	CRMNumber        string     `json:"crm_number,omitempty"`
+	CRMState         string     `json:"crm_state,omitempty"`
+	CRMSuffix        string     `json:"crm_suffix,omitempty"`
+	CRMVerified      bool       `json:"crm_verified"`
+	Specialty        string     `json:"specialty,omitempty"`
+	State            string     `json:"state,omitempty"`
+	Municipality     string     `json:"municipality,omitempty"`
+	MunicipalityCode string     `json:"municipality_code,omitempty"`
+	Source           string     `json:"source"`
+	Stage            string     `json:"stage"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	QualifiedAt      *time.Time `json:"qualified_at,omitempty"`
+}
+
+// newLeadResponse converts a lead to its JSON representation
+func newLeadResponse(lead domain.Lead) leadResponse {
+	resp := leadResponse{
+		Phone:            lead.Phone,
+		Name:             lead.Name,
+		HasCRM:           lead.HasCRM,
+		CRMNumber:        lead.CRMNumber,
+		CRMState:         lead.CRMState,
+		CRMSuffix:        lead.CRMSuffix,
+		CRMVerified:      lead.CRMVerified,
+		Specialty:        lead.Specialty,
+		State:            lead.State,
+		Municipality:     lead.Municipality,
+		MunicipalityCode: lead.MunicipalityCode,
+		Source:           string(lead.Source),
+		Stage:            string(lead.Stage),
+		CreatedAt:        lead.CreatedAt,
+		UpdatedAt:        lead.UpdatedAt,
+	}
+	if !lead.QualifiedAt.IsZero() {
+		resp.QualifiedAt = &lead.QualifiedAt
+	}
+	return resp
+}
+
+// updateLeadRequest is the body of PATCH requests
+type updateLeadRequest struct {
+	Stage string `json:"stage"`
+}
+
+// ListLeads handles GET requests listing the leads, optionally filtered by stage
+func (h *LeadHandler) ListLeads(w http.ResponseWriter, r *http.Request) {
+	filter := domain.LeadFilter{
+		Stage: domain.LeadStage(r.URL.Query().Get("stage")),
+		Limit: 100,
+	}
+	if filter.Stage != "" && !filter.Stage.IsValid() {
+		writeError(w, http.StatusBadRequest, "invalid stage")
+		return
+	}
+	limit, ok := parseLimit(r.URL.Query().Get("limit"), filter.Limit, maxLeadsLimit)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	filter.Limit = limit
+
+	leads, err := h.leads.ListLeads(r.Context(), filter)
+	if err != nil {
+		h.logger.Error("Error listing leads: %v", err)
+		writeError(w, http.StatusInternalServerError, "error listing leads")
+		return
+	}
+
+	resp := make([]leadResponse, len(leads))
+	for i, lead := range leads {
+		resp[i] = newLeadResponse(lead)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetLead handles GET requests for a single lead
+func (h *LeadHandler) GetLead(w http.ResponseWriter, r *http.Request) {
+	lead, err := h.leads.FindLead(r.Context(), chi.URLParam(r, "phone"))
+	if err != nil {
+		h.writeLeadError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newLeadResponse(*lead))
+}
+
+// UpdateLead handles PATCH requests moving a lead to another funnel stage
+func (h *LeadHandler) UpdateLead(w http.ResponseWriter, r *http.Request) {
+	var req updateLeadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Stage == "" {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	lead, err := h.leads.MoveLead(r.Context(), chi.URLParam(r, "phone"), domain.LeadStage(req.Stage))
+	if err != nil {
+		h.writeLeadError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newLeadResponse(*lead))
+}
+
+// writeLeadError writes the response of a failed lead operation
+func (h *LeadHandler) writeLeadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, "lead not found")
+	case errors.Is(err, domain.ErrInvalidLeadStage):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		h.logger.Error("Error handling lead: %v", err)
+		writeError(w, http.StatusInternalServerError, "error handling lead")
+	}
+}
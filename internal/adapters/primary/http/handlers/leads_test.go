@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestLead returns a lead of the test phone qualified at a fixed time
+func newTestLead() *domain.Lead {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	lead := domain.NewLead("554499887766", domain.LeadSourceWhatsApp, now)
+	lead.Name = "Dra. Ana"
+	lead.State = "PR"
+	lead.Municipality = "Maringá"
+	lead.MunicipalityCode = "4115200"
+	lead.Qualify(now)
+	lead.PullEvents()
+	return lead
+}
+
+func newTestLeadHandler(t *testing.T) (http.Handler, *mocks.MockLeadManager) {
+	manager := mocks.NewMockLeadManager(gomock.NewController(t))
+	return NewLeadHandler(manager, newMockLogger()).Routes(), manager
+}
+
+// moveLead makes the manager move the lead with the rules of the domain
+func moveLead(manager *mocks.MockLeadManager, lead *domain.Lead) {
+	manager.EXPECT().MoveLead(gomock.Any(), lead.Phone, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, phone string, stage domain.LeadStage) (*domain.Lead, error) {
+			if err := lead.MoveTo(stage, time.Now()); err != nil {
+				return nil, err
+			}
+			return lead, nil
+		})
+}
+
+func TestLeadHandler(t *testing.T) {
+	t.Run("should list the leads filtered by stage", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestLeadHandler(t)
+		manager.EXPECT().ListLeads(gomock.Any(), domain.LeadFilter{Stage: domain.LeadStageQualified, Limit: 10}).Return([]domain.Lead{*newTestLead()}, nil)
+		req := httptest.NewRequest(http.MethodGet, "/?stage=qualified&limit=10", nil)
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var body []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Len(t, body, 1)
+		assert.Equal(t, "554499887766", body[0]["phone"])
+		assert.Equal(t, "Dra. Ana", body[0]["name"])
+		assert.Equal(t, "qualified", body[0]["stage"])
+		assert.Equal(t, "4115200", body[0]["municipality_code"])
+		assert.Equal(t, "2024-03-10T12:00:00Z", body[0]["qualified_at"])
+	})
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		for _, query := range []string{"?stage=won", "?limit=0", "?limit=abc", "?limit=1000"} {
+			// arrange
+			handler, _ := newTestLeadHandler(t)
+			req := httptest.NewRequest(http.MethodGet, "/"+query, nil)
+			rec := httptest.NewRecorder()
+
+			// act
+			handler.ServeHTTP(rec, req)
+
+			// assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("should return a lead by phone", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestLeadHandler(t)
+		manager.EXPECT().FindLead(gomock.Any(), "554499887766").Return(newTestLead(), nil)
+		req := httptest.NewRequest(http.MethodGet, "/554499887766", nil)
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"municipality":"Maringá"`)
+	})
+
+	t.Run("should return not found for unknown leads", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestLeadHandler(t)
+		manager.EXPECT().FindLead(gomock.Any(), "554400000000").Return(nil, domain.ErrNotFound)
+		req := httptest.NewRequest(http.MethodGet, "/554400000000", nil)
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"error":"lead not found"}`, rec.Body.String())
+	})
+
+	t.Run("should move a lead to another stage", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestLeadHandler(t)
+		lead := newTestLead()
+		moveLead(manager, lead)
+		req := httptest.NewRequest(http.MethodPatch, "/554499887766", strings.NewReader(`{"stage":"contacted"}`))
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"stage":"contacted"`)
+		assert.Equal(t, domain.LeadStageContacted, lead.Stage)
+	})
+
+	t.Run("should reject invalid moves", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestLeadHandler(t)
+		moveLead(manager, newTestLead())
+		req := httptest.NewRequest(http.MethodPatch, "/554499887766", strings.NewReader(`{"stage":"new"}`))
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("should reject moving a contacted lead back to qualified", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestLeadHandler(t)
+		lead := newTestLead()
+		lead.MoveTo(domain.LeadStageContacted, time.Now())
+		moveLead(manager, lead)
+		req := httptest.NewRequest(http.MethodPatch, "/554499887766", strings.NewReader(`{"stage":"qualified"}`))
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, domain.LeadStageContacted, lead.Stage)
+	})
+
+	t.Run("should reject invalid bodies", func(t *testing.T) {
+		// arrange
+		handler, _ := newTestLeadHandler(t)
+		req := httptest.NewRequest(http.MethodPatch, "/554499887766", strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should hide internal errors", func(t *testing.T) {
+		// arrange
+		handler, manager := newTestLeadHandler(t)
+		manager.EXPECT().ListLeads(gomock.Any(), gomock.Any()).Return(nil, errors.New("database down"))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+
+		// act
+		handler.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "database down")
+	})
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// WebhookPayload represents the webhook payload
+type WebhookPayload struct {
+	Object string         `json:"object"`
+	Entry  []WebhookEntry `json:"entry"`
+}
+
+// WebhookEntry represents an entry of the webhook payload
+type WebhookEntry struct {
+	ID      string          `json:"id"`
+	Changes []WebhookChange `json:"changes"`
+}
+
+// WebhookChange represents a change notified by the webhook
+type WebhookChange struct {
+	Value WebhookValue `json:"value"`
+	Field string       `json:"field"`
+}
+
+// WebhookValue holds the content of a change
+type WebhookValue struct {
+	MessagingProduct string           `json:"messaging_product"`
+	Metadata         WebhookMetadata  `json:"metadata"`
+	Contacts         []WebhookContact `json:"contacts,omitempty"`
+	Messages         []WebhookMessage `json:"messages,omitempty"`
+	Statuses         []WebhookStatus  `json:"statuses,omitempty"`
+}
+
+// WebhookContact identifies the sender of the messages of a change
+type WebhookContact struct {
+	WaID    string `json:"wa_id"`
+	Profile struct {
+		Name string `json:"name"`
+	} `json:"profile"`
+}
+
+// ProfileName returns the profile name of the sender with the given WhatsApp ID, if informed
+func (v WebhookValue) ProfileName(waID string) string {
+	for _, contact := range v.Contacts {
+		if contact.WaID == waID {
+			return contact.Profile.Name
+		}
+	}
+	return ""
+}
+
+// WebhookMetadata identifies the business phone number that received the change
+type WebhookMetadata struct {
+	PhoneNumberID      string `json:"phone_number_id"`
+	DisplayPhoneNumber string `json:"display_phone_number"`
+}
+
+// WebhookMessage represents a message received from a WhatsApp user
+type WebhookMessage struct {
+	ID          string              `json:"id"`
+	From        string              `json:"from"`
+	Timestamp   string              `json:"timestamp"`
+	Type        string              `json:"type"`
+	Text        WebhookText         `json:"text,omitempty"`
+	Interactive *WebhookInteractive `json:"interactive,omitempty"`
+	Button      *WebhookButton      `json:"button,omitempty"`
+	Image       *WebhookMedia       `json:"image,omitempty"`
+	Document    *WebhookMedia       `json:"document,omitempty"`
+	Audio       *WebhookMedia       `json:"audio,omitempty"`
+	Video       *WebhookMedia       `json:"video,omitempty"`
+	Sticker     *WebhookMedia       `json:"sticker,omitempty"`
+}
+
+// WebhookText holds the body of a text message
+type WebhookText struct {
+	Body string `json:"body"`
+}
+
+// WebhookInteractive holds the reply to an interactive message
+type WebhookInteractive struct {
+	Type        string        `json:"type"`
+	ButtonReply *WebhookReply `json:"button_reply,omitempty"`
+	ListReply   *WebhookReply `json:"list_reply,omitempty"`
+}
+
+// WebhookReply identifies the reply button or list row tapped by the user
+type WebhookReply struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// WebhookButton holds the reply to a quick reply button of a template message
+type WebhookButton struct {
+	Payload string `json:"payload"`
+	Text    string `json:"text"`
+}
+
+// WebhookMedia describes the attachment of a media message
+type WebhookMedia struct {
+	ID       string `json:"id"`
+	MimeType string `json:"mime_type"`
+	SHA256   string `json:"sha256"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Voice    bool   `json:"voice,omitempty"`
+	Animated bool   `json:"animated,omitempty"`
+}
+
+// WebhookStatus represents a delivery status of an outbound message
+type WebhookStatus struct {
+	ID           string               `json:"id"`
+	Status       string               `json:"status"`
+	Timestamp    string               `json:"timestamp"`
+	RecipientID  string               `json:"recipient_id"`
+	Conversation *WebhookConversation `json:"conversation,omitempty"`
+	Pricing      *WebhookPricing      `json:"pricing,omitempty"`
+	Errors       []WebhookError       `json:"errors,omitempty"`
+}
+
+// WebhookConversation describes the conversation window the message was sent in
+type WebhookConversation struct {
+	ID                  string `json:"id"`
+	ExpirationTimestamp string `json:"expiration_timestamp,omitempty"`
+	Origin              struct {
+		Type string `json:"type"`
+	} `json:"origin"`
+}
+
+// WebhookPricing describes how the message is billed
+type WebhookPricing struct {
+	Billable     bool   `json:"billable"`
+	PricingModel string `json:"pricing_model"`
+	Category     string `json:"category"`
+}
+
+// WebhookError describes why a message failed
+type WebhookError struct {
+	Code      int    `json:"code"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	ErrorData struct {
+		Details string `json:"details"`
+	} `json:"error_data"`
+}
+
+// ToStatusEvent converts the webhook status to the domain representation
+func (s WebhookStatus) ToStatusEvent() domain.StatusEvent {
+	event := domain.StatusEvent{
+		MessageID:   s.ID,
+		RecipientID: s.RecipientID,
+		Status:      domain.DeliveryStatus(s.Status),
+		Timestamp:   parseTimestamp(s.Timestamp),
+	}
+	if s.Conversation != nil {
+		event.ConversationID = s.Conversation.ID
+		event.ConversationOrigin = s.Conversation.Origin.Type
+		if s.Conversation.ExpirationTimestamp != "" {
+			event.ConversationExpires = parseTimestamp(s.Conversation.ExpirationTimestamp)
+		}
+	}
+	if s.Pricing != nil {
+		event.Billable = s.Pricing.Billable
+		event.PricingModel = s.Pricing.PricingModel
+		event.PricingCategory = s.Pricing.Category
+	}
+	for _, e := range s.Errors {
+		event.Errors = append(event.Errors, domain.StatusError{
+			Code:    e.Code,
+			Title:   e.Title,
+			Message: e.Message,
+			Details: e.ErrorData.Details,
+		})
+	}
+	return event
+}
+
+// ToInboundMessage converts the webhook message to the domain representation.
+// It returns false for message types the bot does not handle.
+func (m WebhookMessage) ToInboundMessage() (domain.InboundMessage, bool) {
+	msg := domain.InboundMessage{
+		ID:        m.ID,
+		From:      m.From,
+		Timestamp: parseTimestamp(m.Timestamp),
+	}
+
+	switch m.Type {
+	case "text":
+		msg.Type = domain.MessageTypeText
+		msg.Text = m.Text.Body
+	case "interactive":
+		reply := m.Interactive.reply()
+		if reply == nil {
+			return msg, false
+		}
+		msg.Type = domain.MessageTypeInteractive
+		msg.ReplyID = reply.ID
+		msg.ReplyTitle = reply.Title
+		msg.Text = reply.Title
+	case "button":
+		if m.Button == nil {
+			return msg, false
+		}
+		msg.Type = domain.MessageTypeButton
+		msg.ReplyID = m.Button.Payload
+		msg.ReplyTitle = m.Button.Text
+		msg.Text = m.Button.Text
+	case "image", "document", "audio", "video", "sticker":
+		media := m.media()
+		if media == nil || media.ID == "" {
+			return msg, false
+		}
+		msg.Type = domain.MessageType(m.Type)
+		msg.Text = media.Caption
+		msg.Media = &domain.Media{
+			ID:       media.ID,
+			MimeType: media.MimeType,
+			SHA256:   media.SHA256,
+			Caption:  media.Caption,
+			Filename: media.Filename,
+		}
+	default:
+		return msg, false
+	}
+
+	return msg, true
+}
+
+// media returns the attachment matching the message type
+func (m WebhookMessage) media() *WebhookMedia {
+	switch m.Type {
+	case "image":
+		return m.Image
+	case "document":
+		return m.Document
+	case "audio":
+		return m.Audio
+	case "video":
+		return m.Video
+	case "sticker":
+		return m.Sticker
+	default:
+		return nil
+	}
+}
+
+// reply returns the button or list reply, whichever is present
+func (i *WebhookInteractive) reply() *WebhookReply {
+	if i == nil {
+		return nil
+	}
+	switch i.Type {
+	case "button_reply":
+		return i.ButtonReply
+	case "list_reply":
+		return i.ListReply
+	default:
+		return nil
+	}
+}
+
+// parseTimestamp converts the unix timestamp sent by WhatsApp, falling back to the current time
+func parseTimestamp(value string) time.Time {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.Unix(seconds, 0)
+}
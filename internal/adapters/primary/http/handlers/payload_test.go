@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookMessageToInboundMessage(t *testing.T) {
+	t.Run("should convert a text message", func(t *testing.T) {
+		// arrange
+		var message WebhookMessage
+		json.Unmarshal([]byte(`{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"text","text":{"body":"Oi"}}`), &message)
+
+		// act
+		msg, ok := message.ToInboundMessage()
+
+		// assert
+		assert.True(t, ok)
+		assert.Equal(t, domain.MessageTypeText, msg.Type)
+		assert.Equal(t, "Oi", msg.Text)
+		assert.Empty(t, msg.ReplyID)
+		assert.Equal(t, int64(1617356451), msg.Timestamp.Unix())
+	})
+
+	t.Run("should convert a button reply", func(t *testing.T) {
+		// arrange
+		var message WebhookMessage
+		json.Unmarshal([]byte(`{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"interactive",
+			"interactive":{"type":"button_reply","button_reply":{"id":"crm_yes","title":"Já tenho CRM"}}}`), &message)
+
+		// act
+		msg, ok := message.ToInboundMessage()
+
+		// assert
+		assert.True(t, ok)
+		assert.Equal(t, domain.MessageTypeInteractive, msg.Type)
+		assert.Equal(t, "crm_yes", msg.ReplyID)
+		assert.Equal(t, "Já tenho CRM", msg.ReplyTitle)
+		assert.Equal(t, "Já tenho CRM", msg.Text)
+	})
+
+	t.Run("should convert a list reply", func(t *testing.T) {
+		// arrange
+		var message WebhookMessage
+		json.Unmarshal([]byte(`{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"interactive",
+			"interactive":{"type":"list_reply","list_reply":{"id":"3","title":"Tirar dúvidas","description":"Impostos, abertura"}}}`), &message)
+
+		// act
+		msg, ok := message.ToInboundMessage()
+
+		// assert
+		assert.True(t, ok)
+		assert.Equal(t, domain.MessageTypeInteractive, msg.Type)
+		assert.Equal(t, "3", msg.ReplyID)
+		assert.Equal(t, "Tirar dúvidas", msg.ReplyTitle)
+	})
+
+	t.Run("should convert a legacy template button reply", func(t *testing.T) {
+		// arrange
+		var message WebhookMessage
+		json.Unmarshal([]byte(`{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"button",
+			"button":{"payload":"menu","text":"Ver opções"}}`), &message)
+
+		// act
+		msg, ok := message.ToInboundMessage()
+
+		// assert
+		assert.True(t, ok)
+		assert.Equal(t, domain.MessageTypeButton, msg.Type)
+		assert.Equal(t, "menu", msg.ReplyID)
+		assert.Equal(t, "Ver opções", msg.ReplyTitle)
+	})
+
+	t.Run("should convert a document message", func(t *testing.T) {
+		// arrange
+		var message WebhookMessage
+		json.Unmarshal([]byte(`{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"document",
+			"document":{"id":"media.1","mime_type":"application/pdf","sha256":"abc=","caption":"Meu CRM","filename":"crm.pdf"}}`), &message)
+
+		// act
+		msg, ok := message.ToInboundMessage()
+
+		// assert
+		assert.True(t, ok)
+		assert.Equal(t, domain.MessageTypeDocument, msg.Type)
+		assert.Equal(t, "Meu CRM", msg.Text)
+		assert.Equal(t, &domain.Media{
+			ID:       "media.1",
+			MimeType: "application/pdf",
+			SHA256:   "abc=",
+			Caption:  "Meu CRM",
+			Filename: "crm.pdf",
+		}, msg.Media)
+	})
+
+	t.Run("should convert image, audio, video and sticker messages", func(t *testing.T) {
+		for _, messageType := range []string{"image", "audio", "video", "sticker"} {
+			// arrange
+			var message WebhookMessage
+			json.Unmarshal([]byte(`{"id":"wamid.1","from":"554491234567","type":"`+messageType+`",
+				"`+messageType+`":{"id":"media.1","mime_type":"application/octet-stream","sha256":"abc="}}`), &message)
+
+			// act
+			msg, ok := message.ToInboundMessage()
+
+			// assert
+			assert.True(t, ok, messageType)
+			assert.Equal(t, domain.MessageType(messageType), msg.Type)
+			assert.Equal(t, "media.1", msg.Media.ID)
+		}
+	})
+
+	t.Run("should reject media messages without media", func(t *testing.T) {
+		// arrange
+		message := WebhookMessage{ID: "wamid.1", From: "554491234567", Type: "image"}
+
+		// act
+		_, ok := message.ToInboundMessage()
+
+		// assert
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject interactive messages without a reply", func(t *testing.T) {
+		// arrange
+		var message WebhookMessage
+		json.Unmarshal([]byte(`{"id":"wamid.1","from":"554491234567","type":"interactive","interactive":{"type":"nfm_reply"}}`), &message)
+
+		// act
+		_, ok := message.ToInboundMessage()
+
+		// assert
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject unsupported message types", func(t *testing.T) {
+		// arrange
+		message := WebhookMessage{ID: "wamid.1", From: "554491234567", Type: "location"}
+
+		// act
+		_, ok := message.ToInboundMessage()
+
+		// assert
+		assert.False(t, ok)
+	})
+}
+
+func TestWebhookValueProfileName(t *testing.T) {
+	// arrange
+	var value WebhookValue
+	json.Unmarshal([]byte(`{"contacts":[{"profile":{"name":"Dra. Ana"},"wa_id":"554491234567"}]}`), &value)
+
+	// act & assert
+	assert.Equal(t, "Dra. Ana", value.ProfileName("554491234567"))
+	assert.Equal(t, "", value.ProfileName("554499999999"))
+}
+
+func TestWebhookStatusToStatusEvent(t *testing.T) {
+	t.Run("should convert a delivered status with conversation and pricing", func(t *testing.T) {
+		// arrange
+		var status WebhookStatus
+		json.Unmarshal([]byte(`{"id":"wamid.out","status":"delivered","timestamp":"1617356460","recipient_id":"554491234567",
+			"conversation":{"id":"conv.1","expiration_timestamp":"1617442860","origin":{"type":"service"}},
+			"pricing":{"billable":true,"pricing_model":"CBP","category":"service"}}`), &status)
+
+		// act
+		event := status.ToStatusEvent()
+
+		// assert
+		assert.Equal(t, "wamid.out", event.MessageID)
+		assert.Equal(t, "554491234567", event.RecipientID)
+		assert.Equal(t, domain.StatusDelivered, event.Status)
+		assert.Equal(t, time.Unix(1617356460, 0), event.Timestamp)
+		assert.Equal(t, "conv.1", event.ConversationID)
+		assert.Equal(t, "service", event.ConversationOrigin)
+		assert.Equal(t, time.Unix(1617442860, 0), event.ConversationExpires)
+		assert.True(t, event.Billable)
+		assert.Equal(t, "CBP", event.PricingModel)
+		assert.Equal(t, "service", event.PricingCategory)
+		assert.Empty(t, event.Errors)
+	})
+
+	t.Run("should convert a failed status with errors", func(t *testing.T) {
+		// arrange
+		var status WebhookStatus
+		json.Unmarshal([]byte(`{"id":"wamid.out","status":"failed","timestamp":"1617356460","recipient_id":"554491234567",
+			"errors":[{"code":131047,"title":"Re-engagement message","message":"Re-engagement message",
+			"error_data":{"details":"Message failed to send because more than 24 hours have passed"}}]}`), &status)
+
+		// act
+		event := status.ToStatusEvent()
+
+		// assert
+		assert.Equal(t, domain.StatusFailed, event.Status)
+		assert.Equal(t, []domain.StatusError{{
+			Code:    131047,
+			Title:   "Re-engagement message",
+			Message: "Re-engagement message",
+			Details: "Message failed to send because more than 24 hours have passed",
+		}}, event.Errors)
+	})
+}
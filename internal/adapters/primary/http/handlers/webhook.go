@@ -1,29 +1,37 @@
 package handlers
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
 
 	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
 	"github.com/2rprbm/conta-med-backend/pkg/logger"
 )
 
 // WebhookHandler handles WhatsApp webhook requests
 type WebhookHandler struct {
-	config *config.Config
-	logger logger.Logger
+	config    *config.Config
+	logger    logger.Logger
+	processor ports.MessageProcessor
+	statuses  ports.StatusProcessor
 }
 
 // NewWebhookHandler creates a new webhook handler
-func NewWebhookHandler(cfg *config.Config, log logger.Logger) *WebhookHandler {
+func NewWebhookHandler(cfg *config.Config, log logger.Logger, processor ports.MessageProcessor, statuses ports.StatusProcessor) *WebhookHandler {
 	return &WebhookHandler{
-		config: cfg,
-		logger: log,
+		config:    cfg,
+		logger:    log,
+		processor: processor,
+		statuses:  statuses,
 	}
 }
 
@@ -49,33 +57,6 @@ func (h *WebhookHandler) VerifyToken(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "Verification failed", http.StatusForbidden)
 }
 
-// WebhookPayload represents the webhook payload
-type WebhookPayload struct {
-	Object string `json:"object"`
-	Entry  []struct {
-		ID      string `json:"id"`
-		Changes []struct {
-			Value struct {
-				MessagingProduct string `json:"messaging_product"`
-				Metadata         struct {
-					PhoneNumberID      string `json:"phone_number_id"`
-					DisplayPhoneNumber string `json:"display_phone_number"`
-				} `json:"metadata"`
-				Messages []struct {
-					ID        string `json:"id"`
-					From      string `json:"from"`
-					Timestamp string `json:"timestamp"`
-					Type      string `json:"type"`
-					Text      struct {
-						Body string `json:"body"`
-					} `json:"text,omitempty"`
-				} `json:"messages,omitempty"`
-			} `json:"value"`
-			Field string `json:"field"`
-		} `json:"changes"`
-	} `json:"entry"`
-}
-
 // ReceiveWebhook handles POST requests from WhatsApp
 func (h *WebhookHandler) ReceiveWebhook(w http.ResponseWriter, r *http.Request) {
 	// Verify signature for security
@@ -108,24 +89,63 @@ func (h *WebhookHandler) ReceiveWebhook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Process messages - temporary just logging them
+	// Process messages
+	overloaded := false
 	for _, entry := range payload.Entry {
 		for _, change := range entry.Changes {
 			if change.Field == "messages" {
 				for _, message := range change.Value.Messages {
-					if message.Type == "text" {
-						h.logger.Info("Received message from %s: %s", message.From, message.Text.Body)
-						// TODO: Process message and send response via WhatsApp API
+					msg, ok := message.ToInboundMessage()
+					if !ok {
+						h.logger.Debug("Ignoring unsupported message type %s from %s", message.Type, message.From)
+						continue
 					}
+					msg.ProfileName = change.Value.ProfileName(message.From)
+					h.logger.Info("Received message from %s: %s", msg.From, msg.Text)
+					if !h.process(r.Context(), msg) {
+						overloaded = true
+					}
+				}
+				for _, status := range change.Value.Statuses {
+					h.processStatus(r.Context(), status.ToStatusEvent())
 				}
 			}
 		}
 	}
 
+	// Ask WhatsApp to redeliver the webhook when messages could not be accepted.
+	// The messages already accepted are delivered again as well.
+	if overloaded {
+		http.Error(w, "Too many messages, try again later", http.StatusServiceUnavailable)
+		return
+	}
+
 	// Return 200 OK to acknowledge receipt
 	w.WriteHeader(http.StatusOK)
 }
 
+// process hands the message over to the processor, returning false when it could not be
+// accepted because the processor is overloaded. Other errors are only logged, since
+// answering with an error status would make WhatsApp redeliver the whole webhook.
+func (h *WebhookHandler) process(ctx context.Context, msg domain.InboundMessage) bool {
+	err := h.processor.ProcessMessage(ctx, msg)
+	if errors.Is(err, domain.ErrOverloaded) {
+		h.logger.Warn("Message %s from %s not accepted: %v", msg.ID, msg.From, err)
+		return false
+	}
+	if err != nil {
+		h.logger.Error("Error processing message %s from %s: %v", msg.ID, msg.From, err)
+	}
+	return true
+}
+
+// processStatus hands the delivery status over to the status processor, logging errors
+func (h *WebhookHandler) processStatus(ctx context.Context, event domain.StatusEvent) {
+	if err := h.statuses.ProcessStatus(ctx, event); err != nil {
+		h.logger.Error("Error processing status %s of message %s: %v", event.Status, event.MessageID, err)
+	}
+}
+
 // verifySignature verifies the request signature
 func (h *WebhookHandler) verifySignature(r *http.Request) bool {
 	// In development mode, skip signature verification if app secret is not set
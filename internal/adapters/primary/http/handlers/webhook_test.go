@@ -2,17 +2,23 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
 // mockLogger implements the logger.Logger interface for testing
@@ -47,6 +53,12 @@ func (m *mockLogger) Fatal(format string, args ...interface{}) {
 	m.logger.Printf(format, args...)
 }
 
+// newTestProcessors returns mocked processors without expectations
+func newTestProcessors(t *testing.T) (*mocks.MockMessageProcessor, *mocks.MockStatusProcessor) {
+	ctrl := gomock.NewController(t)
+	return mocks.NewMockMessageProcessor(ctrl), mocks.NewMockStatusProcessor(ctrl)
+}
+
 func TestVerifyToken(t *testing.T) {
 	t.Run("should verify token successfully when token matches", func(t *testing.T) {
 		// arrange
@@ -57,7 +69,8 @@ func TestVerifyToken(t *testing.T) {
 			},
 		}
 
-		handler := NewWebhookHandler(cfg, logger)
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
 
 		// Create request with query parameters
 		req := httptest.NewRequest("GET", "/webhook/whatsapp?hub.mode=subscribe&hub.verify_token=test_token&hub.challenge=challenge_value", nil)
@@ -80,7 +93,8 @@ func TestVerifyToken(t *testing.T) {
 			},
 		}
 
-		handler := NewWebhookHandler(cfg, logger)
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
 
 		// Create request with incorrect token
 		req := httptest.NewRequest("GET", "/webhook/whatsapp?hub.mode=subscribe&hub.verify_token=wrong_token&hub.challenge=challenge_value", nil)
@@ -102,7 +116,8 @@ func TestVerifyToken(t *testing.T) {
 			},
 		}
 
-		handler := NewWebhookHandler(cfg, logger)
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
 
 		// Create request with incorrect mode
 		req := httptest.NewRequest("GET", "/webhook/whatsapp?hub.mode=wrong_mode&hub.verify_token=test_token&hub.challenge=challenge_value", nil)
@@ -129,97 +144,36 @@ func TestReceiveWebhook(t *testing.T) {
 			},
 		}
 
-		handler := NewWebhookHandler(cfg, logger)
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
+		var processed domain.InboundMessage
+		processor.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, msg domain.InboundMessage) error {
+			processed = msg
+			return nil
+		})
 
 		// Create webhook payload
 		payload := WebhookPayload{
 			Object: "whatsapp_business_account",
-			Entry: []struct {
-				ID      string `json:"id"`
-				Changes []struct {
-					Value struct {
-						MessagingProduct string `json:"messaging_product"`
-						Metadata         struct {
-							PhoneNumberID      string `json:"phone_number_id"`
-							DisplayPhoneNumber string `json:"display_phone_number"`
-						} `json:"metadata"`
-						Messages []struct {
-							ID        string `json:"id"`
-							From      string `json:"from"`
-							Timestamp string `json:"timestamp"`
-							Type      string `json:"type"`
-							Text      struct {
-								Body string `json:"body"`
-							} `json:"text,omitempty"`
-						} `json:"messages,omitempty"`
-					} `json:"value"`
-					Field string `json:"field"`
-				} `json:"changes"`
-			}{
+			Entry: []WebhookEntry{
 				{
 					ID: "123456789",
-					Changes: []struct {
-						Value struct {
-							MessagingProduct string `json:"messaging_product"`
-							Metadata         struct {
-								PhoneNumberID      string `json:"phone_number_id"`
-								DisplayPhoneNumber string `json:"display_phone_number"`
-							} `json:"metadata"`
-							Messages []struct {
-								ID        string `json:"id"`
-								From      string `json:"from"`
-								Timestamp string `json:"timestamp"`
-								Type      string `json:"type"`
-								Text      struct {
-									Body string `json:"body"`
-								} `json:"text,omitempty"`
-							} `json:"messages,omitempty"`
-						} `json:"value"`
-						Field string `json:"field"`
-					}{
+					Changes: []WebhookChange{
 						{
 							Field: "messages",
-							Value: struct {
-								MessagingProduct string `json:"messaging_product"`
-								Metadata         struct {
-									PhoneNumberID      string `json:"phone_number_id"`
-									DisplayPhoneNumber string `json:"display_phone_number"`
-								} `json:"metadata"`
-								Messages []struct {
-									ID        string `json:"id"`
-									From      string `json:"from"`
-									Timestamp string `json:"timestamp"`
-									Type      string `json:"type"`
-									Text      struct {
-										Body string `json:"body"`
-									} `json:"text,omitempty"`
-								} `json:"messages,omitempty"`
-							}{
+							Value: WebhookValue{
 								MessagingProduct: "whatsapp",
-								Metadata: struct {
-									PhoneNumberID      string `json:"phone_number_id"`
-									DisplayPhoneNumber string `json:"display_phone_number"`
-								}{
+								Metadata: WebhookMetadata{
 									PhoneNumberID:      "123456789",
 									DisplayPhoneNumber: "+1234567890",
 								},
-								Messages: []struct {
-									ID        string `json:"id"`
-									From      string `json:"from"`
-									Timestamp string `json:"timestamp"`
-									Type      string `json:"type"`
-									Text      struct {
-										Body string `json:"body"`
-									} `json:"text,omitempty"`
-								}{
+								Messages: []WebhookMessage{
 									{
 										ID:        "wamid.123456789",
 										From:      "554491234567",
 										Timestamp: "1617356451",
 										Type:      "text",
-										Text: struct {
-											Body string `json:"body"`
-										}{
+										Text: WebhookText{
 											Body: "Hello, world!",
 										},
 									},
@@ -252,6 +206,129 @@ func TestReceiveWebhook(t *testing.T) {
 		// assert
 		assert.Equal(t, http.StatusOK, recorder.Code)
 		assert.Contains(t, logger.buffer.String(), "Received message from 554491234567: Hello, world!")
+		assert.Equal(t, "wamid.123456789", processed.ID)
+		assert.Equal(t, "554491234567", processed.From)
+		assert.Equal(t, domain.MessageTypeText, processed.Type)
+		assert.Equal(t, "Hello, world!", processed.Text)
+		assert.Equal(t, int64(1617356451), processed.Timestamp.Unix())
+	})
+
+	t.Run("should acknowledge webhook even when processing fails", func(t *testing.T) {
+		// arrange
+		logger := newMockLogger()
+		cfg := &config.Config{
+			Server: config.ServerConfig{
+				Environment: "development",
+			},
+		}
+
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
+		processor.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, msg domain.InboundMessage) error {
+			assert.Equal(t, "Dra. Ana", msg.ProfileName)
+			return errors.New("send failed")
+		})
+
+		payload := `{"object":"whatsapp_business_account","entry":[{"id":"1","changes":[{"field":"messages","value":{"contacts":[{"profile":{"name":"Dra. Ana"},"wa_id":"554491234567"}],"messages":[{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"text","text":{"body":"Oi"}}]}}]}]}`
+		req := httptest.NewRequest("POST", "/webhook/whatsapp", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+
+		// act
+		handler.ReceiveWebhook(recorder, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, logger.buffer.String(), "Error processing message wamid.1 from 554491234567: send failed")
+	})
+
+	t.Run("should ask for redelivery when the processor is overloaded", func(t *testing.T) {
+		// arrange
+		logger := newMockLogger()
+		cfg := &config.Config{
+			Server: config.ServerConfig{
+				Environment: "development",
+			},
+		}
+
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
+		processor.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).Return(fmt.Errorf("queue is full: %w", domain.ErrOverloaded))
+
+		payload := `{"object":"whatsapp_business_account","entry":[{"id":"1","changes":[{"field":"messages","value":{"messages":[{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"text","text":{"body":"Oi"}}]}}]}]}`
+		req := httptest.NewRequest("POST", "/webhook/whatsapp", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+
+		// act
+		handler.ReceiveWebhook(recorder, req)
+
+		// assert
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Contains(t, logger.buffer.String(), "Message wamid.1 from 554491234567 not accepted")
+	})
+
+	t.Run("should dispatch interactive replies and skip unsupported messages", func(t *testing.T) {
+		// arrange
+		logger := newMockLogger()
+		cfg := &config.Config{
+			Server: config.ServerConfig{
+				Environment: "development",
+			},
+		}
+
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
+		processor.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, msg domain.InboundMessage) error {
+			assert.Equal(t, "2", msg.ReplyID)
+			return nil
+		})
+
+		payload := `{"object":"whatsapp_business_account","entry":[{"id":"1","changes":[{"field":"messages","value":{"messages":[` +
+			`{"id":"wamid.1","from":"554491234567","timestamp":"1617356451","type":"interactive","interactive":{"type":"button_reply","button_reply":{"id":"2","title":"Abrir empresa"}}},` +
+			`{"id":"wamid.2","from":"554491234567","timestamp":"1617356452","type":"reaction"}` +
+			`]}}]}]}`
+		req := httptest.NewRequest("POST", "/webhook/whatsapp", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+
+		// act
+		handler.ReceiveWebhook(recorder, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, logger.buffer.String(), "Ignoring unsupported message type reaction")
+	})
+
+	t.Run("should dispatch message statuses", func(t *testing.T) {
+		// arrange
+		logger := newMockLogger()
+		cfg := &config.Config{
+			Server: config.ServerConfig{
+				Environment: "development",
+			},
+		}
+
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
+		statuses.EXPECT().ProcessStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, event domain.StatusEvent) error {
+			assert.Equal(t, "wamid.out", event.MessageID)
+			assert.Equal(t, domain.StatusDelivered, event.Status)
+			return nil
+		})
+
+		payload := `{"object":"whatsapp_business_account","entry":[{"id":"1","changes":[{"field":"messages","value":{"statuses":[` +
+			`{"id":"wamid.out","status":"delivered","timestamp":"1617356460","recipient_id":"554491234567"}` +
+			`]}}]}]}`
+		req := httptest.NewRequest("POST", "/webhook/whatsapp", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+
+		// act
+		handler.ReceiveWebhook(recorder, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, recorder.Code)
 	})
 
 	t.Run("should reject webhook with invalid signature", func(t *testing.T) {
@@ -266,7 +343,8 @@ func TestReceiveWebhook(t *testing.T) {
 			},
 		}
 
-		handler := NewWebhookHandler(cfg, logger)
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
 
 		// Simple payload
 		payload := `{"object":"whatsapp_business_account"}`
@@ -299,7 +377,8 @@ func TestReceiveWebhook(t *testing.T) {
 			},
 		}
 
-		handler := NewWebhookHandler(cfg, logger)
+		processor, statuses := newTestProcessors(t)
+		handler := NewWebhookHandler(cfg, logger, processor, statuses)
 
 		// Payload with wrong object type
 		payload := `{"object":"instagram"}`
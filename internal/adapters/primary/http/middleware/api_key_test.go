@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("should let through requests with the key", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodGet, "/api/leads", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+
+		// act
+		APIKey("secret")(next).ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should refuse requests with a missing or wrong key", func(t *testing.T) {
+		for _, header := range []string{"", "Bearer wrong", "secret", "Basic secret"} {
+			// arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/leads", nil)
+			req.Header.Set("Authorization", header)
+			rec := httptest.NewRecorder()
+
+			// act
+			APIKey("secret")(next).ServeHTTP(rec, req)
+
+			// assert
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, header)
+			assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+		}
+	})
+
+	t.Run("should refuse every request when no key is configured", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodGet, "/api/leads", nil)
+		req.Header.Set("Authorization", "Bearer ")
+		rec := httptest.NewRecorder()
+
+		// act
+		APIKey("")(next).ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -2,12 +2,16 @@ package http
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/2rprbm/conta-med-backend/config"
 	"github.com/2rprbm/conta-med-backend/internal/adapters/primary/http/handlers"
 	"github.com/2rprbm/conta-med-backend/internal/adapters/primary/http/middleware"
+	"github.com/2rprbm/conta-med-backend/internal/application/worker"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
 	"github.com/2rprbm/conta-med-backend/pkg/logger"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
@@ -15,15 +19,24 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	server *http.Server
-	router *chi.Mux
-	logger logger.Logger
-	config *config.Config
+	server    *http.Server
+	router    *chi.Mux
+	api       chi.Router
+	logger    logger.Logger
+	config    *config.Config
+	processor ports.MessageProcessor
+	statuses  ports.StatusProcessor
+	pool      *worker.Pool
+	// cancel cancels the base context of all requests
+	cancel context.CancelFunc
 }
 
-// NewServer creates a new HTTP server
-func NewServer(cfg *config.Config, log logger.Logger) *Server {
+// NewServer creates a new HTTP server. Inbound messages are handed over to the processor,
+// which enqueues them on the worker pool so webhooks are acknowledged without waiting for
+// them. The pool is drained when the server shuts down.
+func NewServer(cfg *config.Config, log logger.Logger, processor ports.MessageProcessor, statuses ports.StatusProcessor, pool *worker.Pool) *Server {
 	r := chi.NewRouter()
+	baseCtx, cancel := context.WithCancel(context.Background())
 
 	srv := &Server{
 		server: &http.Server{
@@ -32,10 +45,15 @@ func NewServer(cfg *config.Config, log logger.Logger) *Server {
 			ReadTimeout:  cfg.Server.ReadTimeout,
 			WriteTimeout: cfg.Server.WriteTimeout,
 			IdleTimeout:  cfg.Server.IdleTimeout,
+			BaseContext:  func(net.Listener) context.Context { return baseCtx },
 		},
-		router: r,
-		logger: log,
-		config: cfg,
+		router:    r,
+		logger:    log,
+		config:    cfg,
+		processor: processor,
+		statuses:  statuses,
+		pool:      pool,
+		cancel:    cancel,
 	}
 
 	srv.setupMiddleware()
@@ -65,7 +83,7 @@ func (s *Server) setupRoutes() {
 	})
 
 	// Webhook handler
-	webhookHandler := handlers.NewWebhookHandler(s.config, s.logger)
+	webhookHandler := handlers.NewWebhookHandler(s.config, s.logger, s.processor, s.statuses)
 
 	// WhatsApp webhook routes
 	s.router.Route("/webhook", func(r chi.Router) {
@@ -75,6 +93,21 @@ func (s *Server) setupRoutes() {
 		// POST for receiving messages
 		r.Post("/whatsapp", webhookHandler.ReceiveWebhook)
 	})
+
+	// Internal API routes, mounted with MountAPI
+	if s.config.Server.APIKey == "" {
+		s.logger.Warn("API key not configured, the internal API refuses every request")
+	}
+	s.router.Route("/api", func(r chi.Router) {
+		r.Use(middleware.APIKey(s.config.Server.APIKey))
+		s.api = r
+	})
+}
+
+// MountAPI serves the handler under /api/<pattern> for requests authenticated with the API key.
+// It must be called before Start.
+func (s *Server) MountAPI(pattern string, handler http.Handler) {
+	s.api.Mount(pattern, handler)
 }
 
 // Start starts the HTTP server
@@ -87,8 +120,18 @@ func (s *Server) Start() {
 	}()
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server, then waits for the queued messages to be processed.
+// The pool is shut down even when the server fails to, and both errors are returned.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down server...")
-	return s.server.Shutdown(ctx)
+	// Requests still running when the shutdown period ends are cancelled,
+	// aborting their outbound calls to the WhatsApp API
+	defer s.cancel()
+	serverErr := s.server.Shutdown(ctx)
+	if serverErr != nil {
+		s.logger.Warn("Server did not shut down gracefully: %v", serverErr)
+	}
+
+	s.logger.Info("Waiting for queued messages...")
+	return errors.Join(serverErr, s.pool.Shutdown(ctx))
 }
@@ -0,0 +1,35 @@
+// Package cfm checks medical registrations with the federal council of medicine (CFM)
+package cfm
+
+import (
+	"context"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// StubVerifier is a stand-in for the CFM registration lookup, used until the lookup is integrated.
+// It confirms the registrations it was created with and reports every other one as unverified,
+// never as not found, so that no doctor is turned away.
+type StubVerifier struct {
+	known map[domain.CRM]bool
+}
+
+var _ ports.CRMVerifier = (*StubVerifier)(nil)
+
+// NewStubVerifier creates a stand-in verifier confirming the given registrations
+func NewStubVerifier(known ...domain.CRM) *StubVerifier {
+	v := &StubVerifier{known: make(map[domain.CRM]bool)}
+	for _, crm := range known {
+		v.known[crm] = true
+	}
+	return v
+}
+
+// VerifyCRM reports the known registrations as verified and the others as unverified
+func (v *StubVerifier) VerifyCRM(ctx context.Context, crm domain.CRM) (domain.CRMVerification, error) {
+	if v.known[crm] {
+		return domain.CRMVerified, nil
+	}
+	return domain.CRMUnverified, nil
+}
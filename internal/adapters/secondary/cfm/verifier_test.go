@@ -0,0 +1,31 @@
+package cfm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStubVerifier(t *testing.T) {
+	verifier := NewStubVerifier(domain.CRM{Number: "123456", State: "SP"})
+
+	t.Run("should confirm the known registrations", func(t *testing.T) {
+		// act
+		result, err := verifier.VerifyCRM(context.Background(), domain.CRM{Number: "123456", State: "SP"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CRMVerified, result)
+	})
+
+	t.Run("should report other registrations as unverified", func(t *testing.T) {
+		// act
+		result, err := verifier.VerifyCRM(context.Background(), domain.CRM{Number: "123456", State: "RJ"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CRMUnverified, result)
+	})
+}
@@ -0,0 +1,77 @@
+// Package faq reads the questions and answers of the knowledge base from its versioned file.
+// The file seeds an empty knowledge base, which is then edited through the API.
+package faq
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// entries holds the knowledge base written by the accountants
+//
+//go:embed data/faq.json
+var entries []byte
+
+// Entries returns the entries embedded in the binary
+func Entries() ([]domain.FAQEntry, error) {
+	return Read(bytes.NewReader(entries))
+}
+
+// LoadFile reads the entries of the file at path, in the format described by Read
+func LoadFile(path string) ([]domain.FAQEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening FAQ file: %w", err)
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+// entryRecord is an entry of the FAQ file
+type entryRecord struct {
+	ID       string   `json:"id"`
+	Category string   `json:"category"`
+	Question string   `json:"question"`
+	Answer   string   `json:"answer"`
+	Keywords []string `json:"keywords"`
+}
+
+// Read reads the entries of a JSON array whose items have the fields id, category, question,
+// answer and keywords. The answers may use the WhatsApp formatting, like *bold*.
+func Read(r io.Reader) ([]domain.FAQEntry, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var records []entryRecord
+	if err := decoder.Decode(&records); err != nil {
+		return nil, fmt.Errorf("error reading FAQ entries: %w", err)
+	}
+
+	seen := make(map[string]bool, len(records))
+	result := make([]domain.FAQEntry, len(records))
+	for i, record := range records {
+		entry := domain.FAQEntry{
+			ID:       record.ID,
+			Category: record.Category,
+			Question: record.Question,
+			Answer:   record.Answer,
+			Keywords: record.Keywords,
+		}
+		if err := entry.Validate(); err != nil {
+			return nil, fmt.Errorf("error reading FAQ entry %d: %w", i+1, err)
+		}
+		if seen[entry.ID] {
+			return nil, fmt.Errorf("error reading FAQ entry %d: duplicate id %q", i+1, entry.ID)
+		}
+		seen[entry.ID] = true
+		result[i] = entry
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package faq
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntries(t *testing.T) {
+	t.Run("should read the embedded knowledge base", func(t *testing.T) {
+		// act
+		entries, err := Entries()
+
+		// assert
+		assert.NoError(t, err)
+		categories := make(map[string]bool)
+		for _, entry := range entries {
+			categories[entry.Category] = true
+		}
+		for _, category := range []string{"regimes-tributarios", "simples-nacional", "pro-labore", "irpf"} {
+			assert.True(t, categories[category], category)
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("should read the entries with their keywords", func(t *testing.T) {
+		// arrange
+		file := `[{"id": "fator-r", "category": "simples-nacional", "question": "O que é o Fator R?",
+			"answer": "É a razão entre a folha e o faturamento.", "keywords": ["folha", "anexo"]}]`
+
+		// act
+		entries, err := Read(strings.NewReader(file))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.FAQEntry{{
+			ID:       "fator-r",
+			Category: "simples-nacional",
+			Question: "O que é o Fator R?",
+			Answer:   "É a razão entre a folha e o faturamento.",
+			Keywords: []string{"folha", "anexo"},
+		}}, entries)
+	})
+
+	t.Run("should reject invalid files", func(t *testing.T) {
+		files := map[string]string{
+			"not json":      `{`,
+			"not an array":  `{"id": "fator-r"}`,
+			"unknown field": `[{"id": "fator-r", "question": "?", "answer": "!", "tags": []}]`,
+			"no answer":     `[{"id": "fator-r", "question": "O que é o Fator R?"}]`,
+			"invalid id":    `[{"id": "Fator R", "question": "?", "answer": "!"}]`,
+			"duplicate id":  `[{"id": "fator-r", "question": "?", "answer": "!"}, {"id": "fator-r", "question": "?", "answer": "!"}]`,
+		}
+		for name, file := range files {
+			// act
+			entries, err := Read(strings.NewReader(file))
+
+			// assert
+			assert.Error(t, err, name)
+			assert.Nil(t, entries, name)
+		}
+	})
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Run("should read the entries of the file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "faq.json")
+		os.WriteFile(path, []byte(`[{"id": "mei", "question": "Médico pode ser MEI?", "answer": "Não."}]`), 0o644)
+
+		// act
+		entries, err := LoadFile(path)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "mei", entries[0].ID)
+	})
+
+	t.Run("should fail when the file does not exist", func(t *testing.T) {
+		// act
+		_, err := LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+
+		// assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+// Package holidays reads the state and municipal holidays observed by the business.
+// The national holidays are known by the domain calendar.
+package holidays
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// stateHolidays holds the state holidays set by the state laws
+//
+//go:embed data/feriados.csv
+var stateHolidays []byte
+
+// StateHolidays returns the state holidays embedded in the binary
+func StateHolidays() ([]domain.Holiday, error) {
+	return Read(bytes.NewReader(stateHolidays))
+}
+
+// LoadFile reads the holidays of the file at path, in the format described by Read
+func LoadFile(path string) ([]domain.Holiday, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening holidays file: %w", err)
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+// Read reads holidays from a semicolon separated file with a header line and the columns
+// data;nome;uf;municipio. The date is MM-DD for the holidays observed every year, or YYYY-MM-DD
+// for a single year. The state (UF) and the IBGE code of the municipality are empty for the
+// holidays observed everywhere.
+func Read(r io.Reader) ([]domain.Holiday, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = 4
+
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("error reading holidays header: %w", err)
+	}
+
+	var holidays []domain.Holiday
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return holidays, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading holidays: %w", err)
+		}
+
+		holiday, err := parseHoliday(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("error reading holiday at line %d: %w", line, err)
+		}
+		holidays = append(holidays, holiday)
+	}
+}
+
+// parseHoliday parses a record of the holidays file
+func parseHoliday(record []string) (domain.Holiday, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+	h := domain.Holiday{Name: record[1], State: strings.ToUpper(record[2]), MunicipalityCode: record[3]}
+
+	date, err := time.Parse("2006-01-02", record[0])
+	if err == nil {
+		h.Year = date.Year()
+	} else if date, err = time.Parse("2006-01-02", "2000-"+record[0]); err != nil {
+		// 2000 is a leap year, so 02-29 is accepted
+		return h, fmt.Errorf("date %q must be like 12-25 or 2025-12-25", record[0])
+	}
+	h.Month, h.Day = date.Month(), date.Day()
+
+	switch {
+	case h.Name == "":
+		return h, errors.New("name is required")
+	case h.State != "" && !domain.IsValidUF(h.State):
+		return h, fmt.Errorf("unknown state %q", h.State)
+	case h.MunicipalityCode != "" && h.State == "":
+		return h, errors.New("the state of the municipality is required")
+	}
+	return h, nil
+}
@@ -0,0 +1,117 @@
+package holidays
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateHolidays(t *testing.T) {
+	t.Run("should read the embedded state holidays", func(t *testing.T) {
+		// act
+		holidays, err := StateHolidays()
+
+		// assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, holidays)
+		for _, h := range holidays {
+			assert.True(t, domain.IsValidUF(h.State), h.Name)
+			assert.Empty(t, h.MunicipalityCode, h.Name)
+		}
+	})
+
+	t.Run("should observe the state holidays only in their state", func(t *testing.T) {
+		// arrange
+		holidays, err := StateHolidays()
+		assert.NoError(t, err)
+		calendar := domain.NewCalendar(holidays...)
+		date := time.Date(2025, time.July, 9, 10, 0, 0, 0, time.UTC)
+
+		// act
+		holiday, inSaoPaulo := calendar.HolidayOn(date, "SP", "")
+		_, inParana := calendar.HolidayOn(date, "PR", "")
+
+		// assert
+		assert.True(t, inSaoPaulo)
+		assert.Equal(t, "Revolução Constitucionalista", holiday.Name)
+		assert.False(t, inParana)
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("should read yearly, single year and municipal holidays", func(t *testing.T) {
+		// arrange
+		file := "data;nome;uf;municipio\n" +
+			"05-10; Aniversário de Maringá ;pr;4115200\n" +
+			"2026-10-04;Eleições;;\n"
+
+		// act
+		holidays, err := Read(strings.NewReader(file))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Holiday{
+			{Name: "Aniversário de Maringá", Month: time.May, Day: 10, State: "PR", MunicipalityCode: "4115200"},
+			{Name: "Eleições", Year: 2026, Month: time.October, Day: 4},
+		}, holidays)
+	})
+
+	t.Run("should accept the leap day", func(t *testing.T) {
+		// act
+		holidays, err := Read(strings.NewReader("data;nome;uf;municipio\n02-29;Dia bissexto;;\n"))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, holidays, 1)
+	})
+
+	for _, tt := range []struct {
+		name   string
+		line   string
+		reason string
+	}{
+		{name: "invalid date", line: "31/12;Réveillon;;", reason: "must be like"},
+		{name: "missing name", line: "12-31;;;", reason: "name is required"},
+		{name: "unknown state", line: "12-31;Réveillon;XX;", reason: "unknown state"},
+		{name: "municipality without state", line: "12-31;Réveillon;;4115200", reason: "state of the municipality"},
+		{name: "missing columns", line: "12-31;Réveillon", reason: "wrong number of fields"},
+	} {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			// act
+			holidays, err := Read(strings.NewReader("data;nome;uf;municipio\n" + tt.line + "\n"))
+
+			// assert
+			assert.ErrorContains(t, err, tt.reason)
+			assert.Nil(t, holidays)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Run("should read the holidays of the file", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "feriados.csv")
+		assert.NoError(t, os.WriteFile(path, []byte("data;nome;uf;municipio\n05-10;Aniversário de Maringá;PR;4115200\n"), 0o600))
+
+		// act
+		holidays, err := LoadFile(path)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, holidays, 1)
+	})
+
+	t.Run("should return an error when the file does not exist", func(t *testing.T) {
+		// act
+		holidays, err := LoadFile(filepath.Join(t.TempDir(), "missing.csv"))
+
+		// assert
+		assert.Error(t, err)
+		assert.Nil(t, holidays)
+	})
+}
@@ -0,0 +1,251 @@
+// Package ibge recognizes the Brazilian states and municipalities typed by users using the
+// territorial division of the IBGE, embedded in the binary
+package ibge
+
+//go:generate go run ./gen -out data
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/textnorm"
+)
+
+// data holds the catalog files, written by the generator in ./gen
+//
+//go:embed data/estados.csv data/municipios.csv
+var data embed.FS
+
+// capitals maps each state to the IBGE code of its capital
+var capitals = map[string]string{
+	"AC": "1200401", "AL": "2704302", "AP": "1600303", "AM": "1302603", "BA": "2927408",
+	"CE": "2304400", "DF": "5300108", "ES": "3205309", "GO": "5208707", "MA": "2111300",
+	"MT": "5103403", "MS": "5002704", "MG": "3106200", "PA": "1501402", "PB": "2507507",
+	"PR": "4106902", "PE": "2611606", "PI": "2211001", "RJ": "3304557", "RN": "2408102",
+	"RS": "4314902", "RO": "1100205", "RR": "1400100", "SC": "4205407", "SP": "3550308",
+	"SE": "2800308", "TO": "1721000",
+}
+
+// municipalityAliases maps the nicknames and abbreviations of the larger cities to their IBGE code
+var municipalityAliases = map[string]string{
+	"bh":        "3106200",
+	"beaga":     "3106200",
+	"sampa":     "3550308",
+	"poa":       "4314902",
+	"floripa":   "4205407",
+	"bsb":       "5300108",
+	"cwb":       "4106902",
+	"ssa":       "2927408",
+	"sjc":       "3549904",
+	"sjrp":      "3549805",
+	"rio preto": "3549805",
+}
+
+// stateAliases maps other names commonly used for the states to their code
+var stateAliases = map[string]string{
+	"brasilia": "DF",
+}
+
+// Catalog implements ports.LocationCatalog over the IBGE states and municipalities
+type Catalog struct {
+	states         []entry
+	municipalities map[string]domain.Municipality
+	// all and byState are the municipality names, identified by code
+	all     []entry
+	byState map[string][]entry
+}
+
+var _ ports.LocationCatalog = (*Catalog)(nil)
+
+// NewCatalog loads the catalog embedded in the binary
+func NewCatalog() (*Catalog, error) {
+	states, err := data.Open("data/estados.csv")
+	if err != nil {
+		return nil, fmt.Errorf("error opening states: %w", err)
+	}
+	defer states.Close()
+
+	municipalities, err := data.Open("data/municipios.csv")
+	if err != nil {
+		return nil, fmt.Errorf("error opening municipalities: %w", err)
+	}
+	defer municipalities.Close()
+
+	return LoadCatalog(states, municipalities)
+}
+
+// LoadCatalog reads a catalog from the CSV files of states (codigo;sigla;nome) and
+// municipalities (codigo;nome;uf), both with a header line
+func LoadCatalog(states, municipalities io.Reader) (*Catalog, error) {
+	c := &Catalog{
+		municipalities: make(map[string]domain.Municipality),
+		byState:        make(map[string][]entry),
+	}
+
+	err := readCSV(states, 3, func(record []string) error {
+		code, uf, name := record[0], strings.ToUpper(record[1]), record[2]
+		if !domain.IsValidUF(uf) {
+			return fmt.Errorf("unknown state %q", uf)
+		}
+		c.states = append(c.states,
+			entry{key: strings.ToLower(uf), id: uf},
+			entry{key: code, id: uf},
+			entry{key: textnorm.Normalize(name), id: uf},
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading states: %w", err)
+	}
+	for alias, uf := range stateAliases {
+		c.states = append(c.states, entry{key: alias, id: uf})
+	}
+
+	err = readCSV(municipalities, 3, func(record []string) error {
+		m := domain.Municipality{Code: record[0], Name: record[1], UF: strings.ToUpper(record[2])}
+		if !domain.IsValidUF(m.UF) {
+			return fmt.Errorf("unknown state %q of %s", m.UF, m.Code)
+		}
+		c.municipalities[m.Code] = m
+		e := entry{key: textnorm.Normalize(m.Name), id: m.Code}
+		c.all = append(c.all, e)
+		c.byState[m.UF] = append(c.byState[m.UF], e)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading municipalities: %w", err)
+	}
+	return c, nil
+}
+
+// readCSV calls fn with every record of the semicolon separated file, skipping its header
+func readCSV(r io.Reader, fields int, fn func(record []string) error) error {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = fields
+
+	if _, err := reader.Read(); err != nil {
+		return fmt.Errorf("error reading header: %w", err)
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// Len returns the number of municipalities in the catalog
+func (c *Catalog) Len() int {
+	return len(c.municipalities)
+}
+
+// MatchState looks up the state named by the text, which may be its name, its code (UF)
+// or its IBGE code
+func (c *Catalog) MatchState(text string) domain.StateMatch {
+	ids := match(c.states, stripFillers(textnorm.Normalize(text)))
+	if len(ids) == 1 {
+		return domain.StateMatch{UF: ids[0]}
+	}
+	return domain.StateMatch{Candidates: ids}
+}
+
+// MatchMunicipality looks up the municipality named by the text, which may be its name, a
+// nickname like "BH", its IBGE code or a reference to the capital like "SP capital"
+func (c *Catalog) MatchMunicipality(uf, text string) domain.MunicipalityMatch {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	query := stripFillers(textnorm.Normalize(text))
+
+	// Codes come from the rows of the disambiguation lists
+	if m, ok := c.municipalities[query]; ok {
+		return c.municipalityMatch([]string{m.Code})
+	}
+
+	// A trailing state, like in "Maringá - PR", restricts the search to that state
+	if tokens := strings.Fields(query); len(tokens) > 1 && domain.IsValidUF(strings.ToUpper(tokens[len(tokens)-1])) {
+		uf = strings.ToUpper(tokens[len(tokens)-1])
+		query = strings.Join(tokens[:len(tokens)-1], " ")
+	}
+
+	query, capitalUF, isCapital := c.parseCapital(query, uf)
+	if isCapital {
+		if code, ok := capitals[capitalUF]; ok {
+			return c.municipalityMatch([]string{code})
+		}
+		return domain.MunicipalityMatch{}
+	}
+
+	if code, ok := municipalityAliases[query]; ok {
+		if m, ok := c.municipalities[code]; ok && (uf == "" || m.UF == uf) {
+			return c.municipalityMatch([]string{code})
+		}
+	}
+
+	entries := c.all
+	if uf != "" {
+		entries = c.byState[uf]
+	}
+	return c.municipalityMatch(match(entries, query))
+}
+
+// municipalityMatch builds the result of a lookup from the codes of the matched municipalities
+func (c *Catalog) municipalityMatch(codes []string) domain.MunicipalityMatch {
+	if len(codes) == 1 {
+		m := c.municipalities[codes[0]]
+		return domain.MunicipalityMatch{Municipality: &m}
+	}
+
+	var result domain.MunicipalityMatch
+	for _, code := range codes {
+		result.Candidates = append(result.Candidates, c.municipalities[code])
+	}
+	return result
+}
+
+// parseCapital recognizes the queries asking for the capital of a state, like "capital",
+// "sp capital" or "capital do parana", returning the state whose capital was asked for.
+// Otherwise the word "capital" is dropped from the query, so "campinas capital" reads "campinas".
+func (c *Catalog) parseCapital(query, uf string) (string, string, bool) {
+	tokens := strings.Fields(query)
+	rest := make([]string, 0, len(tokens))
+	asked := false
+	for _, token := range tokens {
+		if token == "capital" {
+			asked = true
+			continue
+		}
+		rest = append(rest, token)
+	}
+	if !asked {
+		return query, "", false
+	}
+
+	var states []string
+	for _, token := range rest {
+		if !connectives[token] {
+			states = append(states, token)
+		}
+	}
+	if len(states) == 0 {
+		return "", uf, uf != ""
+	}
+	if match := c.MatchState(strings.Join(states, " ")); match.Found() {
+		return "", match.UF, true
+	}
+	return strings.Join(rest, " "), "", false
+}
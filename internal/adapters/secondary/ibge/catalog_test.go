@@ -0,0 +1,219 @@
+package ibge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	catalog, err := NewCatalog()
+	assert.NoError(t, err)
+	return catalog
+}
+
+// names returns the names and states of the municipalities
+func names(municipalities []domain.Municipality) []string {
+	result := make([]string, len(municipalities))
+	for i, m := range municipalities {
+		result[i] = m.String()
+	}
+	return result
+}
+
+func TestNewCatalog(t *testing.T) {
+	t.Run("should load every state and the capitals of the embedded data", func(t *testing.T) {
+		// act
+		catalog := newTestCatalog(t)
+
+		// assert
+		assert.Len(t, catalog.states, 3*len(domain.States)+len(stateAliases))
+		for uf, code := range capitals {
+			m, ok := catalog.municipalities[code]
+			assert.True(t, ok, "capital of %s", uf)
+			assert.Equal(t, uf, m.UF)
+		}
+		for _, code := range municipalityAliases {
+			assert.Contains(t, catalog.municipalities, code)
+		}
+		assert.Equal(t, len(catalog.municipalities), catalog.Len())
+	})
+
+	t.Run("should refuse unknown states", func(t *testing.T) {
+		// arrange
+		states := "codigo;sigla;nome\n41;PR;Paraná\n"
+		municipalities := "codigo;nome;uf\n4115200;Maringá;XX\n"
+
+		// act
+		_, err := LoadCatalog(strings.NewReader(states), strings.NewReader(municipalities))
+
+		// assert
+		assert.ErrorContains(t, err, `unknown state "XX"`)
+	})
+
+	t.Run("should refuse malformed files", func(t *testing.T) {
+		// act
+		_, err := LoadCatalog(strings.NewReader("codigo;sigla;nome\n41;PR\n"), strings.NewReader(""))
+
+		// assert
+		assert.ErrorContains(t, err, "error reading states")
+	})
+}
+
+func TestCatalogMatchState(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "code", text: "PR", expected: "PR"},
+		{name: "lowercase code", text: " sp ", expected: "SP"},
+		{name: "IBGE code", text: "41", expected: "PR"},
+		{name: "name", text: "Paraná", expected: "PR"},
+		{name: "name without accents", text: "PARANA", expected: "PR"},
+		{name: "name with filler", text: "Estado de São Paulo", expected: "SP"},
+		{name: "name with typo", text: "Pernanbuco", expected: "PE"},
+		{name: "name with transposed letters", text: "Sergpie", expected: "SE"},
+		{name: "beginning of the name", text: "Minas", expected: "MG"},
+		{name: "alias", text: "Brasília", expected: "DF"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should match the "+tt.name, func(t *testing.T) {
+			// act
+			match := catalog.MatchState(tt.text)
+
+			// assert
+			assert.True(t, match.Found())
+			assert.Equal(t, tt.expected, match.UF)
+			assert.Empty(t, match.Candidates)
+		})
+	}
+
+	t.Run("should return the candidates of an ambiguous text", func(t *testing.T) {
+		// act
+		match := catalog.MatchState("Rio")
+
+		// assert
+		assert.False(t, match.Found())
+		assert.Equal(t, []string{"RJ", "RS", "RN"}, match.Candidates)
+	})
+
+	t.Run("should not find unknown states", func(t *testing.T) {
+		for _, text := range []string{"", "XX", "Buenos Aires", "!!!"} {
+			match := catalog.MatchState(text)
+			assert.False(t, match.Found(), text)
+			assert.Empty(t, match.Candidates, text)
+		}
+	})
+}
+
+func TestCatalogMatchMunicipality(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	tests := []struct {
+		name     string
+		uf       string
+		text     string
+		expected string
+	}{
+		{name: "name", uf: "PR", text: "Maringá", expected: "Maringá/PR"},
+		{name: "name without accents", uf: "PR", text: "maringa", expected: "Maringá/PR"},
+		{name: "name with typo", uf: "PR", text: "Curitbia", expected: "Curitiba/PR"},
+		{name: "name with filler", uf: "PR", text: "cidade de Londrina", expected: "Londrina/PR"},
+		{name: "name with punctuation", uf: "RO", text: "ji parana", expected: "Ji-Paraná/RO"},
+		{name: "name in the whole country", uf: "", text: "Uberlândia", expected: "Uberlândia/MG"},
+		{name: "name followed by the state", uf: "", text: "Cascavel - PR", expected: "Cascavel/PR"},
+		{name: "name followed by another state", uf: "CE", text: "Cascavel/PR", expected: "Cascavel/PR"},
+		{name: "IBGE code", uf: "PR", text: "4115200", expected: "Maringá/PR"},
+		{name: "nickname", uf: "MG", text: "BH", expected: "Belo Horizonte/MG"},
+		{name: "nickname in the whole country", uf: "", text: "floripa", expected: "Florianópolis/SC"},
+		{name: "capital of the state", uf: "SP", text: "capital", expected: "São Paulo/SP"},
+		{name: "capital of a named state", uf: "", text: "SP capital", expected: "São Paulo/SP"},
+		{name: "capital with connective", uf: "", text: "capital do Paraná", expected: "Curitiba/PR"},
+		{name: "name followed by capital", uf: "SP", text: "São Paulo capital", expected: "São Paulo/SP"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should match the "+tt.name, func(t *testing.T) {
+			// act
+			match := catalog.MatchMunicipality(tt.uf, tt.text)
+
+			// assert
+			assert.True(t, match.Found())
+			if match.Found() {
+				assert.Equal(t, tt.expected, match.Municipality.String())
+			}
+			assert.Empty(t, match.Candidates)
+		})
+	}
+
+	t.Run("should return the homonyms of other states as candidates", func(t *testing.T) {
+		// act
+		match := catalog.MatchMunicipality("", "Cascavel")
+
+		// assert
+		assert.False(t, match.Found())
+		assert.Equal(t, []string{"Cascavel/CE", "Cascavel/PR"}, names(match.Candidates))
+	})
+
+	t.Run("should list the municipalities of ambiguous names", func(t *testing.T) {
+		tests := []struct {
+			uf       string
+			text     string
+			expected []string
+		}{
+			{uf: "", text: "Rio", expected: []string{"Rio Verde/GO", "Rio Branco/AC", "Rio de Janeiro/RJ"}},
+			{uf: "SP", text: "São José", expected: []string{"São José dos Campos/SP", "São José do Rio Preto/SP"}},
+		}
+
+		for _, tt := range tests {
+			// act
+			match := catalog.MatchMunicipality(tt.uf, tt.text)
+
+			// assert
+			assert.False(t, match.Found(), tt.text)
+			assert.Equal(t, tt.expected, names(match.Candidates), tt.text)
+		}
+	})
+
+	t.Run("should not use nicknames of other states", func(t *testing.T) {
+		// act
+		match := catalog.MatchMunicipality("PR", "BH")
+
+		// assert
+		assert.False(t, match.Found())
+		assert.Empty(t, match.Candidates)
+	})
+
+	t.Run("should not find municipalities of other states", func(t *testing.T) {
+		// act
+		match := catalog.MatchMunicipality("SP", "Maringá")
+
+		// assert
+		assert.False(t, match.Found())
+		assert.Empty(t, match.Candidates)
+	})
+
+	t.Run("should limit the number of candidates", func(t *testing.T) {
+		// act
+		match := catalog.MatchMunicipality("", "São")
+
+		// assert
+		assert.False(t, match.Found())
+		assert.Len(t, match.Candidates, MaxCandidates)
+	})
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, distance("parana", "parana"))
+	assert.Equal(t, 1, distance("parna", "parana"))
+	assert.Equal(t, 1, distance("sergpie", "sergipe"))
+	assert.Equal(t, 1, distance("curitba", "curitiba"))
+	assert.Equal(t, 3, distance("", "abc"))
+}
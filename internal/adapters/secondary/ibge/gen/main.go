@@ -0,0 +1,108 @@
+// Command gen downloads the states and municipalities of the IBGE localities API and writes
+// the CSV files embedded by the catalog. Run it with go generate in the ibge package.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const apiURL = "https://servicodados.ibge.gov.br/api/v1/localidades"
+
+// state is an item of the states endpoint
+type state struct {
+	ID    int    `json:"id"`
+	Sigla string `json:"sigla"`
+	Nome  string `json:"nome"`
+}
+
+// municipality is an item of the flattened view of the municipalities endpoint
+type municipality struct {
+	ID   int    `json:"municipio-id"`
+	Nome string `json:"municipio-nome"`
+	UF   string `json:"UF-sigla"`
+}
+
+func main() {
+	out := flag.String("out", "data", "directory where the CSV files are written")
+	flag.Parse()
+
+	if err := run(*out); err != nil {
+		fmt.Fprintf(os.Stderr, "error generating the IBGE catalog: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string) error {
+	client := &http.Client{Timeout: time.Minute}
+
+	var states []state
+	if err := fetch(client, apiURL+"/estados", &states); err != nil {
+		return err
+	}
+	var municipalities []municipality
+	if err := fetch(client, apiURL+"/municipios?view=nivelado", &municipalities); err != nil {
+		return err
+	}
+	if len(states) != 27 || len(municipalities) < 5000 {
+		return fmt.Errorf("unexpected response with %d states and %d municipalities", len(states), len(municipalities))
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+	sort.Slice(municipalities, func(i, j int) bool { return municipalities[i].ID < municipalities[j].ID })
+
+	stateRecords := [][]string{{"codigo", "sigla", "nome"}}
+	for _, s := range states {
+		stateRecords = append(stateRecords, []string{strconv.Itoa(s.ID), s.Sigla, s.Nome})
+	}
+	municipalityRecords := [][]string{{"codigo", "nome", "uf"}}
+	for _, m := range municipalities {
+		municipalityRecords = append(municipalityRecords, []string{strconv.Itoa(m.ID), m.Nome, m.UF})
+	}
+
+	if err := write(filepath.Join(out, "estados.csv"), stateRecords); err != nil {
+		return err
+	}
+	return write(filepath.Join(out, "municipios.csv"), municipalityRecords)
+}
+
+// fetch decodes the JSON response of the URL into v
+func fetch(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("error requesting %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error requesting %s: status %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", url, err)
+	}
+	return nil
+}
+
+// write writes the records to a semicolon separated file
+func write(path string, records [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", path, err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Comma = ';'
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return file.Close()
+}
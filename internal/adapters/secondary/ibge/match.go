@@ -0,0 +1,148 @@
+package ibge
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxCandidates is the maximum number of candidates returned for an ambiguous text
+const MaxCandidates = 5
+
+// minPrefixLength is the length a text must have to be matched as the beginning of a name
+const minPrefixLength = 3
+
+// fillers are the leading words of answers like "estado de São Paulo" or "cidade de Curitiba"
+var fillers = []string{"estado de ", "estado do ", "estado da ", "cidade de ", "municipio de ", "moro em ", "sou de "}
+
+// connectives are the words that may join the parts of a text, like in "capital de SP"
+var connectives = map[string]bool{"de": true, "do": true, "da": true}
+
+// entry is a name of the catalog, normalized for matching, and the ID of what it names
+type entry struct {
+	key string
+	id  string
+}
+
+// stripFillers removes the leading filler words of a normalized text
+func stripFillers(text string) string {
+	for _, filler := range fillers {
+		if strings.HasPrefix(text, filler) {
+			return strings.TrimPrefix(text, filler)
+		}
+	}
+	return text
+}
+
+// match returns the IDs of the entries best matching the normalized query: the exact names,
+// else the closest names within the typo tolerance of the query, else the names starting with it.
+// At most MaxCandidates IDs are returned.
+func match(entries []entry, query string) []string {
+	if query == "" {
+		return nil
+	}
+
+	var exact []entry
+	for _, e := range entries {
+		if e.key == query {
+			exact = append(exact, e)
+		}
+	}
+	if len(exact) > 0 {
+		return ids(exact)
+	}
+
+	var closest []entry
+	best := tolerance(query)
+	for _, e := range entries {
+		d := distance(query, e.key)
+		if d > best {
+			continue
+		}
+		if d < best {
+			best = d
+			closest = closest[:0]
+		}
+		closest = append(closest, e)
+	}
+	if len(closest) > 0 {
+		return ids(closest)
+	}
+
+	if len(query) < minPrefixLength {
+		return nil
+	}
+	var prefixed []entry
+	for _, e := range entries {
+		if strings.HasPrefix(e.key, query) {
+			prefixed = append(prefixed, e)
+		}
+	}
+	return ids(prefixed)
+}
+
+// ids returns the distinct IDs of the entries, the shortest names first
+func ids(entries []entry) []string {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if len(entries[i].key) != len(entries[j].key) {
+			return len(entries[i].key) < len(entries[j].key)
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, e := range entries {
+		if seen[e.id] {
+			continue
+		}
+		seen[e.id] = true
+		result = append(result, e.id)
+		if len(result) == MaxCandidates {
+			break
+		}
+	}
+	return result
+}
+
+// tolerance returns the number of typos accepted in a query, growing with its length.
+// Short queries like state codes must be typed exactly.
+func tolerance(query string) int {
+	switch n := len([]rune(query)); {
+	case n < 4:
+		return 0
+	case n <= 6:
+		return 1
+	case n <= 12:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// distance returns the number of insertions, deletions, substitutions and transpositions of
+// adjacent letters needed to turn a into b (optimal string alignment distance)
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
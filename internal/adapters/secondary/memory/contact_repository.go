@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// ContactRepository is an in-memory implementation of ports.ContactRepository
+type ContactRepository struct {
+	mu       sync.RWMutex
+	contacts map[string]domain.Contact
+}
+
+var _ ports.ContactRepository = (*ContactRepository)(nil)
+
+// NewContactRepository creates a new in-memory contact repository
+func NewContactRepository() *ContactRepository {
+	return &ContactRepository{
+		contacts: make(map[string]domain.Contact),
+	}
+}
+
+// FindByPhone returns a copy of the contact stored for the given phone number
+func (r *ContactRepository) FindByPhone(ctx context.Context, phone string) (*domain.Contact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contact, ok := r.contacts[phone]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &contact, nil
+}
+
+// Save stores a copy of the contact, replacing any previous version
+func (r *ContactRepository) Save(ctx context.Context, contact *domain.Contact) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.contacts[contact.Phone] = *contact
+	return nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/porttest"
+)
+
+func TestConversationRepositoryContract(t *testing.T) {
+	porttest.TestConversationRepository(t, func(t *testing.T) ports.ConversationRepository {
+		return NewConversationRepository()
+	})
+}
+
+func TestContactRepositoryContract(t *testing.T) {
+	porttest.TestContactRepository(t, func(t *testing.T) ports.ContactRepository {
+		return NewContactRepository()
+	})
+}
+
+func TestMessageRepositoryContract(t *testing.T) {
+	porttest.TestMessageRepository(t, func(t *testing.T) ports.MessageRepository {
+		return NewMessageRepository()
+	})
+}
+
+func TestLeadRepositoryContract(t *testing.T) {
+	porttest.TestLeadRepository(t, func(t *testing.T) ports.LeadRepository {
+		return NewLeadRepository()
+	})
+}
+
+func TestHandoffRepositoryContract(t *testing.T) {
+	porttest.TestHandoffRepository(t, func(t *testing.T) ports.HandoffRepository {
+		return NewHandoffRepository()
+	})
+}
+
+func TestFAQRepositoryContract(t *testing.T) {
+	porttest.TestFAQRepository(t, func(t *testing.T) ports.FAQRepository {
+		return NewFAQRepository()
+	})
+}
+
+func TestStatusRepositoryContract(t *testing.T) {
+	porttest.TestStatusRepository(t, func(t *testing.T) ports.StatusRepository {
+		return NewStatusRepository()
+	})
+}
+
+func TestDedupStoreContract(t *testing.T) {
+	porttest.TestDedupStore(t, func(t *testing.T) ports.DedupStore {
+		return NewDedupStore()
+	})
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// ConversationRepository is an in-memory implementation of ports.ConversationRepository
+type ConversationRepository struct {
+	mu            sync.RWMutex
+	conversations map[string]domain.Conversation
+}
+
+var _ ports.ConversationRepository = (*ConversationRepository)(nil)
+
+// NewConversationRepository creates a new in-memory conversation repository
+func NewConversationRepository() *ConversationRepository {
+	return &ConversationRepository{
+		conversations: make(map[string]domain.Conversation),
+	}
+}
+
+// FindByPhone returns a copy of the conversation stored for the given phone number
+func (r *ConversationRepository) FindByPhone(ctx context.Context, phone string) (*domain.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversation, ok := r.conversations[phone]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &conversation, nil
+}
+
+// Save stores a copy of the conversation, replacing any previous version
+func (r *ConversationRepository) Save(ctx context.Context, conversation *domain.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.conversations[conversation.Phone] = *conversation
+	return nil
+}
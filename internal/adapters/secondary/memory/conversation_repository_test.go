@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversationRepository(t *testing.T) {
+	t.Run("should return not found for unknown phone", func(t *testing.T) {
+		// arrange
+		repo := NewConversationRepository()
+
+		// act
+		conversation, err := repo.FindByPhone(context.Background(), "554499887766")
+
+		// assert
+		assert.Nil(t, conversation)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should save and find a conversation", func(t *testing.T) {
+		// arrange
+		repo := NewConversationRepository()
+		conversation := domain.NewConversation("554499887766", time.Now())
+		conversation.Step = domain.StepMainMenu
+
+		// act
+		err := repo.Save(context.Background(), conversation)
+		found, findErr := repo.FindByPhone(context.Background(), "554499887766")
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, domain.StepMainMenu, found.Step)
+	})
+
+	t.Run("should not share state with stored conversations", func(t *testing.T) {
+		// arrange
+		repo := NewConversationRepository()
+		conversation := domain.NewConversation("554499887766", time.Now())
+		repo.Save(context.Background(), conversation)
+
+		// act
+		conversation.Step = domain.StepCompleted
+		found, _ := repo.FindByPhone(context.Background(), "554499887766")
+
+		// assert
+		assert.Equal(t, domain.StepStart, found.Step)
+	})
+}
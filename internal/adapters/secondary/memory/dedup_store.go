@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// minPurgeSize is the number of keys below which expired keys are not purged
+const minPurgeSize = 1024
+
+// DedupStore is an in-memory implementation of ports.DedupStore
+type DedupStore struct {
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	nextPurge int
+	now       func() time.Time
+}
+
+var _ ports.DedupStore = (*DedupStore)(nil)
+
+// NewDedupStore creates a new in-memory deduplication store
+func NewDedupStore() *DedupStore {
+	return &DedupStore{
+		expires:   make(map[string]time.Time),
+		nextPurge: minPurgeSize,
+		now:       time.Now,
+	}
+}
+
+// Claim records the key until the ttl elapses, returning false if it is already recorded
+func (s *DedupStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if expires, ok := s.expires[key]; ok && now.Before(expires) {
+		return false, nil
+	}
+	s.expires[key] = now.Add(ttl)
+
+	// Expired keys are purged once the map doubles, keeping the cost of a claim constant
+	if len(s.expires) >= s.nextPurge {
+		for k, expires := range s.expires {
+			if !now.Before(expires) {
+				delete(s.expires, k)
+			}
+		}
+		s.nextPurge = max(minPurgeSize, 2*len(s.expires))
+	}
+	return true, nil
+}
+
+// Release forgets the key
+func (s *DedupStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.expires, key)
+	return nil
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("should claim a key only once", func(t *testing.T) {
+		// arrange
+		store := NewDedupStore()
+
+		// act
+		first, err1 := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		second, err2 := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		other, err3 := store.Claim(context.Background(), "message:wamid.2", time.Hour)
+
+		// assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.NoError(t, err3)
+		assert.True(t, first)
+		assert.False(t, second)
+		assert.True(t, other)
+	})
+
+	t.Run("should claim the key again after it expires", func(t *testing.T) {
+		// arrange
+		store := NewDedupStore()
+		store.now = func() time.Time { return now }
+		store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// act
+		store.now = func() time.Time { return now.Add(time.Hour) }
+		claimed, err := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("should claim the key again after it is released", func(t *testing.T) {
+		// arrange
+		store := NewDedupStore()
+		store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// act
+		err := store.Release(context.Background(), "message:wamid.1")
+		claimed, _ := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("should purge expired keys", func(t *testing.T) {
+		// arrange
+		store := NewDedupStore()
+		store.now = func() time.Time { return now }
+		for i := 0; i < minPurgeSize-1; i++ {
+			store.Claim(context.Background(), fmt.Sprintf("message:%d", i), time.Minute)
+		}
+
+		// act
+		store.now = func() time.Time { return now.Add(time.Hour) }
+		store.Claim(context.Background(), "message:last", time.Minute)
+
+		// assert
+		assert.Len(t, store.expires, 1)
+	})
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// EventHandler reacts to a domain event
+type EventHandler func(ctx context.Context, event domain.Event) error
+
+// EventBus is an in-process implementation of ports.EventPublisher that hands the events over
+// to the handlers subscribed to their name, in the publishing goroutine
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+}
+
+var _ ports.EventPublisher = (*EventBus)(nil)
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{
+		handlers: make(map[string][]EventHandler),
+	}
+}
+
+// Subscribe registers the handler for the events with the given name
+func (b *EventBus) Subscribe(name string, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish calls every handler of the event, returning the errors of all failed handlers
+func (b *EventBus) Publish(ctx context.Context, event domain.Event) error {
+	b.mu.RLock()
+	handlers := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
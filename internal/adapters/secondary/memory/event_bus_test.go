@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBus(t *testing.T) {
+	event := domain.LeadQualified{Lead: domain.Lead{Phone: "554499887766"}, At: time.Now()}
+
+	t.Run("should hand the event over to the handlers subscribed to it", func(t *testing.T) {
+		// arrange
+		bus := NewEventBus()
+		var received []string
+		bus.Subscribe(domain.LeadQualifiedEvent, func(ctx context.Context, e domain.Event) error {
+			received = append(received, "first:"+e.(domain.LeadQualified).Lead.Phone)
+			return nil
+		})
+		bus.Subscribe(domain.LeadQualifiedEvent, func(ctx context.Context, e domain.Event) error {
+			received = append(received, "second:"+e.(domain.LeadQualified).Lead.Phone)
+			return nil
+		})
+		bus.Subscribe("other.event", func(ctx context.Context, e domain.Event) error {
+			received = append(received, "other")
+			return nil
+		})
+
+		// act
+		err := bus.Publish(context.Background(), event)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first:554499887766", "second:554499887766"}, received)
+	})
+
+	t.Run("should call every handler and return their errors", func(t *testing.T) {
+		// arrange
+		bus := NewEventBus()
+		calls := 0
+		bus.Subscribe(domain.LeadQualifiedEvent, func(ctx context.Context, e domain.Event) error {
+			calls++
+			return errors.New("crm down")
+		})
+		bus.Subscribe(domain.LeadQualifiedEvent, func(ctx context.Context, e domain.Event) error {
+			calls++
+			return nil
+		})
+
+		// act
+		err := bus.Publish(context.Background(), event)
+
+		// assert
+		assert.EqualError(t, err, "crm down")
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should accept events without subscribers", func(t *testing.T) {
+		// act
+		err := NewEventBus().Publish(context.Background(), event)
+
+		// assert
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// FAQRepository is an in-memory implementation of ports.FAQRepository
+type FAQRepository struct {
+	mu      sync.RWMutex
+	entries map[string]domain.FAQEntry
+}
+
+var _ ports.FAQRepository = (*FAQRepository)(nil)
+
+// NewFAQRepository creates a new in-memory FAQ repository
+func NewFAQRepository() *FAQRepository {
+	return &FAQRepository{
+		entries: make(map[string]domain.FAQEntry),
+	}
+}
+
+// FindByID returns a copy of the entry stored with the given ID
+func (r *FAQRepository) FindByID(ctx context.Context, id string) (*domain.FAQEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	entry = copyFAQEntry(entry)
+	return &entry, nil
+}
+
+// Save stores a copy of the entry, replacing any previous version
+func (r *FAQRepository) Save(ctx context.Context, entry *domain.FAQEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[entry.ID] = copyFAQEntry(*entry)
+	return nil
+}
+
+// Delete removes the entry stored with the given ID
+func (r *FAQRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.entries, id)
+	return nil
+}
+
+// List returns copies of every entry ordered by ID
+func (r *FAQRepository) List(ctx context.Context) ([]domain.FAQEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]domain.FAQEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, copyFAQEntry(entry))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// copyFAQEntry copies the entry along with its keywords
+func copyFAQEntry(entry domain.FAQEntry) domain.FAQEntry {
+	entry.Keywords = append([]string(nil), entry.Keywords...)
+	return entry
+}
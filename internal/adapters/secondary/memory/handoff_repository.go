@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// HandoffRepository is an in-memory implementation of ports.HandoffRepository
+type HandoffRepository struct {
+	mu       sync.RWMutex
+	handoffs map[string]domain.Handoff
+}
+
+var _ ports.HandoffRepository = (*HandoffRepository)(nil)
+
+// NewHandoffRepository creates a new in-memory handoff repository
+func NewHandoffRepository() *HandoffRepository {
+	return &HandoffRepository{
+		handoffs: make(map[string]domain.Handoff),
+	}
+}
+
+// FindByPhone returns a copy of the handoff stored for the given phone number
+func (r *HandoffRepository) FindByPhone(ctx context.Context, phone string) (*domain.Handoff, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handoff, ok := r.handoffs[phone]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &handoff, nil
+}
+
+// Save stores a copy of the handoff without its events, replacing the previous version
+// unless it was saved since the handoff was loaded
+func (r *HandoffRepository) Save(ctx context.Context, handoff *domain.Handoff) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if previous, ok := r.handoffs[handoff.Phone]; ok && previous.Version != handoff.Version {
+		return fmt.Errorf("%w: handoff %s is at version %d, not %d", domain.ErrConflict, handoff.Phone, previous.Version, handoff.Version)
+	}
+
+	handoff.Version++
+	stored := *handoff
+	stored.PullEvents()
+	r.handoffs[handoff.Phone] = stored
+	return nil
+}
+
+// List returns the handoffs selected by the filter ordered by opening time, the oldest first.
+// Handoffs opened at the same time are ordered by phone number.
+func (r *HandoffRepository) List(ctx context.Context, filter domain.HandoffFilter) ([]domain.Handoff, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handoffs := make([]domain.Handoff, 0, len(r.handoffs))
+	for _, handoff := range r.handoffs {
+		if filter.Matches(handoff) {
+			handoffs = append(handoffs, handoff)
+		}
+	}
+	sort.Slice(handoffs, func(i, j int) bool {
+		if !handoffs[i].OpenedAt.Equal(handoffs[j].OpenedAt) {
+			return handoffs[i].OpenedAt.Before(handoffs[j].OpenedAt)
+		}
+		return handoffs[i].Phone < handoffs[j].Phone
+	})
+
+	if filter.Limit > 0 && len(handoffs) > filter.Limit {
+		handoffs = handoffs[:filter.Limit]
+	}
+	return handoffs, nil
+}
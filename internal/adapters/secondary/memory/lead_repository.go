@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// LeadRepository is an in-memory implementation of ports.LeadRepository
+type LeadRepository struct {
+	mu    sync.RWMutex
+	leads map[string]domain.Lead
+}
+
+var _ ports.LeadRepository = (*LeadRepository)(nil)
+
+// NewLeadRepository creates a new in-memory lead repository
+func NewLeadRepository() *LeadRepository {
+	return &LeadRepository{
+		leads: make(map[string]domain.Lead),
+	}
+}
+
+// FindByPhone returns a copy of the lead stored for the given phone number
+func (r *LeadRepository) FindByPhone(ctx context.Context, phone string) (*domain.Lead, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lead, ok := r.leads[phone]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &lead, nil
+}
+
+// Save stores a copy of the lead without its events, replacing any previous version
+func (r *LeadRepository) Save(ctx context.Context, lead *domain.Lead) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *lead
+	stored.PullEvents()
+	r.leads[lead.Phone] = stored
+	return nil
+}
+
+// List returns the leads selected by the filter ordered by update time, the most recent first.
+// Leads updated at the same time are ordered by phone number.
+func (r *LeadRepository) List(ctx context.Context, filter domain.LeadFilter) ([]domain.Lead, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	leads := make([]domain.Lead, 0, len(r.leads))
+	for _, lead := range r.leads {
+		if filter.Stage == "" || lead.Stage == filter.Stage {
+			leads = append(leads, lead)
+		}
+	}
+	sort.Slice(leads, func(i, j int) bool {
+		if !leads[i].UpdatedAt.Equal(leads[j].UpdatedAt) {
+			return leads[i].UpdatedAt.After(leads[j].UpdatedAt)
+		}
+		return leads[i].Phone < leads[j].Phone
+	})
+
+	if filter.Limit > 0 && len(leads) > filter.Limit {
+		leads = leads[:filter.Limit]
+	}
+	return leads, nil
+}
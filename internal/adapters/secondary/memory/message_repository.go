@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// MessageRepository is an in-memory implementation of ports.MessageRepository
+type MessageRepository struct {
+	mu sync.RWMutex
+	// messages holds the messages of each phone number by message ID
+	messages map[string]map[string]domain.MessageRecord
+	// phones holds the phone number of each stored message ID
+	phones map[string]string
+}
+
+var _ ports.MessageRepository = (*MessageRepository)(nil)
+
+// NewMessageRepository creates a new in-memory message repository
+func NewMessageRepository() *MessageRepository {
+	return &MessageRepository{
+		messages: make(map[string]map[string]domain.MessageRecord),
+		phones:   make(map[string]string),
+	}
+}
+
+// Save stores the message, replacing any message stored with the same ID
+func (r *MessageRepository) Save(ctx context.Context, message domain.MessageRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if phone, ok := r.phones[message.ID]; ok {
+		delete(r.messages[phone], message.ID)
+	}
+	if r.messages[message.Phone] == nil {
+		r.messages[message.Phone] = make(map[string]domain.MessageRecord)
+	}
+	r.messages[message.Phone][message.ID] = message
+	r.phones[message.ID] = message.Phone
+	return nil
+}
+
+// FindByPhone returns the last limit messages of the contact ordered by timestamp and ID,
+// or all of them when limit is not positive
+func (r *MessageRepository) FindByPhone(ctx context.Context, phone string, limit int) ([]domain.MessageRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	messages := make([]domain.MessageRecord, 0, len(r.messages[phone]))
+	for _, message := range r.messages[phone] {
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].Timestamp.Equal(messages[j].Timestamp) {
+			return messages[i].Timestamp.Before(messages[j].Timestamp)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+
+	if limit > 0 && len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+	return messages, nil
+}
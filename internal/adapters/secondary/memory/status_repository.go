@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// StatusRepository is an in-memory implementation of ports.StatusRepository
+type StatusRepository struct {
+	mu       sync.RWMutex
+	statuses map[string][]domain.StatusEvent
+}
+
+var _ ports.StatusRepository = (*StatusRepository)(nil)
+
+// NewStatusRepository creates a new in-memory status repository
+func NewStatusRepository() *StatusRepository {
+	return &StatusRepository{
+		statuses: make(map[string][]domain.StatusEvent),
+	}
+}
+
+// AppendStatus adds the event to the timeline of its message unless the status was already recorded
+func (r *StatusRepository) AppendStatus(ctx context.Context, event domain.StatusEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.statuses[event.MessageID] {
+		if existing.Status == event.Status {
+			return nil
+		}
+	}
+	r.statuses[event.MessageID] = append(r.statuses[event.MessageID], event)
+	return nil
+}
+
+// FindStatuses returns a copy of the events of the message ordered by timestamp
+func (r *StatusRepository) FindStatuses(ctx context.Context, messageID string) ([]domain.StatusEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := append([]domain.StatusEvent(nil), r.statuses[messageID]...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusRepository(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("should return the events ordered by timestamp", func(t *testing.T) {
+		// arrange
+		repo := NewStatusRepository()
+		repo.AppendStatus(context.Background(), domain.StatusEvent{MessageID: "wamid.1", Status: domain.StatusDelivered, Timestamp: now.Add(time.Second)})
+		repo.AppendStatus(context.Background(), domain.StatusEvent{MessageID: "wamid.1", Status: domain.StatusSent, Timestamp: now})
+		repo.AppendStatus(context.Background(), domain.StatusEvent{MessageID: "wamid.2", Status: domain.StatusSent, Timestamp: now})
+
+		// act
+		events, err := repo.FindStatuses(context.Background(), "wamid.1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, domain.StatusSent, events[0].Status)
+		assert.Equal(t, domain.StatusDelivered, events[1].Status)
+	})
+
+	t.Run("should ignore repeated statuses", func(t *testing.T) {
+		// arrange
+		repo := NewStatusRepository()
+		event := domain.StatusEvent{MessageID: "wamid.1", Status: domain.StatusSent, Timestamp: now}
+
+		// act
+		repo.AppendStatus(context.Background(), event)
+		repo.AppendStatus(context.Background(), event)
+		events, _ := repo.FindStatuses(context.Background(), "wamid.1")
+
+		// assert
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("should return no events for unknown messages", func(t *testing.T) {
+		// arrange
+		repo := NewStatusRepository()
+
+		// act
+		events, err := repo.FindStatuses(context.Background(), "wamid.unknown")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
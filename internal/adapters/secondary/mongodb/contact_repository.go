@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// contactDocument is the stored form of a domain.Contact, keyed by phone number
+type contactDocument struct {
+	Phone       string    `bson:"_id"`
+	Name        string    `bson:"name,omitempty"`
+	FirstSeenAt time.Time `bson:"first_seen_at"`
+	LastSeenAt  time.Time `bson:"last_seen_at"`
+}
+
+// ContactRepository is a MongoDB implementation of ports.ContactRepository
+type ContactRepository struct {
+	db *Database
+}
+
+var _ ports.ContactRepository = (*ContactRepository)(nil)
+
+// NewContactRepository creates a contact repository on the database
+func NewContactRepository(db *Database) *ContactRepository {
+	return &ContactRepository{db: db}
+}
+
+// FindByPhone returns the contact stored for the given phone number
+func (r *ContactRepository) FindByPhone(ctx context.Context, phone string) (*domain.Contact, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	var doc contactDocument
+	err := r.db.collection(contactsCollection).FindOne(ctx, bson.M{"_id": phone}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding contact: %w", err)
+	}
+
+	return &domain.Contact{
+		Phone:       doc.Phone,
+		Name:        doc.Name,
+		FirstSeenAt: doc.FirstSeenAt,
+		LastSeenAt:  doc.LastSeenAt,
+	}, nil
+}
+
+// Save stores the contact, replacing any previous version
+func (r *ContactRepository) Save(ctx context.Context, contact *domain.Contact) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	doc := contactDocument{
+		Phone:       contact.Phone,
+		Name:        contact.Name,
+		FirstSeenAt: contact.FirstSeenAt,
+		LastSeenAt:  contact.LastSeenAt,
+	}
+	_, err := r.db.collection(contactsCollection).ReplaceOne(ctx, bson.M{"_id": doc.Phone}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving contact: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/porttest"
+)
+
+func TestConversationRepositoryContract(t *testing.T) {
+	porttest.TestConversationRepository(t, func(t *testing.T) ports.ConversationRepository {
+		return NewConversationRepository(newTestDatabase(t))
+	})
+}
+
+func TestContactRepositoryContract(t *testing.T) {
+	porttest.TestContactRepository(t, func(t *testing.T) ports.ContactRepository {
+		return NewContactRepository(newTestDatabase(t))
+	})
+}
+
+func TestMessageRepositoryContract(t *testing.T) {
+	porttest.TestMessageRepository(t, func(t *testing.T) ports.MessageRepository {
+		return NewMessageRepository(newTestDatabase(t))
+	})
+}
+
+func TestLeadRepositoryContract(t *testing.T) {
+	porttest.TestLeadRepository(t, func(t *testing.T) ports.LeadRepository {
+		return NewLeadRepository(newTestDatabase(t))
+	})
+}
+
+func TestHandoffRepositoryContract(t *testing.T) {
+	porttest.TestHandoffRepository(t, func(t *testing.T) ports.HandoffRepository {
+		return NewHandoffRepository(newTestDatabase(t))
+	})
+}
+
+func TestFAQRepositoryContract(t *testing.T) {
+	porttest.TestFAQRepository(t, func(t *testing.T) ports.FAQRepository {
+		return NewFAQRepository(newTestDatabase(t))
+	})
+}
+
+func TestStatusRepositoryContract(t *testing.T) {
+	porttest.TestStatusRepository(t, func(t *testing.T) ports.StatusRepository {
+		return NewStatusRepository(newTestDatabase(t))
+	})
+}
+
+func TestDedupStoreContract(t *testing.T) {
+	porttest.TestDedupStore(t, func(t *testing.T) ports.DedupStore {
+		return NewDedupStore(newTestDatabase(t))
+	})
+}
@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// conversationDocument is the stored form of a domain.Conversation, keyed by phone number
+type conversationDocument struct {
+	Phone            string    `bson:"_id"`
+	Step             string    `bson:"step"`
+	MenuOption       string    `bson:"menu_option,omitempty"`
+	HasCRM           bool      `bson:"has_crm"`
+	CRMNumber        string    `bson:"crm_number,omitempty"`
+	CRMState         string    `bson:"crm_state,omitempty"`
+	CRMSuffix        string    `bson:"crm_suffix,omitempty"`
+	CRMVerified      bool      `bson:"crm_verified"`
+	Specialty        string    `bson:"specialty,omitempty"`
+	State            string    `bson:"state,omitempty"`
+	Municipality     string    `bson:"municipality,omitempty"`
+	MunicipalityCode string    `bson:"municipality_code,omitempty"`
+	Timezone         string    `bson:"timezone,omitempty"`
+	Revenue          float64   `bson:"revenue,omitempty"`
+	Attempts         int       `bson:"attempts,omitempty"`
+	HandoffReason    string    `bson:"handoff_reason,omitempty"`
+	StartedAt        time.Time `bson:"started_at"`
+	UpdatedAt        time.Time `bson:"updated_at"`
+}
+
+// ConversationRepository is a MongoDB implementation of ports.ConversationRepository
+type ConversationRepository struct {
+	db *Database
+}
+
+var _ ports.ConversationRepository = (*ConversationRepository)(nil)
+
+// NewConversationRepository creates a conversation repository on the database
+func NewConversationRepository(db *Database) *ConversationRepository {
+	return &ConversationRepository{db: db}
+}
+
+// FindByPhone returns the conversation stored for the given phone number
+func (r *ConversationRepository) FindByPhone(ctx context.Context, phone string) (*domain.Conversation, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	var doc conversationDocument
+	err := r.db.collection(conversationsCollection).FindOne(ctx, bson.M{"_id": phone}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding conversation: %w", err)
+	}
+
+	return &domain.Conversation{
+		Phone:            doc.Phone,
+		Step:             domain.ConversationStep(doc.Step),
+		MenuOption:       domain.MenuOption(doc.MenuOption),
+		HasCRM:           doc.HasCRM,
+		CRM:              domain.CRM{Number: doc.CRMNumber, State: doc.CRMState, Suffix: doc.CRMSuffix},
+		CRMVerified:      doc.CRMVerified,
+		Specialty:        doc.Specialty,
+		State:            doc.State,
+		Municipality:     doc.Municipality,
+		MunicipalityCode: doc.MunicipalityCode,
+		Timezone:         doc.Timezone,
+		Revenue:          doc.Revenue,
+		Attempts:         doc.Attempts,
+		HandoffReason:    domain.HandoffReason(doc.HandoffReason),
+		StartedAt:        doc.StartedAt,
+		UpdatedAt:        doc.UpdatedAt,
+	}, nil
+}
+
+// Save stores the conversation, replacing any previous version
+func (r *ConversationRepository) Save(ctx context.Context, conversation *domain.Conversation) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	doc := conversationDocument{
+		Phone:            conversation.Phone,
+		Step:             string(conversation.Step),
+		MenuOption:       string(conversation.MenuOption),
+		HasCRM:           conversation.HasCRM,
+		CRMNumber:        conversation.CRM.Number,
+		CRMState:         conversation.CRM.State,
+		CRMSuffix:        conversation.CRM.Suffix,
+		CRMVerified:      conversation.CRMVerified,
+		Specialty:        conversation.Specialty,
+		State:            conversation.State,
+		Municipality:     conversation.Municipality,
+		MunicipalityCode: conversation.MunicipalityCode,
+		Timezone:         conversation.Timezone,
+		Revenue:          conversation.Revenue,
+		Attempts:         conversation.Attempts,
+		HandoffReason:    string(conversation.HandoffReason),
+		StartedAt:        conversation.StartedAt,
+		UpdatedAt:        conversation.UpdatedAt,
+	}
+	_, err := r.db.collection(conversationsCollection).ReplaceOne(ctx, bson.M{"_id": doc.Phone}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving conversation: %w", err)
+	}
+	return nil
+}
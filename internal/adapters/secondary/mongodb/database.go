@@ -0,0 +1,119 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Collection names
+const (
+	conversationsCollection = "conversations"
+	contactsCollection      = "contacts"
+	messagesCollection      = "messages"
+	leadsCollection         = "leads"
+	handoffsCollection      = "handoffs"
+	faqCollection           = "faq"
+	statusesCollection      = "statuses"
+	dedupCollection         = "dedup"
+)
+
+// Database is a connection to the MongoDB database of the application
+type Database struct {
+	client  *mongo.Client
+	db      *mongo.Database
+	timeout time.Duration
+}
+
+// Connect connects to the configured MongoDB deployment and checks that it is reachable
+func Connect(ctx context.Context, cfg config.MongoDBConfig) (*Database, error) {
+	if cfg.URI == "" {
+		return nil, fmt.Errorf("MongoDB URI not configured")
+	}
+
+	opts := options.Client().
+		ApplyURI(cfg.URI).
+		SetConnectTimeout(cfg.Timeout).
+		SetServerSelectionTimeout(cfg.Timeout)
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to MongoDB: %w", err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	if err := client.Ping(pingCtx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("error pinging MongoDB: %w", err)
+	}
+
+	return &Database{
+		client:  client,
+		db:      client.Database(cfg.Database),
+		timeout: cfg.Timeout,
+	}, nil
+}
+
+// EnsureIndexes creates the indexes used by the repositories. Existing indexes are kept.
+func (d *Database) EnsureIndexes(ctx context.Context) error {
+	indexes := map[string][]mongo.IndexModel{
+		conversationsCollection: {
+			{Keys: bson.D{{Key: "step", Value: 1}, {Key: "updated_at", Value: -1}}},
+		},
+		contactsCollection: {
+			{Keys: bson.D{{Key: "last_seen_at", Value: -1}}},
+		},
+		messagesCollection: {
+			{Keys: bson.D{{Key: "phone", Value: 1}, {Key: "timestamp", Value: -1}}},
+		},
+		leadsCollection: {
+			{Keys: bson.D{{Key: "updated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "stage", Value: 1}, {Key: "updated_at", Value: -1}}},
+		},
+		handoffsCollection: {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "opened_at", Value: 1}}},
+			{Keys: bson.D{{Key: "agent", Value: 1}, {Key: "status", Value: 1}, {Key: "opened_at", Value: 1}}},
+		},
+		statusesCollection: {
+			{Keys: bson.D{{Key: "message_id", Value: 1}, {Key: "timestamp", Value: 1}}},
+		},
+		dedupCollection: {
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+	}
+
+	for collection, models := range indexes {
+		opCtx, cancel := d.withTimeout(ctx)
+		_, err := d.db.Collection(collection).Indexes().CreateMany(opCtx, models)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("error creating indexes of %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+// Close disconnects from MongoDB, waiting for the operations in progress until ctx is done
+func (d *Database) Close(ctx context.Context) error {
+	return d.client.Disconnect(ctx)
+}
+
+// collection returns the collection with the given name
+func (d *Database) collection(name string) *mongo.Collection {
+	return d.db.Collection(name)
+}
+
+// withTimeout bounds a single database operation by the configured timeout
+func (d *Database) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d.timeout)
+}
@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDatabase connects to the MongoDB deployment of MONGODB_TEST_URI, e.g. a local
+// mongod at mongodb://localhost:27017, using a database dropped when the test ends.
+// Tests are skipped when the variable is not set.
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set, skipping MongoDB integration test")
+	}
+
+	ctx := context.Background()
+	db, err := Connect(ctx, config.MongoDBConfig{
+		URI:      uri,
+		Database: fmt.Sprintf("contamed_test_%d", time.Now().UnixNano()),
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("error connecting to MongoDB: %v", err)
+	}
+	if err := db.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("error creating indexes: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.db.Drop(ctx)
+		db.Close(ctx)
+	})
+	return db
+}
+
+func TestConnect(t *testing.T) {
+	t.Run("should require the URI", func(t *testing.T) {
+		// act
+		_, err := Connect(context.Background(), config.MongoDBConfig{Database: "test"})
+
+		// assert
+		assert.ErrorContains(t, err, "MongoDB URI not configured")
+	})
+
+	t.Run("should fail when the server is unreachable", func(t *testing.T) {
+		// act
+		_, err := Connect(context.Background(), config.MongoDBConfig{
+			URI:      "mongodb://127.0.0.1:1",
+			Database: "test",
+			Timeout:  100 * time.Millisecond,
+		})
+
+		// assert
+		assert.ErrorContains(t, err, "error pinging MongoDB")
+	})
+
+	t.Run("should create the indexes more than once", func(t *testing.T) {
+		// arrange
+		db := newTestDatabase(t)
+
+		// act
+		err := db.EnsureIndexes(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DedupStore is a MongoDB implementation of ports.DedupStore, shared by every instance of the
+// server. The keys are removed by the TTL index on expires_at once they expire.
+type DedupStore struct {
+	db  *Database
+	now func() time.Time
+}
+
+var _ ports.DedupStore = (*DedupStore)(nil)
+
+// NewDedupStore creates a deduplication store on the database
+func NewDedupStore(db *Database) *DedupStore {
+	return &DedupStore{db: db, now: time.Now}
+}
+
+// Claim records the key until the ttl elapses, returning false if it is already recorded.
+// The key is claimed by a single upsert: an expired key not yet removed by the TTL monitor
+// is updated, while an unexpired one makes the upsert fail with a duplicate key error.
+func (s *DedupStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ctx, cancel := s.db.withTimeout(ctx)
+	defer cancel()
+
+	now := s.now()
+	filter := bson.M{"_id": key, "expires_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"expires_at": now.Add(ttl)}}
+	_, err := s.db.collection(dedupCollection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error claiming dedup key: %w", err)
+	}
+	return true, nil
+}
+
+// Release forgets the key
+func (s *DedupStore) Release(ctx context.Context, key string) error {
+	ctx, cancel := s.db.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.collection(dedupCollection).DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("error releasing dedup key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// faqDocument is the stored form of a domain.FAQEntry, keyed by its ID
+type faqDocument struct {
+	ID          string    `bson:"_id"`
+	Category    string    `bson:"category,omitempty"`
+	Question    string    `bson:"question"`
+	Answer      string    `bson:"answer"`
+	Keywords    []string  `bson:"keywords,omitempty"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+	SeedVersion string    `bson:"seed_version,omitempty"`
+}
+
+// FAQRepository is a MongoDB implementation of ports.FAQRepository
+type FAQRepository struct {
+	db *Database
+}
+
+var _ ports.FAQRepository = (*FAQRepository)(nil)
+
+// NewFAQRepository creates a FAQ repository on the database
+func NewFAQRepository(db *Database) *FAQRepository {
+	return &FAQRepository{db: db}
+}
+
+// FindByID returns the entry stored with the given ID
+func (r *FAQRepository) FindByID(ctx context.Context, id string) (*domain.FAQEntry, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	var doc faqDocument
+	err := r.db.collection(faqCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding FAQ entry: %w", err)
+	}
+
+	entry := doc.toEntry()
+	return &entry, nil
+}
+
+// Save stores the entry, replacing any previous version
+func (r *FAQRepository) Save(ctx context.Context, entry *domain.FAQEntry) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	doc := faqDocument{
+		ID:          entry.ID,
+		Category:    entry.Category,
+		Question:    entry.Question,
+		Answer:      entry.Answer,
+		Keywords:    entry.Keywords,
+		UpdatedAt:   entry.UpdatedAt,
+		SeedVersion: entry.SeedVersion,
+	}
+	_, err := r.db.collection(faqCollection).ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving FAQ entry: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the entry stored with the given ID
+func (r *FAQRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.collection(faqCollection).DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("error deleting FAQ entry: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// List returns every entry ordered by ID
+func (r *FAQRepository) List(ctx context.Context) ([]domain.FAQEntry, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.db.collection(faqCollection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding FAQ entries: %w", err)
+	}
+
+	var docs []faqDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("error decoding FAQ entries: %w", err)
+	}
+
+	entries := make([]domain.FAQEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = doc.toEntry()
+	}
+	return entries, nil
+}
+
+// toEntry converts the document to a domain.FAQEntry
+func (doc faqDocument) toEntry() domain.FAQEntry {
+	return domain.FAQEntry{
+		ID:          doc.ID,
+		Category:    doc.Category,
+		Question:    doc.Question,
+		Answer:      doc.Answer,
+		Keywords:    doc.Keywords,
+		UpdatedAt:   doc.UpdatedAt,
+		SeedVersion: doc.SeedVersion,
+	}
+}
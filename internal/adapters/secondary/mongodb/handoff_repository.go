@@ -0,0 +1,146 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// handoffDocument is the stored form of a domain.Handoff, keyed by phone number
+type handoffDocument struct {
+	Phone         string    `bson:"_id"`
+	Name          string    `bson:"name,omitempty"`
+	Reason        string    `bson:"reason"`
+	Status        string    `bson:"status"`
+	Agent         string    `bson:"agent,omitempty"`
+	OpenedAt      time.Time `bson:"opened_at"`
+	LastMessageAt time.Time `bson:"last_message_at"`
+	LastReplyAt   time.Time `bson:"last_reply_at,omitempty"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+	ClosedAt      time.Time `bson:"closed_at,omitempty"`
+	Version       int       `bson:"version"`
+}
+
+// HandoffRepository is a MongoDB implementation of ports.HandoffRepository
+type HandoffRepository struct {
+	db *Database
+}
+
+var _ ports.HandoffRepository = (*HandoffRepository)(nil)
+
+// NewHandoffRepository creates a handoff repository on the database
+func NewHandoffRepository(db *Database) *HandoffRepository {
+	return &HandoffRepository{db: db}
+}
+
+// FindByPhone returns the handoff stored for the given phone number
+func (r *HandoffRepository) FindByPhone(ctx context.Context, phone string) (*domain.Handoff, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	var doc handoffDocument
+	err := r.db.collection(handoffsCollection).FindOne(ctx, bson.M{"_id": phone}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding handoff: %w", err)
+	}
+
+	handoff := doc.toHandoff()
+	return &handoff, nil
+}
+
+// Save stores the handoff, replacing the previous version unless it was saved since the
+// handoff was loaded. The replacement is an upsert filtered on the loaded version: when
+// another version is stored, the upsert fails with a duplicate key error.
+func (r *HandoffRepository) Save(ctx context.Context, handoff *domain.Handoff) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	doc := handoffDocument{
+		Phone:         handoff.Phone,
+		Name:          handoff.Name,
+		Reason:        string(handoff.Reason),
+		Status:        string(handoff.Status),
+		Agent:         handoff.Agent,
+		OpenedAt:      handoff.OpenedAt,
+		LastMessageAt: handoff.LastMessageAt,
+		LastReplyAt:   handoff.LastReplyAt,
+		UpdatedAt:     handoff.UpdatedAt,
+		ClosedAt:      handoff.ClosedAt,
+		Version:       handoff.Version + 1,
+	}
+	filter := bson.M{"_id": doc.Phone, "version": handoff.Version}
+	if handoff.Version == 0 {
+		// Handoffs stored before they had a version have no version field
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	_, err := r.db.collection(handoffsCollection).ReplaceOne(ctx, filter, doc, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: handoff %s was saved since version %d", domain.ErrConflict, handoff.Phone, handoff.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("error saving handoff: %w", err)
+	}
+	handoff.Version = doc.Version
+	return nil
+}
+
+// List returns the handoffs selected by the filter ordered by opening time, the oldest first.
+// Handoffs opened at the same time are ordered by phone number.
+func (r *HandoffRepository) List(ctx context.Context, filter domain.HandoffFilter) ([]domain.Handoff, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	query := bson.M{"status": bson.M{"$ne": string(domain.HandoffClosed)}}
+	if filter.Status != "" {
+		query["status"] = string(filter.Status)
+	}
+	if filter.Agent != "" {
+		query["agent"] = filter.Agent
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "opened_at", Value: 1}, {Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := r.db.collection(handoffsCollection).Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding handoffs: %w", err)
+	}
+
+	var docs []handoffDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("error decoding handoffs: %w", err)
+	}
+
+	handoffs := make([]domain.Handoff, len(docs))
+	for i, doc := range docs {
+		handoffs[i] = doc.toHandoff()
+	}
+	return handoffs, nil
+}
+
+// toHandoff converts the document to a domain.Handoff
+func (doc handoffDocument) toHandoff() domain.Handoff {
+	return domain.Handoff{
+		Phone:         doc.Phone,
+		Name:          doc.Name,
+		Reason:        domain.HandoffReason(doc.Reason),
+		Status:        domain.HandoffStatus(doc.Status),
+		Agent:         doc.Agent,
+		OpenedAt:      doc.OpenedAt,
+		LastMessageAt: doc.LastMessageAt,
+		LastReplyAt:   doc.LastReplyAt,
+		UpdatedAt:     doc.UpdatedAt,
+		ClosedAt:      doc.ClosedAt,
+		Version:       doc.Version,
+	}
+}
@@ -0,0 +1,147 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// leadDocument is the stored form of a domain.Lead, keyed by phone number
+type leadDocument struct {
+	Phone            string    `bson:"_id"`
+	Name             string    `bson:"name,omitempty"`
+	HasCRM           bool      `bson:"has_crm"`
+	CRMNumber        string    `bson:"crm_number,omitempty"`
+	CRMState         string    `bson:"crm_state,omitempty"`
+	CRMSuffix        string    `bson:"crm_suffix,omitempty"`
+	CRMVerified      bool      `bson:"crm_verified"`
+	Specialty        string    `bson:"specialty,omitempty"`
+	State            string    `bson:"state,omitempty"`
+	Municipality     string    `bson:"municipality,omitempty"`
+	MunicipalityCode string    `bson:"municipality_code,omitempty"`
+	Source           string    `bson:"source"`
+	Stage            string    `bson:"stage"`
+	CreatedAt        time.Time `bson:"created_at"`
+	UpdatedAt        time.Time `bson:"updated_at"`
+	QualifiedAt      time.Time `bson:"qualified_at,omitempty"`
+}
+
+// LeadRepository is a MongoDB implementation of ports.LeadRepository
+type LeadRepository struct {
+	db *Database
+}
+
+var _ ports.LeadRepository = (*LeadRepository)(nil)
+
+// NewLeadRepository creates a lead repository on the database
+func NewLeadRepository(db *Database) *LeadRepository {
+	return &LeadRepository{db: db}
+}
+
+// FindByPhone returns the lead stored for the given phone number
+func (r *LeadRepository) FindByPhone(ctx context.Context, phone string) (*domain.Lead, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	var doc leadDocument
+	err := r.db.collection(leadsCollection).FindOne(ctx, bson.M{"_id": phone}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding lead: %w", err)
+	}
+
+	lead := doc.toLead()
+	return &lead, nil
+}
+
+// Save stores the lead, replacing any previous version
+func (r *LeadRepository) Save(ctx context.Context, lead *domain.Lead) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	doc := leadDocument{
+		Phone:            lead.Phone,
+		Name:             lead.Name,
+		HasCRM:           lead.HasCRM,
+		CRMNumber:        lead.CRMNumber,
+		CRMState:         lead.CRMState,
+		CRMSuffix:        lead.CRMSuffix,
+		CRMVerified:      lead.CRMVerified,
+		Specialty:        lead.Specialty,
+		State:            lead.State,
+		Municipality:     lead.Municipality,
+		MunicipalityCode: lead.MunicipalityCode,
+		Source:           string(lead.Source),
+		Stage:            string(lead.Stage),
+		CreatedAt:        lead.CreatedAt,
+		UpdatedAt:        lead.UpdatedAt,
+		QualifiedAt:      lead.QualifiedAt,
+	}
+	_, err := r.db.collection(leadsCollection).ReplaceOne(ctx, bson.M{"_id": doc.Phone}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving lead: %w", err)
+	}
+	return nil
+}
+
+// List returns the leads selected by the filter ordered by update time, the most recent first.
+// Leads updated at the same time are ordered by phone number.
+func (r *LeadRepository) List(ctx context.Context, filter domain.LeadFilter) ([]domain.Lead, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	query := bson.M{}
+	if filter.Stage != "" {
+		query["stage"] = string(filter.Stage)
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := r.db.collection(leadsCollection).Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding leads: %w", err)
+	}
+
+	var docs []leadDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("error decoding leads: %w", err)
+	}
+
+	leads := make([]domain.Lead, len(docs))
+	for i, doc := range docs {
+		leads[i] = doc.toLead()
+	}
+	return leads, nil
+}
+
+// toLead converts the document to a domain.Lead
+func (doc leadDocument) toLead() domain.Lead {
+	return domain.Lead{
+		Phone:            doc.Phone,
+		Name:             doc.Name,
+		HasCRM:           doc.HasCRM,
+		CRMNumber:        doc.CRMNumber,
+		CRMState:         doc.CRMState,
+		CRMSuffix:        doc.CRMSuffix,
+		CRMVerified:      doc.CRMVerified,
+		Specialty:        doc.Specialty,
+		State:            doc.State,
+		Municipality:     doc.Municipality,
+		MunicipalityCode: doc.MunicipalityCode,
+		Source:           domain.LeadSource(doc.Source),
+		Stage:            domain.LeadStage(doc.Stage),
+		CreatedAt:        doc.CreatedAt,
+		UpdatedAt:        doc.UpdatedAt,
+		QualifiedAt:      doc.QualifiedAt,
+	}
+}
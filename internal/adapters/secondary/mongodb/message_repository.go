@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// messageDocument is the stored form of a domain.MessageRecord, keyed by WhatsApp message ID
+type messageDocument struct {
+	ID        string    `bson:"_id"`
+	Phone     string    `bson:"phone"`
+	Direction string    `bson:"direction"`
+	Type      string    `bson:"type"`
+	Text      string    `bson:"text,omitempty"`
+	Timestamp time.Time `bson:"timestamp"`
+}
+
+// MessageRepository is a MongoDB implementation of ports.MessageRepository
+type MessageRepository struct {
+	db *Database
+}
+
+var _ ports.MessageRepository = (*MessageRepository)(nil)
+
+// NewMessageRepository creates a message repository on the database
+func NewMessageRepository(db *Database) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+// Save stores the message, replacing any message stored with the same ID
+func (r *MessageRepository) Save(ctx context.Context, message domain.MessageRecord) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	doc := messageDocument{
+		ID:        message.ID,
+		Phone:     message.Phone,
+		Direction: string(message.Direction),
+		Type:      string(message.Type),
+		Text:      message.Text,
+		Timestamp: message.Timestamp,
+	}
+	_, err := r.db.collection(messagesCollection).ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving message: %w", err)
+	}
+	return nil
+}
+
+// FindByPhone returns the last limit messages of the contact ordered by timestamp and ID,
+// or all of them when limit is not positive
+func (r *MessageRepository) FindByPhone(ctx context.Context, phone string, limit int) ([]domain.MessageRecord, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	cursor, err := r.db.collection(messagesCollection).Find(ctx, bson.M{"phone": phone}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding messages: %w", err)
+	}
+
+	var docs []messageDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("error decoding messages: %w", err)
+	}
+
+	// The newest messages are fetched first, so the limit keeps the latest ones
+	messages := make([]domain.MessageRecord, len(docs))
+	for i, doc := range docs {
+		messages[len(docs)-1-i] = domain.MessageRecord{
+			ID:        doc.ID,
+			Phone:     doc.Phone,
+			Direction: domain.MessageDirection(doc.Direction),
+			Type:      domain.MessageType(doc.Type),
+			Text:      doc.Text,
+			Timestamp: doc.Timestamp,
+		}
+	}
+	return messages, nil
+}
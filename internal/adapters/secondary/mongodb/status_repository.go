@@ -0,0 +1,119 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// statusDocument is the stored form of a domain.StatusEvent, keyed by message ID and status so
+// that a status notified again is stored once
+type statusDocument struct {
+	ID                  string                `bson:"_id"`
+	MessageID           string                `bson:"message_id"`
+	RecipientID         string                `bson:"recipient_id,omitempty"`
+	Status              string                `bson:"status"`
+	Timestamp           time.Time             `bson:"timestamp"`
+	ConversationID      string                `bson:"conversation_id,omitempty"`
+	ConversationOrigin  string                `bson:"conversation_origin,omitempty"`
+	ConversationExpires time.Time             `bson:"conversation_expires"`
+	Billable            bool                  `bson:"billable"`
+	PricingModel        string                `bson:"pricing_model,omitempty"`
+	PricingCategory     string                `bson:"pricing_category,omitempty"`
+	Errors              []statusErrorDocument `bson:"errors,omitempty"`
+}
+
+// statusErrorDocument is the stored form of a domain.StatusError
+type statusErrorDocument struct {
+	Code    int    `bson:"code"`
+	Title   string `bson:"title,omitempty"`
+	Message string `bson:"message,omitempty"`
+	Details string `bson:"details,omitempty"`
+}
+
+// StatusRepository is a MongoDB implementation of ports.StatusRepository
+type StatusRepository struct {
+	db *Database
+}
+
+var _ ports.StatusRepository = (*StatusRepository)(nil)
+
+// NewStatusRepository creates a status repository on the database
+func NewStatusRepository(db *Database) *StatusRepository {
+	return &StatusRepository{db: db}
+}
+
+// AppendStatus stores the event unless the status of the message was already stored
+func (r *StatusRepository) AppendStatus(ctx context.Context, event domain.StatusEvent) error {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	doc := statusDocument{
+		ID:                  event.MessageID + ":" + string(event.Status),
+		MessageID:           event.MessageID,
+		RecipientID:         event.RecipientID,
+		Status:              string(event.Status),
+		Timestamp:           event.Timestamp,
+		ConversationID:      event.ConversationID,
+		ConversationOrigin:  event.ConversationOrigin,
+		ConversationExpires: event.ConversationExpires,
+		Billable:            event.Billable,
+		PricingModel:        event.PricingModel,
+		PricingCategory:     event.PricingCategory,
+	}
+	for _, statusErr := range event.Errors {
+		doc.Errors = append(doc.Errors, statusErrorDocument(statusErr))
+	}
+
+	_, err := r.db.collection(statusesCollection).InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error saving status: %w", err)
+	}
+	return nil
+}
+
+// FindStatuses returns the events of the message ordered by timestamp
+func (r *StatusRepository) FindStatuses(ctx context.Context, messageID string) ([]domain.StatusEvent, error) {
+	ctx, cancel := r.db.withTimeout(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+	cursor, err := r.db.collection(statusesCollection).Find(ctx, bson.M{"message_id": messageID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding statuses: %w", err)
+	}
+
+	var docs []statusDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("error decoding statuses: %w", err)
+	}
+
+	events := make([]domain.StatusEvent, len(docs))
+	for i, doc := range docs {
+		events[i] = domain.StatusEvent{
+			MessageID:           doc.MessageID,
+			RecipientID:         doc.RecipientID,
+			Status:              domain.DeliveryStatus(doc.Status),
+			Timestamp:           doc.Timestamp,
+			ConversationID:      doc.ConversationID,
+			ConversationOrigin:  doc.ConversationOrigin,
+			ConversationExpires: doc.ConversationExpires,
+			Billable:            doc.Billable,
+			PricingModel:        doc.PricingModel,
+			PricingCategory:     doc.PricingCategory,
+		}
+		for _, statusErr := range doc.Errors {
+			events[i].Errors = append(events[i].Errors, domain.StatusError(statusErr))
+		}
+	}
+	return events, nil
+}
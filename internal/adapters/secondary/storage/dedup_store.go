@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// minCompactSize is the number of records below which the file is not compacted
+const minCompactSize = 1024
+
+// dedupRecord is a line of the deduplication file. Released keys are written without expiry.
+type dedupRecord struct {
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+}
+
+// FileDedupStore is an implementation of ports.DedupStore that keeps the keys in memory
+// and appends every change to a file, so the keys survive restarts. The file is rewritten
+// without the expired and released keys when it grows past twice the live keys.
+type FileDedupStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	expires map[string]time.Time
+	records int
+	now     func() time.Time
+}
+
+var _ ports.DedupStore = (*FileDedupStore)(nil)
+
+// NewFileDedupStore opens the deduplication file at path, creating it if needed
+func NewFileDedupStore(path string) (*FileDedupStore, error) {
+	return openFileDedupStore(path, time.Now)
+}
+
+// openFileDedupStore loads the keys of the file that are still valid at now() and compacts it
+func openFileDedupStore(path string, now func() time.Time) (*FileDedupStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("error creating dedup directory: %w", err)
+	}
+
+	s := &FileDedupStore{
+		path:    path,
+		expires: make(map[string]time.Time),
+		now:     now,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Claim records the key until the ttl elapses, returning false if it is already recorded
+func (s *FileDedupStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if expires, ok := s.expires[key]; ok && now.Before(expires) {
+		return false, nil
+	}
+
+	record := dedupRecord{Key: key, Expires: now.Add(ttl)}
+	if err := s.append(record); err != nil {
+		return false, err
+	}
+	s.expires[key] = record.Expires
+	return true, s.compactIfNeeded()
+}
+
+// Release forgets the key
+func (s *FileDedupStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.expires[key]; !ok {
+		return nil
+	}
+	if err := s.append(dedupRecord{Key: key}); err != nil {
+		return err
+	}
+	delete(s.expires, key)
+	return s.compactIfNeeded()
+}
+
+// Close closes the deduplication file
+func (s *FileDedupStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// load replays the records of the file. A truncated last line, left by a crash while
+// writing, is ignored.
+func (s *FileDedupStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening dedup file: %w", err)
+	}
+	defer file.Close()
+
+	now := s.now()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record dedupRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if record.Expires.IsZero() || !now.Before(record.Expires) {
+			delete(s.expires, record.Key)
+			continue
+		}
+		s.expires[record.Key] = record.Expires
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading dedup file: %w", err)
+	}
+	return nil
+}
+
+// append writes the record at the end of the file
+func (s *FileDedupStore) append(record dedupRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding dedup record: %w", err)
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing dedup file: %w", err)
+	}
+	s.records++
+	return nil
+}
+
+// compactIfNeeded compacts the file once it has twice as many records as live keys
+func (s *FileDedupStore) compactIfNeeded() error {
+	if s.records < minCompactSize || s.records < 2*len(s.expires) {
+		return nil
+	}
+	return s.compact()
+}
+
+// compact rewrites the file with the keys that did not expire yet and reopens it for appending.
+// The new file is written to a temporary file first so a crash never loses the keys.
+func (s *FileDedupStore) compact() error {
+	now := s.now()
+	for key, expires := range s.expires {
+		if !now.Before(expires) {
+			delete(s.expires, key)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".dedup-*")
+	if err != nil {
+		return fmt.Errorf("error creating dedup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for key, expires := range s.expires {
+		if err := encoder.Encode(dedupRecord{Key: key, Expires: expires}); err != nil {
+			tmp.Close()
+			return fmt.Errorf("error writing dedup file: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing dedup file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing dedup file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error moving dedup file: %w", err)
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return fmt.Errorf("error opening dedup file: %w", err)
+	}
+	s.file = file
+	s.records = len(s.expires)
+	return nil
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/porttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileDedupStoreContract(t *testing.T) {
+	porttest.TestDedupStore(t, func(t *testing.T) ports.DedupStore {
+		store, err := NewFileDedupStore(filepath.Join(t.TempDir(), "dedup.log"))
+		assert.NoError(t, err)
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+func TestFileDedupStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func(at time.Time) func() time.Time {
+		return func() time.Time { return at }
+	}
+
+	t.Run("should claim a key only once", func(t *testing.T) {
+		// arrange
+		store, err := NewFileDedupStore(filepath.Join(t.TempDir(), "dedup.log"))
+		assert.NoError(t, err)
+		defer store.Close()
+
+		// act
+		first, _ := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		second, _ := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// assert
+		assert.True(t, first)
+		assert.False(t, second)
+	})
+
+	t.Run("should remember the keys after reopening", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "data", "dedup.log")
+		store, err := openFileDedupStore(path, clock(now))
+		assert.NoError(t, err)
+		store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		store.Claim(context.Background(), "message:wamid.2", time.Hour)
+		store.Release(context.Background(), "message:wamid.2")
+		store.Close()
+
+		// act
+		reopened, err := openFileDedupStore(path, clock(now.Add(time.Minute)))
+		assert.NoError(t, err)
+		defer reopened.Close()
+		first, _ := reopened.Claim(context.Background(), "message:wamid.1", time.Hour)
+		second, _ := reopened.Claim(context.Background(), "message:wamid.2", time.Hour)
+
+		// assert
+		assert.False(t, first)
+		assert.True(t, second)
+	})
+
+	t.Run("should forget expired keys when reopening", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "dedup.log")
+		store, err := openFileDedupStore(path, clock(now))
+		assert.NoError(t, err)
+		store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		store.Close()
+
+		// act
+		reopened, err := openFileDedupStore(path, clock(now.Add(2*time.Hour)))
+		assert.NoError(t, err)
+		defer reopened.Close()
+
+		// assert
+		assert.Empty(t, reopened.expires)
+		content, _ := os.ReadFile(path)
+		assert.Empty(t, content)
+	})
+
+	t.Run("should ignore a truncated last record", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "dedup.log")
+		expires := now.Add(time.Hour).Format(time.RFC3339)
+		content := fmt.Sprintf("{\"key\":\"message:wamid.1\",\"expires\":%q}\n{\"key\":\"message:wam", expires)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o640))
+
+		// act
+		store, err := openFileDedupStore(path, clock(now))
+		assert.NoError(t, err)
+		defer store.Close()
+
+		// assert
+		assert.Len(t, store.expires, 1)
+		claimed, _ := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		assert.False(t, claimed)
+	})
+
+	t.Run("should compact the file when it grows", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "dedup.log")
+		store, err := openFileDedupStore(path, clock(now))
+		assert.NoError(t, err)
+		defer store.Close()
+
+		// act
+		for i := 0; i < minCompactSize; i++ {
+			key := fmt.Sprintf("message:%d", i)
+			store.Claim(context.Background(), key, time.Hour)
+			store.Release(context.Background(), key)
+		}
+		store.Claim(context.Background(), "message:kept", time.Hour)
+
+		// assert
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		assert.Less(t, len(lines), minCompactSize)
+		assert.Contains(t, string(content), "message:kept")
+	})
+}
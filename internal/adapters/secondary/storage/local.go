@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+)
+
+// LocalMediaStore is an implementation of ports.MediaStore backed by the local filesystem
+type LocalMediaStore struct {
+	baseDir string
+}
+
+var _ ports.MediaStore = (*LocalMediaStore)(nil)
+
+// NewLocalMediaStore creates a media store rooted at baseDir
+func NewLocalMediaStore(baseDir string) *LocalMediaStore {
+	return &LocalMediaStore{baseDir: baseDir}
+}
+
+// Save writes the content to a file under the base directory and returns its relative path.
+// The content is written to a temporary file first so readers never see partial files.
+func (s *LocalMediaStore) Save(ctx context.Context, name string, content io.Reader) (string, error) {
+	path, err := s.resolve(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("error creating media directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("error creating media file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("error writing media file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("error writing media file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("error moving media file: %w", err)
+	}
+
+	return filepath.ToSlash(filepath.Clean(name)), nil
+}
+
+// Open opens a file previously saved under the base directory
+func (s *LocalMediaStore) Open(ctx context.Context, location string) (io.ReadCloser, error) {
+	path, err := s.resolve(location)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening media file: %w", err)
+	}
+	return file, nil
+}
+
+// resolve maps a name to a path inside the base directory, rejecting names that escape it
+func (s *LocalMediaStore) resolve(name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if name == "" || filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid media name %q", name)
+	}
+	return filepath.Join(s.baseDir, clean), nil
+}
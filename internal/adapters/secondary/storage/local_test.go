@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalMediaStore(t *testing.T) {
+	t.Run("should save and open a file", func(t *testing.T) {
+		// arrange
+		dir := t.TempDir()
+		store := NewLocalMediaStore(dir)
+
+		// act
+		location, err := store.Save(context.Background(), "554499887766/wamid.1.pdf", strings.NewReader("content"))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "554499887766/wamid.1.pdf", location)
+
+		saved, _ := os.ReadFile(filepath.Join(dir, "554499887766", "wamid.1.pdf"))
+		assert.Equal(t, "content", string(saved))
+
+		file, err := store.Open(context.Background(), location)
+		assert.NoError(t, err)
+		defer file.Close()
+		opened, _ := io.ReadAll(file)
+		assert.Equal(t, "content", string(opened))
+	})
+
+	t.Run("should replace an existing file", func(t *testing.T) {
+		// arrange
+		store := NewLocalMediaStore(t.TempDir())
+		store.Save(context.Background(), "a.txt", strings.NewReader("old"))
+
+		// act
+		_, err := store.Save(context.Background(), "a.txt", strings.NewReader("new"))
+
+		// assert
+		assert.NoError(t, err)
+		file, _ := store.Open(context.Background(), "a.txt")
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		assert.Equal(t, "new", string(content))
+	})
+
+	t.Run("should reject names escaping the base directory", func(t *testing.T) {
+		// arrange
+		store := NewLocalMediaStore(t.TempDir())
+
+		for _, name := range []string{"", "../secret", "/etc/passwd", "a/../../b"} {
+			// act
+			_, err := store.Save(context.Background(), name, strings.NewReader("x"))
+
+			// assert
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("should return not found for missing files", func(t *testing.T) {
+		// arrange
+		store := NewLocalMediaStore(t.TempDir())
+
+		// act
+		_, err := store.Open(context.Background(), "missing.pdf")
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
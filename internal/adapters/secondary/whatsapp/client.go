@@ -1,14 +1,36 @@
 package whatsapp
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
 	"github.com/2rprbm/conta-med-backend/pkg/logger"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader is the header carrying the ID of the inbound request that caused an outbound call
+const RequestIDHeader = "X-Request-ID"
+
+// requestTag returns the request ID of the context formatted for log messages
+func requestTag(ctx context.Context) string {
+	if id := middleware.GetReqID(ctx); id != "" {
+		return " [request " + id + "]"
+	}
+	return ""
+}
+
+// Client implements the outbound messaging and media ports
+var (
+	_ ports.MessageSender   = (*Client)(nil)
+	_ ports.MediaDownloader = (*Client)(nil)
+	_ ports.MediaUploader   = (*Client)(nil)
 )
 
 // Client represents a WhatsApp API client
@@ -16,7 +38,12 @@ type Client struct {
 	Config     *config.Config
 	Logger     logger.Logger
 	HttpClient *http.Client
-	APIURL     string // URL template for API endpoints
+	// DownloadClient downloads media files, which can take much longer than the API calls
+	DownloadClient *http.Client
+	APIURL         string // URL template for API endpoints
+	Retry          RetryPolicy
+	sleep          func(context.Context, time.Duration) error
+	random         func() float64
 }
 
 // NewClient creates a new WhatsApp API client
@@ -27,7 +54,13 @@ func NewClient(cfg *config.Config, log logger.Logger) *Client {
 		HttpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		DownloadClient: &http.Client{
+			Timeout: DownloadTimeout,
+		},
 		APIURL: "https://graph.facebook.com/v18.0/%s/messages",
+		Retry:  NewRetryPolicy(cfg.WhatsApp),
+		sleep:  sleepContext,
+		random: defaultRandom,
 	}
 }
 
@@ -42,12 +75,27 @@ type TextMessage struct {
 	} `json:"text"`
 }
 
-// SendTextMessage sends a text message to a WhatsApp user
-func (c *Client) SendTextMessage(to, message string) error {
-	if c.Config.WhatsApp.PhoneNumberID == "" {
-		return fmt.Errorf("phone number ID not configured")
-	}
+// SendResponse represents the response of the messages endpoint
+type SendResponse struct {
+	MessagingProduct string `json:"messaging_product"`
+	Contacts         []struct {
+		Input string `json:"input"`
+		WaID  string `json:"wa_id"`
+	} `json:"contacts"`
+	Messages []struct {
+		ID            string `json:"id"`
+		MessageStatus string `json:"message_status,omitempty"`
+	} `json:"messages"`
+}
 
+// SendTextMessage sends a text message to a WhatsApp user.
+// It uses a background context, use SendTextMessageContext to control cancellation and deadlines.
+func (c *Client) SendTextMessage(to, message string) (*domain.SendResult, error) {
+	return c.SendTextMessageContext(context.Background(), to, message)
+}
+
+// SendTextMessageContext sends a text message to a WhatsApp user, aborting when the context is done
+func (c *Client) SendTextMessageContext(ctx context.Context, to, message string) (*domain.SendResult, error) {
 	// Create message payload
 	payload := TextMessage{
 		MessagingProduct: "whatsapp",
@@ -57,42 +105,59 @@ func (c *Client) SendTextMessage(to, message string) error {
 	}
 	payload.Text.Body = message
 
-	// Convert to JSON
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("error marshaling message: %w", err)
-	}
+	c.Logger.Debug("Sending WhatsApp message to %s%s: %s", to, requestTag(ctx), message)
+	return c.send(ctx, to, payload)
+}
 
-	// Create API URL
-	url := fmt.Sprintf(c.APIURL, c.Config.WhatsApp.PhoneNumberID)
+// send posts a message payload to the messages endpoint of the configured phone number
+// and returns the ID assigned to the message by WhatsApp
+func (c *Client) send(ctx context.Context, to string, payload interface{}) (*domain.SendResult, error) {
+	if c.Config.WhatsApp.PhoneNumberID == "" {
+		return nil, fmt.Errorf("phone number ID not configured")
+	}
 
-	// Create request
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	// Convert to JSON
+	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+		return nil, fmt.Errorf("error marshaling message: %w", err)
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Config.WhatsApp.AccessToken)
-
 	// Send request
-	c.Logger.Debug("Sending WhatsApp message to %s: %s", to, message)
-	resp, err := c.HttpClient.Do(req)
+	resp, err := c.do(ctx, request{
+		method:      "POST",
+		url:         fmt.Sprintf(c.APIURL, c.Config.WhatsApp.PhoneNumberID),
+		contentType: "application/json",
+		body:        jsonPayload,
+		failure:     "error sending message",
+	})
 	if err != nil {
-		return fmt.Errorf("error sending message: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var errorResp map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err != nil {
-			return fmt.Errorf("API error: %d", resp.StatusCode)
-		}
-		return fmt.Errorf("API error: %v", errorResp)
+	// Decode the message ID
+	var sendResp SendResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sendResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+	if len(sendResp.Messages) == 0 || sendResp.Messages[0].ID == "" {
+		return nil, fmt.Errorf("response has no message ID")
+	}
+
+	result := &domain.SendResult{
+		MessageID:     sendResp.Messages[0].ID,
+		MessageStatus: sendResp.Messages[0].MessageStatus,
 	}
+	if len(sendResp.Contacts) > 0 {
+		result.Input = sendResp.Contacts[0].Input
+		result.WaID = sendResp.Contacts[0].WaID
+	}
+
+	c.Logger.Info("Message sent successfully to %s with ID %s%s", to, result.MessageID, requestTag(ctx))
+	return result, nil
+}
 
-	c.Logger.Info("Message sent successfully to %s", to)
-	return nil
+// graphURL builds the URL of a Graph API path using the base of APIURL
+func (c *Client) graphURL(path string) string {
+	return strings.TrimSuffix(c.APIURL, "/%s/messages") + "/" + path
 }
@@ -1,12 +1,16 @@
 package whatsapp
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,6 +53,9 @@ func (m *mockLogger) Fatal(format string, args ...interface{}) {
 	m.fatalMessages = append(m.fatalMessages, format)
 }
 
+// sendResponseBody is a successful response of the messages endpoint
+const sendResponseBody = `{"messaging_product":"whatsapp","contacts":[{"input":"554499887766","wa_id":"554499887766"}],"messages":[{"id":"wamid.out.1"}]}`
+
 func TestSendTextMessage(t *testing.T) {
 	t.Run("should send a text message successfully", func(t *testing.T) {
 		// arrange
@@ -72,7 +79,7 @@ func TestSendTextMessage(t *testing.T) {
 
 			// Return success response
 			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"success": true}`))
+			w.Write([]byte(sendResponseBody))
 		}))
 		defer server.Close()
 
@@ -92,10 +99,15 @@ func TestSendTextMessage(t *testing.T) {
 		client.APIURL = server.URL + "/%s/messages"
 
 		// act
-		err := client.SendTextMessage("554499887766", "Hello from test")
+		result, err := client.SendTextMessage("554499887766", "Hello from test")
 
 		// assert
 		assert.NoError(t, err)
+		assert.Equal(t, &domain.SendResult{
+			MessageID: "wamid.out.1",
+			Input:     "554499887766",
+			WaID:      "554499887766",
+		}, result)
 		assert.Contains(t, logger.debugMessages[0], "Sending WhatsApp message")
 		assert.Contains(t, logger.infoMessages[0], "Message sent successfully")
 	})
@@ -113,7 +125,7 @@ func TestSendTextMessage(t *testing.T) {
 		client := NewClient(cfg, logger)
 
 		// act
-		err := client.SendTextMessage("554499887766", "Hello from test")
+		_, err := client.SendTextMessage("554499887766", "Hello from test")
 
 		// assert
 		assert.Error(t, err)
@@ -146,10 +158,171 @@ func TestSendTextMessage(t *testing.T) {
 		client.APIURL = server.URL + "/%s/messages"
 
 		// act
-		err := client.SendTextMessage("554499887766", "Hello from test")
+		_, err := client.SendTextMessage("554499887766", "Hello from test")
 
 		// assert
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "API error")
 	})
 }
+
+func TestSendResult(t *testing.T) {
+	t.Run("should return the status of template messages", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"messaging_product":"whatsapp","contacts":[{"input":"+55 44 99887766","wa_id":"554499887766"}],` +
+				`"messages":[{"id":"wamid.out.2","message_status":"accepted"}]}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		result, err := client.SendTemplateMessage("+55 44 99887766", domain.TemplateMessage{Name: "hello_world"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "wamid.out.2", result.MessageID)
+		assert.Equal(t, "accepted", result.MessageStatus)
+		assert.Equal(t, "+55 44 99887766", result.Input)
+		assert.Equal(t, "554499887766", result.WaID)
+	})
+
+	t.Run("should return error when the response has no message ID", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success": true}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		result, err := client.SendTextMessage("554499887766", "Hello from test")
+
+		// assert
+		assert.Nil(t, result)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "response has no message ID")
+	})
+
+	t.Run("should return error when the response cannot be decoded", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`not json`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Hello from test")
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error decoding response")
+	})
+}
+
+func TestClientContext(t *testing.T) {
+	t.Run("should forward the request ID of the context", func(t *testing.T) {
+		// arrange
+		var requestID string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID = r.Header.Get(RequestIDHeader)
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		logger := newMockLogger()
+		client := newTestClient(server, logger)
+		ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "host/abc-000001")
+
+		// act
+		_, err := client.SendTextMessageContext(ctx, "554499887766", "Hello from test")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "host/abc-000001", requestID)
+	})
+
+	t.Run("should not set the request ID header without a request ID", func(t *testing.T) {
+		// arrange
+		headers := http.Header{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Hello from test")
+
+		// assert
+		assert.NoError(t, err)
+		assert.NotContains(t, headers, RequestIDHeader)
+	})
+
+	t.Run("should abort the request when the context is done", func(t *testing.T) {
+		// arrange
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		client := newTestClient(server, newMockLogger())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// act
+		_, err := client.SendTextMessageContext(ctx, "554499887766", "Hello from test")
+
+		// assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should not retry once the context is done", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		ctx, cancel := context.WithCancel(context.Background())
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			cancel()
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.GetMediaInfoContext(ctx, "media-1")
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should stop waiting for a retry when the context is done", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+		client.sleep = sleepContext
+		client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// act
+		_, err := client.GetMediaInfoContext(ctx, "media-1")
+
+		// assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
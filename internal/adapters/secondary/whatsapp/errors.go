@@ -0,0 +1,192 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// Graph API error codes the client reacts to
+const (
+	CodeAPIUnknown                   = 1
+	CodeAPIService                   = 2
+	CodeAPITooManyCalls              = 4
+	CodeAccessTokenExpired           = 190
+	CodeRateLimit                    = 80007
+	CodeThroughputRateLimit          = 130429
+	CodeExperimentRecipient          = 130472
+	CodeGenericError                 = 131000
+	CodeServiceUnavailable           = 131016
+	CodeRecipientIsSender            = 131021
+	CodeMessageUndeliverable         = 131026
+	CodeRecipientNotAllowed          = 131030
+	CodeReEngagementRequired         = 131047
+	CodeSpamRateLimit                = 131048
+	CodeMarketingOptOut              = 131050
+	CodePairRateLimit                = 131056
+	CodeServerTemporarilyUnavailable = 133004
+)
+
+// retryableCodes are rate limits and transient failures that may succeed later
+var retryableCodes = map[int]bool{
+	CodeAPIUnknown:                   true,
+	CodeAPIService:                   true,
+	CodeAPITooManyCalls:              true,
+	CodeRateLimit:                    true,
+	CodeThroughputRateLimit:          true,
+	CodeGenericError:                 true,
+	CodeServiceUnavailable:           true,
+	CodeSpamRateLimit:                true,
+	CodePairRateLimit:                true,
+	CodeServerTemporarilyUnavailable: true,
+}
+
+// throttlingCodes are the rate limits, returned when WhatsApp refused the request without processing it
+var throttlingCodes = map[int]bool{
+	CodeAPITooManyCalls:     true,
+	CodeRateLimit:           true,
+	CodeThroughputRateLimit: true,
+	CodePairRateLimit:       true,
+}
+
+// recipientCodes are failures caused by the recipient that will not succeed on retry
+var recipientCodes = map[int]bool{
+	CodeExperimentRecipient:  true,
+	CodeRecipientIsSender:    true,
+	CodeMessageUndeliverable: true,
+	CodeRecipientNotAllowed:  true,
+	CodeMarketingOptOut:      true,
+}
+
+// APIError is an error response of the Graph API
+type APIError struct {
+	StatusCode int
+	Code       int
+	Subcode    int
+	Type       string
+	Message    string
+	Details    string
+	FBTraceID  string
+	// RetryAfter is the wait requested by the Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+// graphErrorResponse represents the error body returned by the Graph API
+type graphErrorResponse struct {
+	Error struct {
+		Message      string `json:"message"`
+		Type         string `json:"type"`
+		Code         int    `json:"code"`
+		ErrorSubcode int    `json:"error_subcode"`
+		FBTraceID    string `json:"fbtrace_id"`
+		ErrorData    struct {
+			Details string `json:"details"`
+		} `json:"error_data"`
+	} `json:"error"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API error: status %d", e.StatusCode)
+	if e.Code != 0 {
+		fmt.Fprintf(&b, ", code %d", e.Code)
+	}
+	if e.Subcode != 0 {
+		fmt.Fprintf(&b, ", subcode %d", e.Subcode)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Details != "" {
+		fmt.Fprintf(&b, " (%s)", e.Details)
+	}
+	if e.FBTraceID != "" {
+		fmt.Fprintf(&b, " [fbtrace_id %s]", e.FBTraceID)
+	}
+	return b.String()
+}
+
+// IsRetryable reports whether the request may succeed if sent again later
+func (e *APIError) IsRetryable() bool {
+	if e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	return retryableCodes[e.Code]
+}
+
+// RequiresTemplate reports whether the recipient is outside the 24-hour customer service
+// window, so only a template message can reach them
+func (e *APIError) RequiresTemplate() bool {
+	return e.Code == CodeReEngagementRequired
+}
+
+// IsRecipientError reports whether the recipient can not receive the message, permanently
+func (e *APIError) IsRecipientError() bool {
+	return recipientCodes[e.Code]
+}
+
+// Is maps the error to the domain delivery errors, so the application can use errors.Is
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case domain.ErrTemporaryFailure:
+		return e.IsRetryable()
+	case domain.ErrTemplateRequired:
+		return e.RequiresTemplate()
+	case domain.ErrRecipientUnavailable:
+		return e.IsRecipientError()
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether err is a Graph API error that may succeed if retried
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsRetryable()
+}
+
+// RequiresTemplate reports whether err means that only a template can reach the recipient
+func RequiresTemplate(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.RequiresTemplate()
+}
+
+// IsRecipientError reports whether err is a permanent problem with the recipient
+func IsRecipientError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsRecipientError()
+}
+
+// checkResponse converts a non-2xx response into an *APIError
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var errorResp graphErrorResponse
+	if err := json.Unmarshal(body, &errorResp); err != nil || errorResp.Error.Message == "" && errorResp.Error.Code == 0 {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+		return apiErr
+	}
+
+	apiErr.Code = errorResp.Error.Code
+	apiErr.Subcode = errorResp.Error.ErrorSubcode
+	apiErr.Type = errorResp.Error.Type
+	apiErr.Message = errorResp.Error.Message
+	apiErr.Details = errorResp.Error.ErrorData.Details
+	apiErr.FBTraceID = errorResp.Error.FBTraceID
+	return apiErr
+}
@@ -0,0 +1,112 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	t.Run("should decode the Graph API error response", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"(#131047) Re-engagement message","type":"OAuthException","code":131047,` +
+				`"error_subcode":2494010,"error_data":{"messaging_product":"whatsapp","details":"More than 24 hours have passed"},` +
+				`"fbtrace_id":"AbCdEf"}}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, &APIError{
+			StatusCode: http.StatusBadRequest,
+			Code:       131047,
+			Subcode:    2494010,
+			Type:       "OAuthException",
+			Message:    "(#131047) Re-engagement message",
+			Details:    "More than 24 hours have passed",
+			FBTraceID:  "AbCdEf",
+		}, apiErr)
+		assert.Equal(t, "API error: status 400, code 131047, subcode 2494010: (#131047) Re-engagement message "+
+			"(More than 24 hours have passed) [fbtrace_id AbCdEf]", err.Error())
+	})
+
+	t.Run("should use the status text when the body is not a Graph error", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`<html>Bad Gateway</html>`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+		assert.Equal(t, "API error: status 502: Bad Gateway", err.Error())
+	})
+}
+
+func TestAPIErrorClassification(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              *APIError
+		retryable        bool
+		requiresTemplate bool
+		recipient        bool
+	}{
+		{name: "too many requests", err: &APIError{StatusCode: 429}, retryable: true},
+		{name: "internal server error", err: &APIError{StatusCode: 500}, retryable: true},
+		{name: "throughput rate limit", err: &APIError{StatusCode: 400, Code: CodeThroughputRateLimit}, retryable: true},
+		{name: "app rate limit", err: &APIError{StatusCode: 400, Code: CodeAPITooManyCalls}, retryable: true},
+		{name: "pair rate limit", err: &APIError{StatusCode: 400, Code: CodePairRateLimit}, retryable: true},
+		{name: "service unavailable", err: &APIError{StatusCode: 400, Code: CodeServiceUnavailable}, retryable: true},
+		{name: "re-engagement", err: &APIError{StatusCode: 400, Code: CodeReEngagementRequired}, requiresTemplate: true},
+		{name: "undeliverable", err: &APIError{StatusCode: 400, Code: CodeMessageUndeliverable}, recipient: true},
+		{name: "recipient not allowed", err: &APIError{StatusCode: 400, Code: CodeRecipientNotAllowed}, recipient: true},
+		{name: "expired token", err: &APIError{StatusCode: 401, Code: CodeAccessTokenExpired}},
+		{name: "invalid parameter", err: &APIError{StatusCode: 400, Code: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should classify "+tt.name, func(t *testing.T) {
+			// arrange
+			err := fmt.Errorf("error sending reply: %w", tt.err)
+
+			// act & assert
+			assert.Equal(t, tt.retryable, IsRetryable(err))
+			assert.Equal(t, tt.requiresTemplate, RequiresTemplate(err))
+			assert.Equal(t, tt.recipient, IsRecipientError(err))
+			assert.Equal(t, tt.retryable, errors.Is(err, domain.ErrTemporaryFailure))
+			assert.Equal(t, tt.requiresTemplate, errors.Is(err, domain.ErrTemplateRequired))
+			assert.Equal(t, tt.recipient, errors.Is(err, domain.ErrRecipientUnavailable))
+		})
+	}
+
+	t.Run("should not classify other errors", func(t *testing.T) {
+		// arrange
+		err := errors.New("connection refused")
+
+		// act & assert
+		assert.False(t, IsRetryable(err))
+		assert.False(t, RequiresTemplate(err))
+		assert.False(t, IsRecipientError(err))
+	})
+}
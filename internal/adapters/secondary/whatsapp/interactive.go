@@ -0,0 +1,273 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// Limits enforced by the WhatsApp Cloud API on interactive messages
+const (
+	MaxReplyButtons         = 3
+	MaxButtonTitleLength    = 20
+	MaxButtonIDLength       = 256
+	MaxButtonBodyLength     = 1024
+	MaxListBodyLength       = 4096
+	MaxHeaderLength         = 60
+	MaxFooterLength         = 60
+	MaxListButtonTextLength = 20
+	MaxListSections         = 10
+	MaxListRows             = 10
+	MaxSectionTitleLength   = 24
+	MaxRowTitleLength       = 24
+	MaxRowIDLength          = 200
+	MaxRowDescriptionLength = 72
+)
+
+// InteractiveMessage represents an interactive message to send
+type InteractiveMessage struct {
+	MessagingProduct string      `json:"messaging_product"`
+	RecipientType    string      `json:"recipient_type"`
+	To               string      `json:"to"`
+	Type             string      `json:"type"`
+	Interactive      Interactive `json:"interactive"`
+}
+
+// Interactive holds the content of an interactive message
+type Interactive struct {
+	Type   string             `json:"type"`
+	Header *InteractiveHeader `json:"header,omitempty"`
+	Body   InteractiveText    `json:"body"`
+	Footer *InteractiveText   `json:"footer,omitempty"`
+	Action InteractiveAction  `json:"action"`
+}
+
+// InteractiveHeader represents the header of an interactive message
+type InteractiveHeader struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// InteractiveText represents the body or footer of an interactive message
+type InteractiveText struct {
+	Text string `json:"text"`
+}
+
+// InteractiveAction holds the buttons or the list sections of an interactive message
+type InteractiveAction struct {
+	Buttons  []InteractiveButton  `json:"buttons,omitempty"`
+	Button   string               `json:"button,omitempty"`
+	Sections []InteractiveSection `json:"sections,omitempty"`
+}
+
+// InteractiveButton represents a reply button
+type InteractiveButton struct {
+	Type  string           `json:"type"`
+	Reply InteractiveReply `json:"reply"`
+}
+
+// InteractiveReply identifies a reply button
+type InteractiveReply struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// InteractiveSection represents a section of a list message
+type InteractiveSection struct {
+	Title string           `json:"title,omitempty"`
+	Rows  []InteractiveRow `json:"rows"`
+}
+
+// InteractiveRow represents a row of a list message
+type InteractiveRow struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// SendButtonMessage sends an interactive message with reply buttons to a WhatsApp user.
+// It uses a background context, use SendButtonMessageContext to control cancellation and deadlines.
+func (c *Client) SendButtonMessage(to string, message domain.ButtonMessage) (*domain.SendResult, error) {
+	return c.SendButtonMessageContext(context.Background(), to, message)
+}
+
+// SendButtonMessageContext sends an interactive message with reply buttons to a WhatsApp user, aborting when the context is done
+func (c *Client) SendButtonMessageContext(ctx context.Context, to string, message domain.ButtonMessage) (*domain.SendResult, error) {
+	if err := ValidateButtonMessage(message); err != nil {
+		return nil, err
+	}
+
+	buttons := make([]InteractiveButton, 0, len(message.Buttons))
+	for _, button := range message.Buttons {
+		buttons = append(buttons, InteractiveButton{
+			Type:  "reply",
+			Reply: InteractiveReply{ID: button.ID, Title: button.Title},
+		})
+	}
+
+	payload := newInteractiveMessage(to, Interactive{
+		Type:   "button",
+		Header: newHeader(message.Header),
+		Body:   InteractiveText{Text: message.Body},
+		Footer: newFooter(message.Footer),
+		Action: InteractiveAction{Buttons: buttons},
+	})
+
+	c.Logger.Debug("Sending WhatsApp button message to %s: %s", to, message.Body)
+	return c.send(ctx, to, payload)
+}
+
+// SendListMessage sends an interactive list message to a WhatsApp user.
+// It uses a background context, use SendListMessageContext to control cancellation and deadlines.
+func (c *Client) SendListMessage(to string, message domain.ListMessage) (*domain.SendResult, error) {
+	return c.SendListMessageContext(context.Background(), to, message)
+}
+
+// SendListMessageContext sends an interactive list message to a WhatsApp user, aborting when the context is done
+func (c *Client) SendListMessageContext(ctx context.Context, to string, message domain.ListMessage) (*domain.SendResult, error) {
+	if err := ValidateListMessage(message); err != nil {
+		return nil, err
+	}
+
+	sections := make([]InteractiveSection, 0, len(message.Sections))
+	for _, section := range message.Sections {
+		rows := make([]InteractiveRow, 0, len(section.Rows))
+		for _, row := range section.Rows {
+			rows = append(rows, InteractiveRow{ID: row.ID, Title: row.Title, Description: row.Description})
+		}
+		sections = append(sections, InteractiveSection{Title: section.Title, Rows: rows})
+	}
+
+	payload := newInteractiveMessage(to, Interactive{
+		Type:   "list",
+		Header: newHeader(message.Header),
+		Body:   InteractiveText{Text: message.Body},
+		Footer: newFooter(message.Footer),
+		Action: InteractiveAction{Button: message.ButtonText, Sections: sections},
+	})
+
+	c.Logger.Debug("Sending WhatsApp list message to %s: %s", to, message.Body)
+	return c.send(ctx, to, payload)
+}
+
+// ValidateButtonMessage checks the message against the limits of the WhatsApp Cloud API
+func ValidateButtonMessage(message domain.ButtonMessage) error {
+	if len(message.Buttons) == 0 || len(message.Buttons) > MaxReplyButtons {
+		return fmt.Errorf("invalid button message: must have between 1 and %d buttons, got %d", MaxReplyButtons, len(message.Buttons))
+	}
+	if err := validateTexts(message.Header, message.Body, message.Footer, MaxButtonBodyLength); err != nil {
+		return fmt.Errorf("invalid button message: %w", err)
+	}
+
+	ids := make(map[string]bool, len(message.Buttons))
+	for i, button := range message.Buttons {
+		if err := validateLength(fmt.Sprintf("button %d id", i+1), button.ID, MaxButtonIDLength, true); err != nil {
+			return fmt.Errorf("invalid button message: %w", err)
+		}
+		if err := validateLength(fmt.Sprintf("button %d title", i+1), button.Title, MaxButtonTitleLength, true); err != nil {
+			return fmt.Errorf("invalid button message: %w", err)
+		}
+		if ids[button.ID] {
+			return fmt.Errorf("invalid button message: duplicated button id %q", button.ID)
+		}
+		ids[button.ID] = true
+	}
+	return nil
+}
+
+// ValidateListMessage checks the message against the limits of the WhatsApp Cloud API
+func ValidateListMessage(message domain.ListMessage) error {
+	if err := validateTexts(message.Header, message.Body, message.Footer, MaxListBodyLength); err != nil {
+		return fmt.Errorf("invalid list message: %w", err)
+	}
+	if err := validateLength("button text", message.ButtonText, MaxListButtonTextLength, true); err != nil {
+		return fmt.Errorf("invalid list message: %w", err)
+	}
+	if len(message.Sections) == 0 || len(message.Sections) > MaxListSections {
+		return fmt.Errorf("invalid list message: must have between 1 and %d sections, got %d", MaxListSections, len(message.Sections))
+	}
+
+	rows := 0
+	ids := make(map[string]bool)
+	for i, section := range message.Sections {
+		// A title is required for every section when the list has more than one
+		if err := validateLength(fmt.Sprintf("section %d title", i+1), section.Title, MaxSectionTitleLength, len(message.Sections) > 1); err != nil {
+			return fmt.Errorf("invalid list message: %w", err)
+		}
+		if len(section.Rows) == 0 {
+			return fmt.Errorf("invalid list message: section %d has no rows", i+1)
+		}
+		for _, row := range section.Rows {
+			rows++
+			if err := validateLength(fmt.Sprintf("row %d id", rows), row.ID, MaxRowIDLength, true); err != nil {
+				return fmt.Errorf("invalid list message: %w", err)
+			}
+			if err := validateLength(fmt.Sprintf("row %d title", rows), row.Title, MaxRowTitleLength, true); err != nil {
+				return fmt.Errorf("invalid list message: %w", err)
+			}
+			if err := validateLength(fmt.Sprintf("row %d description", rows), row.Description, MaxRowDescriptionLength, false); err != nil {
+				return fmt.Errorf("invalid list message: %w", err)
+			}
+			if ids[row.ID] {
+				return fmt.Errorf("invalid list message: duplicated row id %q", row.ID)
+			}
+			ids[row.ID] = true
+		}
+	}
+	if rows > MaxListRows {
+		return fmt.Errorf("invalid list message: must have at most %d rows, got %d", MaxListRows, rows)
+	}
+	return nil
+}
+
+// validateTexts checks the header, body and footer shared by all interactive messages
+func validateTexts(header, body, footer string, maxBody int) error {
+	if err := validateLength("header", header, MaxHeaderLength, false); err != nil {
+		return err
+	}
+	if err := validateLength("body", body, maxBody, true); err != nil {
+		return err
+	}
+	return validateLength("footer", footer, MaxFooterLength, false)
+}
+
+// validateLength checks that value has at most max characters and is not empty when required
+func validateLength(field, value string, max int, required bool) error {
+	length := utf8.RuneCountInString(value)
+	if required && length == 0 {
+		return fmt.Errorf("%s is required", field)
+	}
+	if length > max {
+		return fmt.Errorf("%s exceeds %d characters", field, max)
+	}
+	return nil
+}
+
+// newInteractiveMessage wraps the interactive content in a message payload
+func newInteractiveMessage(to string, interactive Interactive) InteractiveMessage {
+	return InteractiveMessage{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               to,
+		Type:             "interactive",
+		Interactive:      interactive,
+	}
+}
+
+// newHeader returns a text header, or nil when the header is empty
+func newHeader(text string) *InteractiveHeader {
+	if text == "" {
+		return nil
+	}
+	return &InteractiveHeader{Type: "text", Text: text}
+}
+
+// newFooter returns a footer, or nil when the footer is empty
+func newFooter(text string) *InteractiveText {
+	if text == "" {
+		return nil
+	}
+	return &InteractiveText{Text: text}
+}
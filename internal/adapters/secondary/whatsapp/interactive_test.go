@@ -0,0 +1,245 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestClient creates a client that sends its requests to the given test server
+func newTestClient(server *httptest.Server, logger *mockLogger) *Client {
+	cfg := &config.Config{
+		WhatsApp: config.WhatsAppConfig{
+			PhoneNumberID: "12345",
+			AccessToken:   "test_token",
+		},
+	}
+
+	client := NewClient(cfg, logger)
+	client.HttpClient = server.Client()
+	client.DownloadClient = server.Client()
+	client.APIURL = server.URL + "/%s/messages"
+	client.sleep = func(context.Context, time.Duration) error { return nil }
+	return client
+}
+
+func TestSendButtonMessage(t *testing.T) {
+	t.Run("should send a button message successfully", func(t *testing.T) {
+		// arrange
+		var payload InteractiveMessage
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/12345/messages", r.URL.Path)
+			assert.Equal(t, "Bearer test_token", r.Header.Get("Authorization"))
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		logger := newMockLogger()
+		client := newTestClient(server, logger)
+
+		// act
+		_, err := client.SendButtonMessage("554499887766", domain.ButtonMessage{
+			Body:   "Você já possui CRM?",
+			Footer: "ContaMed",
+			Buttons: []domain.ReplyButton{
+				{ID: "1", Title: "Já tenho CRM"},
+				{ID: "2", Title: "Ainda não possuo"},
+			},
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "interactive", payload.Type)
+		assert.Equal(t, "554499887766", payload.To)
+		assert.Equal(t, "button", payload.Interactive.Type)
+		assert.Nil(t, payload.Interactive.Header)
+		assert.Equal(t, "Você já possui CRM?", payload.Interactive.Body.Text)
+		assert.Equal(t, "ContaMed", payload.Interactive.Footer.Text)
+		assert.Len(t, payload.Interactive.Action.Buttons, 2)
+		assert.Equal(t, "reply", payload.Interactive.Action.Buttons[0].Type)
+		assert.Equal(t, "2", payload.Interactive.Action.Buttons[1].Reply.ID)
+		assert.Contains(t, logger.debugMessages[0], "Sending WhatsApp button message")
+	})
+
+	t.Run("should not call the API when the message is invalid", func(t *testing.T) {
+		// arrange
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendButtonMessage("554499887766", domain.ButtonMessage{
+			Body: "Escolha",
+			Buttons: []domain.ReplyButton{
+				{ID: "1", Title: "Um"}, {ID: "2", Title: "Dois"}, {ID: "3", Title: "Três"}, {ID: "4", Title: "Quatro"},
+			},
+		})
+
+		// assert
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestSendListMessage(t *testing.T) {
+	t.Run("should send a list message successfully", func(t *testing.T) {
+		// arrange
+		var payload InteractiveMessage
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendListMessage("554499887766", domain.ListMessage{
+			Header:     "ContaMed",
+			Body:       "Como podemos ajudar?",
+			ButtonText: "Ver opções",
+			Sections: []domain.ListSection{
+				{Rows: []domain.ListRow{
+					{ID: "1", Title: "Já tenho empresa", Description: "Já tenho uma empresa médica constituída"},
+					{ID: "2", Title: "Quero abrir empresa"},
+				}},
+			},
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "list", payload.Interactive.Type)
+		assert.Equal(t, "text", payload.Interactive.Header.Type)
+		assert.Equal(t, "ContaMed", payload.Interactive.Header.Text)
+		assert.Nil(t, payload.Interactive.Footer)
+		assert.Equal(t, "Ver opções", payload.Interactive.Action.Button)
+		assert.Len(t, payload.Interactive.Action.Sections, 1)
+		assert.Len(t, payload.Interactive.Action.Sections[0].Rows, 2)
+		assert.Equal(t, "Já tenho uma empresa médica constituída", payload.Interactive.Action.Sections[0].Rows[0].Description)
+	})
+}
+
+func TestValidateButtonMessage(t *testing.T) {
+	valid := func() domain.ButtonMessage {
+		return domain.ButtonMessage{
+			Body:    "Escolha uma opção",
+			Buttons: []domain.ReplyButton{{ID: "1", Title: "Sim"}, {ID: "2", Title: "Não"}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(m *domain.ButtonMessage)
+		expected string
+	}{
+		{name: "without buttons", modify: func(m *domain.ButtonMessage) { m.Buttons = nil }, expected: "between 1 and 3 buttons"},
+		{name: "without body", modify: func(m *domain.ButtonMessage) { m.Body = "" }, expected: "body is required"},
+		{name: "with long body", modify: func(m *domain.ButtonMessage) { m.Body = strings.Repeat("a", 1025) }, expected: "body exceeds 1024"},
+		{name: "with long header", modify: func(m *domain.ButtonMessage) { m.Header = strings.Repeat("a", 61) }, expected: "header exceeds 60"},
+		{name: "with long footer", modify: func(m *domain.ButtonMessage) { m.Footer = strings.Repeat("a", 61) }, expected: "footer exceeds 60"},
+		{name: "with long title", modify: func(m *domain.ButtonMessage) { m.Buttons[0].Title = strings.Repeat("a", 21) }, expected: "button 1 title exceeds 20"},
+		{name: "with empty id", modify: func(m *domain.ButtonMessage) { m.Buttons[1].ID = "" }, expected: "button 2 id is required"},
+		{name: "with duplicated id", modify: func(m *domain.ButtonMessage) { m.Buttons[1].ID = "1" }, expected: "duplicated button id"},
+	}
+
+	t.Run("should accept a valid message", func(t *testing.T) {
+		assert.NoError(t, ValidateButtonMessage(valid()))
+	})
+
+	t.Run("should count characters instead of bytes", func(t *testing.T) {
+		// arrange
+		message := valid()
+		message.Buttons[0].Title = strings.Repeat("ã", 20)
+
+		// act & assert
+		assert.NoError(t, ValidateButtonMessage(message))
+	})
+
+	for _, tt := range tests {
+		t.Run("should reject message "+tt.name, func(t *testing.T) {
+			// arrange
+			message := valid()
+			tt.modify(&message)
+
+			// act
+			err := ValidateButtonMessage(message)
+
+			// assert
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestValidateListMessage(t *testing.T) {
+	valid := func() domain.ListMessage {
+		return domain.ListMessage{
+			Body:       "Escolha uma opção",
+			ButtonText: "Opções",
+			Sections: []domain.ListSection{
+				{Title: "Empresa", Rows: []domain.ListRow{{ID: "1", Title: "Abrir"}, {ID: "2", Title: "Migrar"}}},
+			},
+		}
+	}
+
+	manyRows := func(n int) []domain.ListRow {
+		rows := make([]domain.ListRow, n)
+		for i := range rows {
+			rows[i] = domain.ListRow{ID: strings.Repeat("r", i+1), Title: "Linha"}
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(m *domain.ListMessage)
+		expected string
+	}{
+		{name: "without button text", modify: func(m *domain.ListMessage) { m.ButtonText = "" }, expected: "button text is required"},
+		{name: "without sections", modify: func(m *domain.ListMessage) { m.Sections = nil }, expected: "between 1 and 10 sections"},
+		{name: "with empty section", modify: func(m *domain.ListMessage) { m.Sections[0].Rows = nil }, expected: "section 1 has no rows"},
+		{name: "with too many rows", modify: func(m *domain.ListMessage) { m.Sections[0].Rows = manyRows(11) }, expected: "at most 10 rows"},
+		{name: "with long row title", modify: func(m *domain.ListMessage) { m.Sections[0].Rows[0].Title = strings.Repeat("a", 25) }, expected: "row 1 title exceeds 24"},
+		{name: "with long row description", modify: func(m *domain.ListMessage) { m.Sections[0].Rows[1].Description = strings.Repeat("a", 73) }, expected: "row 2 description exceeds 72"},
+		{name: "with duplicated row id", modify: func(m *domain.ListMessage) { m.Sections[0].Rows[1].ID = "1" }, expected: "duplicated row id"},
+		{
+			name: "without section title when there are many sections",
+			modify: func(m *domain.ListMessage) {
+				m.Sections = append(m.Sections, domain.ListSection{Rows: []domain.ListRow{{ID: "3", Title: "Outros"}}})
+			},
+			expected: "section 2 title is required",
+		},
+	}
+
+	t.Run("should accept a valid message", func(t *testing.T) {
+		assert.NoError(t, ValidateListMessage(valid()))
+	})
+
+	for _, tt := range tests {
+		t.Run("should reject message "+tt.name, func(t *testing.T) {
+			// arrange
+			message := valid()
+			tt.modify(&message)
+
+			// act
+			err := ValidateListMessage(message)
+
+			// assert
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
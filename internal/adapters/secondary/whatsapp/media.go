@@ -0,0 +1,252 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+	"unicode/utf8"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// MaxMediaSize is the largest media file accepted by the WhatsApp Cloud API (100 MB)
+const MaxMediaSize = 100 << 20
+
+// DownloadTimeout bounds the download of a media file, leaving room for files up to MaxMediaSize
+const DownloadTimeout = 5 * time.Minute
+
+// MaxCaptionLength is the longest caption accepted for media messages
+const MaxCaptionLength = 1024
+
+// MediaInfo represents the metadata of a media returned by the Graph API
+type MediaInfo struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+	SHA256   string `json:"sha256"`
+	FileSize int64  `json:"file_size"`
+}
+
+// GetMediaInfo retrieves the metadata and the temporary download URL of a media.
+// It uses a background context, use GetMediaInfoContext to control cancellation and deadlines.
+func (c *Client) GetMediaInfo(mediaID string) (*MediaInfo, error) {
+	return c.GetMediaInfoContext(context.Background(), mediaID)
+}
+
+// GetMediaInfoContext retrieves the metadata and the temporary download URL of a media, aborting when the context is done
+func (c *Client) GetMediaInfoContext(ctx context.Context, mediaID string) (*MediaInfo, error) {
+	if mediaID == "" {
+		return nil, fmt.Errorf("media ID is required")
+	}
+
+	resp, err := c.get(ctx, c.graphURL(mediaID))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving media %s: %w", mediaID, err)
+	}
+	defer resp.Body.Close()
+
+	var info MediaInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("error decoding media %s: %w", mediaID, err)
+	}
+	if info.URL == "" {
+		return nil, fmt.Errorf("media %s has no download URL", mediaID)
+	}
+	return &info, nil
+}
+
+// DownloadMedia downloads the content of a media received in a message.
+// It uses a background context, use DownloadMediaContext to control cancellation and deadlines.
+func (c *Client) DownloadMedia(mediaID string) ([]byte, error) {
+	return c.DownloadMediaContext(context.Background(), mediaID)
+}
+
+// DownloadMediaContext downloads the content of a media received in a message, aborting when the context is done
+func (c *Client) DownloadMediaContext(ctx context.Context, mediaID string) ([]byte, error) {
+	info, err := c.GetMediaInfoContext(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if info.FileSize > MaxMediaSize {
+		return nil, fmt.Errorf("media %s exceeds %d bytes", mediaID, MaxMediaSize)
+	}
+
+	c.Logger.Debug("Downloading WhatsApp media %s (%s)", mediaID, info.MimeType)
+	resp, err := c.do(ctx, request{method: "GET", url: info.URL, client: c.DownloadClient, idempotent: true})
+	if err != nil {
+		return nil, fmt.Errorf("error downloading media %s: %w", mediaID, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxMediaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading media %s: %w", mediaID, err)
+	}
+	if len(data) > MaxMediaSize {
+		return nil, fmt.Errorf("media %s exceeds %d bytes", mediaID, MaxMediaSize)
+	}
+	return data, nil
+}
+
+// MediaMessagePayload represents a media message to send
+type MediaMessagePayload struct {
+	MessagingProduct string       `json:"messaging_product"`
+	RecipientType    string       `json:"recipient_type"`
+	To               string       `json:"to"`
+	Type             string       `json:"type"`
+	Image            *MediaObject `json:"image,omitempty"`
+	Document         *MediaObject `json:"document,omitempty"`
+	Audio            *MediaObject `json:"audio,omitempty"`
+	Video            *MediaObject `json:"video,omitempty"`
+}
+
+// MediaObject references the media of a message by ID or link
+type MediaObject struct {
+	ID       string `json:"id,omitempty"`
+	Link     string `json:"link,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// uploadResponse represents the response of the media upload endpoint
+type uploadResponse struct {
+	ID string `json:"id"`
+}
+
+// UploadMedia uploads a file to the media endpoint of the configured phone number and returns its media ID.
+// It uses a background context, use UploadMediaContext to control cancellation and deadlines.
+func (c *Client) UploadMedia(filename, mimeType string, content io.Reader) (string, error) {
+	return c.UploadMediaContext(context.Background(), filename, mimeType, content)
+}
+
+// UploadMediaContext uploads a file to the media endpoint of the configured phone number and returns its media ID, aborting when the context is done
+func (c *Client) UploadMediaContext(ctx context.Context, filename, mimeType string, content io.Reader) (string, error) {
+	if c.Config.WhatsApp.PhoneNumberID == "" {
+		return "", fmt.Errorf("phone number ID not configured")
+	}
+	if mimeType == "" {
+		return "", fmt.Errorf("mime type is required")
+	}
+
+	// Build the multipart form expected by the Graph API
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("messaging_product", "whatsapp")
+	writer.WriteField("type", mimeType)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+	header.Set("Content-Type", mimeType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return "", fmt.Errorf("error creating upload: %w", err)
+	}
+
+	written, err := io.Copy(part, io.LimitReader(content, MaxMediaSize+1))
+	if err != nil {
+		return "", fmt.Errorf("error reading media: %w", err)
+	}
+	if written > MaxMediaSize {
+		return "", fmt.Errorf("media exceeds %d bytes", MaxMediaSize)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("error creating upload: %w", err)
+	}
+
+	c.Logger.Debug("Uploading WhatsApp media %s (%s)", filename, mimeType)
+	resp, err := c.do(ctx, request{
+		method:      "POST",
+		url:         c.graphURL(c.Config.WhatsApp.PhoneNumberID + "/media"),
+		contentType: writer.FormDataContentType(),
+		body:        body.Bytes(),
+		idempotent:  true,
+		failure:     "error uploading media",
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result uploadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding upload response: %w", err)
+	}
+	if result.ID == "" {
+		return "", fmt.Errorf("upload response has no media ID")
+	}
+
+	c.Logger.Info("Media %s uploaded with ID %s", filename, result.ID)
+	return result.ID, nil
+}
+
+// SendMediaMessage sends an image, document, audio or video message to a WhatsApp user.
+// It uses a background context, use SendMediaMessageContext to control cancellation and deadlines.
+func (c *Client) SendMediaMessage(to string, message domain.MediaMessage) (*domain.SendResult, error) {
+	return c.SendMediaMessageContext(context.Background(), to, message)
+}
+
+// SendMediaMessageContext sends an image, document, audio or video message to a WhatsApp user, aborting when the context is done
+func (c *Client) SendMediaMessageContext(ctx context.Context, to string, message domain.MediaMessage) (*domain.SendResult, error) {
+	if err := ValidateMediaMessage(message); err != nil {
+		return nil, err
+	}
+
+	object := &MediaObject{
+		ID:       message.ID,
+		Link:     message.Link,
+		Caption:  message.Caption,
+		Filename: message.Filename,
+	}
+	payload := MediaMessagePayload{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               to,
+		Type:             string(message.Type),
+	}
+	switch message.Type {
+	case domain.MessageTypeImage:
+		payload.Image = object
+	case domain.MessageTypeDocument:
+		payload.Document = object
+	case domain.MessageTypeAudio:
+		payload.Audio = object
+	case domain.MessageTypeVideo:
+		payload.Video = object
+	}
+
+	c.Logger.Debug("Sending WhatsApp %s message to %s", message.Type, to)
+	return c.send(ctx, to, payload)
+}
+
+// ValidateMediaMessage checks that the media message can be accepted by the WhatsApp Cloud API
+func ValidateMediaMessage(message domain.MediaMessage) error {
+	switch message.Type {
+	case domain.MessageTypeImage, domain.MessageTypeDocument, domain.MessageTypeAudio, domain.MessageTypeVideo:
+	default:
+		return fmt.Errorf("invalid media message: unsupported type %q", message.Type)
+	}
+	if (message.ID == "") == (message.Link == "") {
+		return fmt.Errorf("invalid media message: either an id or a link is required")
+	}
+	if message.Type == domain.MessageTypeAudio && message.Caption != "" {
+		return fmt.Errorf("invalid media message: audio does not support captions")
+	}
+	if message.Type != domain.MessageTypeDocument && message.Filename != "" {
+		return fmt.Errorf("invalid media message: only documents support filenames")
+	}
+	if utf8.RuneCountInString(message.Caption) > MaxCaptionLength {
+		return fmt.Errorf("invalid media message: caption exceeds %d characters", MaxCaptionLength)
+	}
+	return nil
+}
+
+// get performs an authenticated GET request, returning the response on success
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	return c.do(ctx, request{method: "GET", url: url, idempotent: true})
+}
@@ -0,0 +1,302 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadMedia(t *testing.T) {
+	t.Run("should download media through its temporary URL", func(t *testing.T) {
+		// arrange
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "GET", r.Method)
+			assert.Equal(t, "Bearer test_token", r.Header.Get("Authorization"))
+
+			switch r.URL.Path {
+			case "/media.1":
+				fmt.Fprintf(w, `{"id":"media.1","url":"%s/download/media.1","mime_type":"image/jpeg","sha256":"abc","file_size":5}`, server.URL)
+			case "/download/media.1":
+				w.Write([]byte("image"))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		logger := newMockLogger()
+		client := newTestClient(server, logger)
+
+		// act
+		data, err := client.DownloadMedia("media.1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("image"), data)
+		assert.Contains(t, logger.debugMessages[0], "Downloading WhatsApp media")
+	})
+
+	t.Run("should download a slow media body beyond the API timeout", func(t *testing.T) {
+		// arrange
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/media.1":
+				fmt.Fprintf(w, `{"id":"media.1","url":"%s/download/media.1","mime_type":"application/pdf","file_size":5}`, server.URL)
+			case "/download/media.1":
+				for _, chunk := range []string{"p", "d", "f", "-", "1"} {
+					w.Write([]byte(chunk))
+					w.(http.Flusher).Flush()
+					time.Sleep(20 * time.Millisecond)
+				}
+			}
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+		client.HttpClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+		// act
+		data, err := client.DownloadMedia("media.1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("pdf-1"), data)
+	})
+
+	t.Run("should stop downloads exceeding the download timeout", func(t *testing.T) {
+		// arrange
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/media.1":
+				fmt.Fprintf(w, `{"id":"media.1","url":"%s/download/media.1","mime_type":"application/pdf","file_size":5}`, server.URL)
+			case "/download/media.1":
+				w.Write([]byte("p"))
+				w.(http.Flusher).Flush()
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			}
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+		client.DownloadClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+		// act
+		_, err := client.DownloadMedia("media.1")
+
+		// assert
+		assert.ErrorContains(t, err, "error reading media media.1")
+	})
+
+	t.Run("should return error when media info cannot be retrieved", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "Unsupported get request"}}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		data, err := client.DownloadMedia("media.1")
+
+		// assert
+		assert.Nil(t, data)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "API error")
+	})
+
+	t.Run("should reject media larger than the limit", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"id":"media.1","url":"http://example.com/x","file_size":%d}`, MaxMediaSize+1)
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.DownloadMedia("media.1")
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds")
+	})
+
+	t.Run("should require a media ID", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.GetMediaInfo("")
+
+		// assert
+		assert.Error(t, err)
+	})
+}
+
+func TestUploadMedia(t *testing.T) {
+	t.Run("should upload media as multipart form", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/12345/media", r.URL.Path)
+			assert.Equal(t, "Bearer test_token", r.Header.Get("Authorization"))
+
+			assert.NoError(t, r.ParseMultipartForm(1<<20))
+			assert.Equal(t, "whatsapp", r.FormValue("messaging_product"))
+			assert.Equal(t, "application/pdf", r.FormValue("type"))
+
+			file, header, err := r.FormFile("file")
+			assert.NoError(t, err)
+			defer file.Close()
+			content, _ := io.ReadAll(file)
+			assert.Equal(t, "proposta.pdf", header.Filename)
+			assert.Equal(t, "application/pdf", header.Header.Get("Content-Type"))
+			assert.Equal(t, "%PDF-1.4", string(content))
+
+			w.Write([]byte(`{"id":"media.42"}`))
+		}))
+		defer server.Close()
+
+		logger := newMockLogger()
+		client := newTestClient(server, logger)
+
+		// act
+		id, err := client.UploadMedia("proposta.pdf", "application/pdf", strings.NewReader("%PDF-1.4"))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "media.42", id)
+		assert.Contains(t, logger.infoMessages[0], "uploaded with ID")
+	})
+
+	t.Run("should return error when upload is rejected", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "Param file must be a file with one of the following types"}}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		id, err := client.UploadMedia("virus.exe", "application/x-msdownload", strings.NewReader("MZ"))
+
+		// assert
+		assert.Empty(t, id)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "API error")
+	})
+
+	t.Run("should require a mime type", func(t *testing.T) {
+		// arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.UploadMedia("file", "", strings.NewReader("x"))
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "mime type is required")
+	})
+}
+
+func TestSendMediaMessage(t *testing.T) {
+	t.Run("should send a document by ID with filename and caption", func(t *testing.T) {
+		// arrange
+		var payload MediaMessagePayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/12345/messages", r.URL.Path)
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendMediaMessage("554499887766", domain.MediaMessage{
+			Type:     domain.MessageTypeDocument,
+			ID:       "media.42",
+			Caption:  "Sua proposta",
+			Filename: "proposta.pdf",
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "document", payload.Type)
+		assert.Nil(t, payload.Image)
+		assert.Equal(t, &MediaObject{ID: "media.42", Caption: "Sua proposta", Filename: "proposta.pdf"}, payload.Document)
+	})
+
+	t.Run("should send an image by link", func(t *testing.T) {
+		// arrange
+		var payload MediaMessagePayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendMediaMessage("554499887766", domain.MediaMessage{
+			Type: domain.MessageTypeImage,
+			Link: "https://example.com/guia.png",
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "image", payload.Type)
+		assert.Equal(t, "https://example.com/guia.png", payload.Image.Link)
+	})
+}
+
+func TestValidateMediaMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  domain.MediaMessage
+		expected string
+	}{
+		{name: "with unsupported type", message: domain.MediaMessage{Type: domain.MessageTypeSticker, ID: "1"}, expected: "unsupported type"},
+		{name: "without id or link", message: domain.MediaMessage{Type: domain.MessageTypeImage}, expected: "either an id or a link"},
+		{name: "with id and link", message: domain.MediaMessage{Type: domain.MessageTypeImage, ID: "1", Link: "https://x"}, expected: "either an id or a link"},
+		{name: "with audio caption", message: domain.MediaMessage{Type: domain.MessageTypeAudio, ID: "1", Caption: "oi"}, expected: "audio does not support captions"},
+		{name: "with video filename", message: domain.MediaMessage{Type: domain.MessageTypeVideo, ID: "1", Filename: "a.mp4"}, expected: "only documents support filenames"},
+		{name: "with long caption", message: domain.MediaMessage{Type: domain.MessageTypeImage, ID: "1", Caption: strings.Repeat("a", 1025)}, expected: "caption exceeds 1024"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject message "+tt.name, func(t *testing.T) {
+			// act
+			err := ValidateMediaMessage(tt.message)
+
+			// assert
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Default retry policy used when the configuration does not set one
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryMaxDelay    = 10 * time.Second
+	DefaultRetryJitter      = 0.2
+)
+
+// RetryPolicy controls how failed Graph API requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled on every following retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts. A Retry-After above it stops the retries.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay randomly added or removed, between 0 and 1
+	Jitter float64
+}
+
+// NewRetryPolicy creates the retry policy from the configuration, using defaults for unset values
+func NewRetryPolicy(cfg config.WhatsAppConfig) RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts: cfg.RetryMaxAttempts,
+		BaseDelay:   cfg.RetryBaseDelay,
+		MaxDelay:    cfg.RetryMaxDelay,
+		Jitter:      cfg.RetryJitter,
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultRetryBaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultRetryMaxDelay
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		policy.Jitter = DefaultRetryJitter
+	}
+	return policy
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry), with jitter applied.
+// random must return a number in [0, 1).
+func (p RetryPolicy) Backoff(retry int, random float64) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	delay *= 1 + p.Jitter*(2*random-1)
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	return time.Duration(delay)
+}
+
+// request describes a Graph API request that can be built again for every attempt
+type request struct {
+	method      string
+	url         string
+	contentType string
+	body        []byte
+	// client performs the request instead of HttpClient when set
+	client *http.Client
+	// idempotent requests can be retried whenever they fail. Message sends are not
+	// idempotent and are only retried when WhatsApp certainly did not accept them.
+	idempotent bool
+	// failure describes the request in network errors, e.g. "error sending message"
+	failure string
+}
+
+// do performs the request, retrying according to the retry policy. On success the
+// caller must close the response body.
+func (c *Client) do(ctx context.Context, r request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(ctx, r)
+		if err == nil {
+			return resp, nil
+		}
+
+		delay, retry := c.retryDelay(ctx, r, err, attempt)
+		if !retry {
+			var apiErr *APIError
+			if r.failure != "" && !errors.As(err, &apiErr) {
+				return nil, fmt.Errorf("%s: %w", r.failure, err)
+			}
+			return nil, err
+		}
+
+		c.Logger.Warn("Retrying WhatsApp request %s %s%s in %s (attempt %d of %d): %v", r.method, r.url, requestTag(ctx), delay, attempt+1, c.Retry.MaxAttempts, err)
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt performs a single attempt of the request
+func (c *Client) attempt(ctx context.Context, r request) (*http.Response, error) {
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Config.WhatsApp.AccessToken)
+	if id := middleware.GetReqID(ctx); id != "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	client := c.HttpClient
+	if r.client != nil {
+		client = r.client
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// retryDelay decides whether the failed attempt should be retried and how long to wait
+func (c *Client) retryDelay(ctx context.Context, r request, err error, attempt int) (time.Duration, bool) {
+	if attempt >= c.Retry.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if !apiErr.IsRetryable() || (!r.idempotent && !apiErr.isRejection()) {
+			return 0, false
+		}
+	} else if !isTransportRetryable(err, r.idempotent) {
+		return 0, false
+	}
+
+	delay := c.Retry.Backoff(attempt, c.random())
+	if apiErr != nil && apiErr.RetryAfter > 0 {
+		if apiErr.RetryAfter > c.Retry.MaxDelay {
+			return 0, false
+		}
+		if apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+	}
+	return delay, true
+}
+
+// isRejection reports whether the error certainly means that WhatsApp did not process the
+// request, so sending it again can not produce a duplicate message. Only the rate limits are
+// rejections: the generic and service errors, like a bare 5xx returned by gateways on timeouts,
+// may hide an accepted message. Without an error code, only a 429 is taken as a rate limit.
+func (e *APIError) isRejection() bool {
+	if e.Code != 0 {
+		return throttlingCodes[e.Code]
+	}
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// isTransportRetryable reports whether a network error may succeed on retry. Requests that
+// are not idempotent are only retried when the connection could not be established, since
+// any later failure may happen after WhatsApp received the message.
+func isTransportRetryable(err error, idempotent bool) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	return idempotent
+}
+
+// parseRetryAfter parses the Retry-After header, given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// sleepContext waits for the delay, returning early with the context error when it is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// defaultRandom returns a random number in [0, 1) for the jitter
+func defaultRandom() float64 {
+	return rand.Float64()
+}
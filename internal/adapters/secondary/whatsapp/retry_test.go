@@ -0,0 +1,359 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRetryTestClient creates a test client that records the delays instead of sleeping
+func newRetryTestClient(server *httptest.Server, logger *mockLogger) (*Client, *[]time.Duration) {
+	client := newTestClient(server, logger)
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	client.random = func() float64 { return 0.5 }
+
+	delays := []time.Duration{}
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return client, &delays
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("should use the defaults for unset values", func(t *testing.T) {
+		// act
+		policy := NewRetryPolicy(config.WhatsAppConfig{})
+
+		// assert
+		assert.Equal(t, RetryPolicy{
+			MaxAttempts: DefaultRetryMaxAttempts,
+			BaseDelay:   DefaultRetryBaseDelay,
+			MaxDelay:    DefaultRetryMaxDelay,
+			Jitter:      0,
+		}, policy)
+	})
+
+	t.Run("should use the configured values", func(t *testing.T) {
+		// act
+		policy := NewRetryPolicy(config.WhatsAppConfig{
+			RetryMaxAttempts: 5,
+			RetryBaseDelay:   time.Second,
+			RetryMaxDelay:    time.Minute,
+			RetryJitter:      0.5,
+		})
+
+		// assert
+		assert.Equal(t, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.5}, policy)
+	})
+
+	t.Run("should double the delay on every retry up to the maximum", func(t *testing.T) {
+		// arrange
+		policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+		// act & assert
+		assert.Equal(t, 100*time.Millisecond, policy.Backoff(1, 0.5))
+		assert.Equal(t, 200*time.Millisecond, policy.Backoff(2, 0.5))
+		assert.Equal(t, 400*time.Millisecond, policy.Backoff(3, 0.5))
+		assert.Equal(t, time.Second, policy.Backoff(5, 0.5))
+	})
+
+	t.Run("should spread the delay by the jitter fraction", func(t *testing.T) {
+		// arrange
+		policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.2}
+
+		// act & assert
+		assert.Equal(t, 800*time.Millisecond, policy.Backoff(1, 0))
+		assert.Equal(t, time.Second, policy.Backoff(1, 0.5))
+		assert.InDelta(t, float64(1200*time.Millisecond), float64(policy.Backoff(1, 0.9999)), float64(time.Millisecond))
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should parse the delay in seconds", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	})
+
+	t.Run("should parse the HTTP date", func(t *testing.T) {
+		assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	})
+
+	t.Run("should ignore missing and invalid values", func(t *testing.T) {
+		assert.Zero(t, parseRetryAfter("", now))
+		assert.Zero(t, parseRetryAfter("soon", now))
+		assert.Zero(t, parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	})
+}
+
+func TestClientRetry(t *testing.T) {
+	t.Run("should retry a rate limited send with exponential backoff", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"error":{"message":"Rate limit hit","code":130429}}`))
+				return
+			}
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+		logger := newMockLogger()
+		client, delays := newRetryTestClient(server, logger)
+
+		// act
+		result, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "wamid.out.1", result.MessageID)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *delays)
+		assert.Len(t, logger.warnMessages, 2)
+	})
+
+	t.Run("should send the same body on every attempt", func(t *testing.T) {
+		// arrange
+		bodies := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			buf := make([]byte, r.ContentLength)
+			r.Body.Read(buf)
+			bodies = append(bodies, string(buf))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+		client, _ := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, bodies, 2)
+		assert.Equal(t, bodies[0], bodies[1])
+		assert.Contains(t, bodies[1], "Olá")
+	})
+
+	t.Run("should give up after the maximum number of attempts", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client, delays := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.Error(t, err)
+		assert.True(t, IsRetryable(err))
+		assert.Equal(t, 3, attempts)
+		assert.Len(t, *delays, 2)
+	})
+
+	t.Run("should not retry permanent errors", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"Invalid parameter","code":100}}`))
+		}))
+		defer server.Close()
+		client, _ := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should not resend a message after an ambiguous server error", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}))
+		defer server.Close()
+		client, _ := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should not resend a message after a generic or service error", func(t *testing.T) {
+		for _, code := range []int{CodeAPIUnknown, CodeAPIService, CodeGenericError, CodeServiceUnavailable} {
+			// arrange
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprintf(w, `{"error":{"message":"Something went wrong","code":%d}}`, code)
+			}))
+			client, _ := newRetryTestClient(server, newMockLogger())
+
+			// act
+			_, err := client.SendTextMessage("554499887766", "Olá")
+
+			// assert
+			assert.True(t, IsRetryable(err), code)
+			assert.Equal(t, 1, attempts, code)
+			server.Close()
+		}
+	})
+
+	t.Run("should not resend a message after a 503 without an error code", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("upstream unavailable"))
+		}))
+		defer server.Close()
+		client, _ := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.True(t, IsRetryable(err))
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should retry idempotent requests after a generic error", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":{"message":"Something went wrong","code":131000}}`))
+				return
+			}
+			w.Write([]byte(`{"url":"https://example.com/media","mime_type":"image/jpeg","id":"media-1"}`))
+		}))
+		defer server.Close()
+		client, _ := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.GetMediaInfo("media-1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("should retry idempotent requests after an ambiguous server error", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+			w.Write([]byte(`{"url":"https://example.com/media","mime_type":"image/jpeg","id":"media-1"}`))
+		}))
+		defer server.Close()
+		client, _ := newRetryTestClient(server, newMockLogger())
+
+		// act
+		info, err := client.GetMediaInfo("media-1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "media-1", info.ID)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("should wait as long as the Retry-After header asks", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+		client, delays := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second}, *delays)
+	})
+
+	t.Run("should stop when Retry-After exceeds the maximum delay", func(t *testing.T) {
+		// arrange
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client, _ := newRetryTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		var apiErr *APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, 2*time.Minute, apiErr.RetryAfter)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should retry a send when the connection could not be established", func(t *testing.T) {
+		// arrange
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		address := listener.Addr().String()
+		listener.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+		client, delays := newRetryTestClient(server, newMockLogger())
+		client.HttpClient = &http.Client{}
+		client.APIURL = "http://" + address + "/%s/messages"
+
+		// act
+		_, err = client.SendTextMessage("554499887766", "Olá")
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error sending message")
+		assert.Len(t, *delays, 2)
+	})
+}
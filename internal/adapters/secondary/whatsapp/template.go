@@ -0,0 +1,231 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// DefaultTemplateLanguage is the language used when a template does not set one
+const DefaultTemplateLanguage = "pt_BR"
+
+// MaxTemplateButtonIndex is the highest button index accepted by the WhatsApp Cloud API
+const MaxTemplateButtonIndex = 9
+
+// TemplateMessagePayload represents a template message to send
+type TemplateMessagePayload struct {
+	MessagingProduct string   `json:"messaging_product"`
+	RecipientType    string   `json:"recipient_type"`
+	To               string   `json:"to"`
+	Type             string   `json:"type"`
+	Template         Template `json:"template"`
+}
+
+// Template identifies the template and holds its components
+type Template struct {
+	Name       string              `json:"name"`
+	Language   TemplateLanguage    `json:"language"`
+	Components []TemplateComponent `json:"components,omitempty"`
+}
+
+// TemplateLanguage holds the language code of a template
+type TemplateLanguage struct {
+	Code string `json:"code"`
+}
+
+// TemplateComponent represents a header, body or button component
+type TemplateComponent struct {
+	Type       string              `json:"type"`
+	SubType    string              `json:"sub_type,omitempty"`
+	Index      string              `json:"index,omitempty"`
+	Parameters []TemplateParameter `json:"parameters"`
+}
+
+// TemplateParameter represents a typed template parameter
+type TemplateParameter struct {
+	Type     string            `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	Payload  string            `json:"payload,omitempty"`
+	Currency *TemplateCurrency `json:"currency,omitempty"`
+	DateTime *TemplateDateTime `json:"date_time,omitempty"`
+	Image    *TemplateMedia    `json:"image,omitempty"`
+	Document *TemplateMedia    `json:"document,omitempty"`
+	Video    *TemplateMedia    `json:"video,omitempty"`
+}
+
+// TemplateCurrency represents a currency parameter
+type TemplateCurrency struct {
+	FallbackValue string `json:"fallback_value"`
+	Code          string `json:"code"`
+	Amount1000    int64  `json:"amount_1000"`
+}
+
+// TemplateDateTime represents a date and time parameter
+type TemplateDateTime struct {
+	FallbackValue string `json:"fallback_value"`
+}
+
+// TemplateMedia represents a media parameter
+type TemplateMedia struct {
+	ID       string `json:"id,omitempty"`
+	Link     string `json:"link,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// SendTemplateMessage sends a template message to a WhatsApp user.
+// It uses a background context, use SendTemplateMessageContext to control cancellation and deadlines.
+func (c *Client) SendTemplateMessage(to string, template domain.TemplateMessage) (*domain.SendResult, error) {
+	return c.SendTemplateMessageContext(context.Background(), to, template)
+}
+
+// SendTemplateMessageContext sends a template message to a WhatsApp user, aborting when the context is done
+func (c *Client) SendTemplateMessageContext(ctx context.Context, to string, template domain.TemplateMessage) (*domain.SendResult, error) {
+	if err := ValidateTemplateMessage(template); err != nil {
+		return nil, err
+	}
+
+	language := template.LanguageCode
+	if language == "" {
+		language = DefaultTemplateLanguage
+	}
+
+	components := make([]TemplateComponent, 0, len(template.Components))
+	for _, component := range template.Components {
+		components = append(components, newTemplateComponent(component))
+	}
+
+	payload := TemplateMessagePayload{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               to,
+		Type:             "template",
+		Template: Template{
+			Name:       template.Name,
+			Language:   TemplateLanguage{Code: language},
+			Components: components,
+		},
+	}
+
+	c.Logger.Debug("Sending WhatsApp template %s to %s", template.Name, to)
+	return c.send(ctx, to, payload)
+}
+
+// ValidateTemplateMessage checks that the template can be accepted by the WhatsApp Cloud API
+func ValidateTemplateMessage(template domain.TemplateMessage) error {
+	if template.Name == "" {
+		return fmt.Errorf("invalid template message: name is required")
+	}
+
+	for i, component := range template.Components {
+		if err := validateTemplateComponent(component); err != nil {
+			return fmt.Errorf("invalid template message: component %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validateTemplateComponent checks a single component and its parameters
+func validateTemplateComponent(component domain.TemplateComponent) error {
+	switch component.Type {
+	case domain.TemplateComponentHeader, domain.TemplateComponentBody:
+	case domain.TemplateComponentButton:
+		if component.SubType != domain.TemplateButtonQuickReply && component.SubType != domain.TemplateButtonURL {
+			return fmt.Errorf("unsupported button sub type %q", component.SubType)
+		}
+		if component.Index < 0 || component.Index > MaxTemplateButtonIndex {
+			return fmt.Errorf("button index must be between 0 and %d", MaxTemplateButtonIndex)
+		}
+	default:
+		return fmt.Errorf("unsupported component type %q", component.Type)
+	}
+
+	for i, parameter := range component.Parameters {
+		if err := validateTemplateParameter(component.Type, parameter); err != nil {
+			return fmt.Errorf("parameter %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validateTemplateParameter checks that the parameter carries the value of its type
+func validateTemplateParameter(componentType domain.TemplateComponentType, parameter domain.TemplateParameter) error {
+	switch parameter.Type {
+	case domain.TemplateParameterText:
+		if parameter.Text == "" {
+			return fmt.Errorf("text is required")
+		}
+	case domain.TemplateParameterPayload:
+		if componentType != domain.TemplateComponentButton {
+			return fmt.Errorf("payload is only allowed in buttons")
+		}
+		if parameter.Payload == "" {
+			return fmt.Errorf("payload is required")
+		}
+	case domain.TemplateParameterCurrency:
+		if parameter.Currency == nil || parameter.Currency.FallbackValue == "" {
+			return fmt.Errorf("currency fallback value is required")
+		}
+		if len(parameter.Currency.Code) != 3 {
+			return fmt.Errorf("currency code must be an ISO 4217 code")
+		}
+	case domain.TemplateParameterDateTime:
+		if parameter.DateTime == nil || parameter.DateTime.FallbackValue == "" {
+			return fmt.Errorf("date_time fallback value is required")
+		}
+	case domain.TemplateParameterImage, domain.TemplateParameterDocument, domain.TemplateParameterVideo:
+		if componentType != domain.TemplateComponentHeader {
+			return fmt.Errorf("%s is only allowed in the header", parameter.Type)
+		}
+		if parameter.Media == nil || (parameter.Media.ID == "") == (parameter.Media.Link == "") {
+			return fmt.Errorf("%s requires either an id or a link", parameter.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported parameter type %q", parameter.Type)
+	}
+	return nil
+}
+
+// newTemplateComponent converts a domain component to its API representation
+func newTemplateComponent(component domain.TemplateComponent) TemplateComponent {
+	result := TemplateComponent{
+		Type:       string(component.Type),
+		Parameters: make([]TemplateParameter, 0, len(component.Parameters)),
+	}
+	if component.Type == domain.TemplateComponentButton {
+		result.SubType = string(component.SubType)
+		result.Index = strconv.Itoa(component.Index)
+	}
+
+	for _, parameter := range component.Parameters {
+		p := TemplateParameter{
+			Type:    string(parameter.Type),
+			Text:    parameter.Text,
+			Payload: parameter.Payload,
+		}
+		if parameter.Currency != nil {
+			p.Currency = &TemplateCurrency{
+				FallbackValue: parameter.Currency.FallbackValue,
+				Code:          parameter.Currency.Code,
+				Amount1000:    parameter.Currency.Amount1000,
+			}
+		}
+		if parameter.DateTime != nil {
+			p.DateTime = &TemplateDateTime{FallbackValue: parameter.DateTime.FallbackValue}
+		}
+		if parameter.Media != nil {
+			media := &TemplateMedia{ID: parameter.Media.ID, Link: parameter.Media.Link, Filename: parameter.Media.Filename}
+			switch parameter.Type {
+			case domain.TemplateParameterImage:
+				p.Image = media
+			case domain.TemplateParameterDocument:
+				p.Document = media
+			case domain.TemplateParameterVideo:
+				p.Video = media
+			}
+		}
+		result.Parameters = append(result.Parameters, p)
+	}
+	return result
+}
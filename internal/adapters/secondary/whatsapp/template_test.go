@@ -0,0 +1,175 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendTemplateMessage(t *testing.T) {
+	t.Run("should send a template with typed parameters", func(t *testing.T) {
+		// arrange
+		var body map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		logger := newMockLogger()
+		client := newTestClient(server, logger)
+
+		// act
+		_, err := client.SendTemplateMessage("554499887766", domain.TemplateMessage{
+			Name: "lembrete_das",
+			Components: []domain.TemplateComponent{
+				domain.HeaderComponent(domain.DocumentParameter("https://example.com/das.pdf", "DAS.pdf")),
+				domain.BodyComponent(
+					domain.TextParameter("Dra. Ana"),
+					domain.CurrencyParameter("R$ 1.234,56", "BRL", 123456),
+					domain.DateTimeParameter("20/03/2025"),
+				),
+				domain.QuickReplyButtonComponent(0, "menu"),
+			},
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "template", body["type"])
+		template := body["template"].(map[string]interface{})
+		assert.Equal(t, "lembrete_das", template["name"])
+		assert.Equal(t, "pt_BR", template["language"].(map[string]interface{})["code"])
+
+		components := template["components"].([]interface{})
+		assert.Len(t, components, 3)
+
+		header := components[0].(map[string]interface{})
+		document := header["parameters"].([]interface{})[0].(map[string]interface{})["document"].(map[string]interface{})
+		assert.Equal(t, "https://example.com/das.pdf", document["link"])
+		assert.Equal(t, "DAS.pdf", document["filename"])
+
+		bodyParameters := components[1].(map[string]interface{})["parameters"].([]interface{})
+		assert.Equal(t, "Dra. Ana", bodyParameters[0].(map[string]interface{})["text"])
+		currency := bodyParameters[1].(map[string]interface{})["currency"].(map[string]interface{})
+		assert.Equal(t, "BRL", currency["code"])
+		assert.Equal(t, float64(1234560), currency["amount_1000"])
+		dateTime := bodyParameters[2].(map[string]interface{})["date_time"].(map[string]interface{})
+		assert.Equal(t, "20/03/2025", dateTime["fallback_value"])
+
+		button := components[2].(map[string]interface{})
+		assert.Equal(t, "button", button["type"])
+		assert.Equal(t, "quick_reply", button["sub_type"])
+		assert.Equal(t, "0", button["index"])
+		assert.Equal(t, "menu", button["parameters"].([]interface{})[0].(map[string]interface{})["payload"])
+		assert.Contains(t, logger.debugMessages[0], "Sending WhatsApp template")
+	})
+
+	t.Run("should keep the given language code", func(t *testing.T) {
+		// arrange
+		var payload TemplateMessagePayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.Write([]byte(sendResponseBody))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server, newMockLogger())
+
+		// act
+		_, err := client.SendTemplateMessage("554499887766", domain.TemplateMessage{Name: "hello_world", LanguageCode: "en_US"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "en_US", payload.Template.Language.Code)
+		assert.Empty(t, payload.Template.Components)
+	})
+}
+
+func TestValidateTemplateMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		template domain.TemplateMessage
+		expected string
+	}{
+		{
+			name:     "without name",
+			template: domain.TemplateMessage{},
+			expected: "name is required",
+		},
+		{
+			name: "with unknown component",
+			template: domain.TemplateMessage{Name: "t", Components: []domain.TemplateComponent{
+				{Type: "footer"},
+			}},
+			expected: "unsupported component type",
+		},
+		{
+			name: "with button index out of range",
+			template: domain.TemplateMessage{Name: "t", Components: []domain.TemplateComponent{
+				domain.QuickReplyButtonComponent(10, "menu"),
+			}},
+			expected: "button index must be between 0 and 9",
+		},
+		{
+			name: "with empty text",
+			template: domain.TemplateMessage{Name: "t", Components: []domain.TemplateComponent{
+				domain.BodyComponent(domain.TextParameter("")),
+			}},
+			expected: "component 1: parameter 1: text is required",
+		},
+		{
+			name: "with invalid currency code",
+			template: domain.TemplateMessage{Name: "t", Components: []domain.TemplateComponent{
+				domain.BodyComponent(domain.CurrencyParameter("R$ 10,00", "REAL", 1000)),
+			}},
+			expected: "ISO 4217",
+		},
+		{
+			name: "with media outside the header",
+			template: domain.TemplateMessage{Name: "t", Components: []domain.TemplateComponent{
+				domain.BodyComponent(domain.ImageParameter("https://example.com/a.png")),
+			}},
+			expected: "image is only allowed in the header",
+		},
+		{
+			name: "with media without id or link",
+			template: domain.TemplateMessage{Name: "t", Components: []domain.TemplateComponent{
+				domain.HeaderComponent(domain.TemplateParameter{Type: domain.TemplateParameterVideo, Media: &domain.TemplateMedia{}}),
+			}},
+			expected: "video requires either an id or a link",
+		},
+		{
+			name: "with payload outside a button",
+			template: domain.TemplateMessage{Name: "t", Components: []domain.TemplateComponent{
+				domain.BodyComponent(domain.TemplateParameter{Type: domain.TemplateParameterPayload, Payload: "x"}),
+			}},
+			expected: "payload is only allowed in buttons",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject template "+tt.name, func(t *testing.T) {
+			// act
+			err := ValidateTemplateMessage(tt.template)
+
+			// assert
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+
+	t.Run("should accept a template with url button", func(t *testing.T) {
+		// arrange
+		template := domain.TemplateMessage{Name: "proposta", Components: []domain.TemplateComponent{
+			domain.HeaderComponent(domain.TemplateParameter{Type: domain.TemplateParameterImage, Media: &domain.TemplateMedia{ID: "123"}}),
+			domain.URLButtonComponent(1, "proposta/42"),
+		}}
+
+		// act & assert
+		assert.NoError(t, ValidateTemplateMessage(template))
+	})
+}
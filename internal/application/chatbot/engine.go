@@ -0,0 +1,466 @@
+package chatbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// DefaultSessionTimeout is the idle time after which a conversation starts over
+const DefaultSessionTimeout = 24 * time.Hour
+
+// maxMunicipalityAttempts is the number of answers not found in the catalog after which the
+// municipality is kept as typed, so that a municipality missing from the catalog does not block the user
+const maxMunicipalityAttempts = 2
+
+// maxMisunderstoodAnswers is the number of answers in a row the bot can not understand after which
+// the conversation is handed off to the agents
+const maxMisunderstoodAnswers = 3
+
+// restartKeywords are the inputs that bring the user back to the main menu from any step
+var restartKeywords = map[string]bool{
+	"menu":   true,
+	"inicio": true,
+	"início": true,
+	"voltar": true,
+}
+
+// handoffKeywords are the inputs that hand the conversation off to the agents from any step
+var handoffKeywords = map[string]bool{
+	"atendente":   true,
+	"atendimento": true,
+	"humano":      true,
+}
+
+// simulationKeywords are the inputs that start the tax simulation from any step
+var simulationKeywords = map[string]bool{
+	"simular":   true,
+	"simulador": true,
+	"simulacao": true,
+	"simulação": true,
+}
+
+// Engine drives the chatbot conversation flow
+type Engine struct {
+	sender         ports.MessageSender
+	conversations  ports.ConversationRepository
+	media          ports.MediaIngester
+	leads          ports.LeadCapturer
+	handoffs       ports.HandoffRequester
+	crms           ports.CRMVerifier
+	locations      ports.LocationCatalog
+	questions      ports.FAQSearcher
+	logger         logger.Logger
+	clock          clock.Clock
+	SessionTimeout time.Duration
+	// Hours tells when the team answers the users, who are told when to expect an answer outside them.
+	// The team is always available when nil.
+	Hours *domain.BusinessHours
+	// TaxRules are the tables and rates used by the tax simulation
+	TaxRules domain.TaxRules
+}
+
+var _ ports.MessageProcessor = (*Engine)(nil)
+
+// NewEngine creates a new conversation engine
+func NewEngine(sender ports.MessageSender, conversations ports.ConversationRepository, media ports.MediaIngester, leads ports.LeadCapturer, handoffs ports.HandoffRequester, crms ports.CRMVerifier, locations ports.LocationCatalog, questions ports.FAQSearcher, log logger.Logger) *Engine {
+	return &Engine{
+		sender:         sender,
+		conversations:  conversations,
+		media:          media,
+		leads:          leads,
+		handoffs:       handoffs,
+		crms:           crms,
+		locations:      locations,
+		questions:      questions,
+		logger:         log,
+		clock:          clock.System,
+		SessionTimeout: DefaultSessionTimeout,
+		TaxRules:       domain.TaxRules2026,
+	}
+}
+
+// ProcessMessage advances the conversation of the sender and replies to it.
+// Conversations handed off to the agents are left to them without a reply.
+func (e *Engine) ProcessMessage(ctx context.Context, msg domain.InboundMessage) error {
+	// Attachments are stored without moving the conversation forward
+	if msg.Media != nil {
+		return e.receiveMedia(ctx, msg)
+	}
+
+	now := e.clock.Now()
+
+	conversation, err := e.conversations.FindByPhone(ctx, msg.From)
+	if errors.Is(err, domain.ErrNotFound) {
+		conversation = domain.NewConversation(msg.From, now)
+	} else if err != nil {
+		return fmt.Errorf("error loading conversation: %w", err)
+	}
+	if conversation.IsHandedOff() {
+		return e.leaveToAgents(ctx, conversation, msg)
+	}
+
+	// Tapped buttons and list rows carry the option in their reply ID
+	input, answer := normalizeInput(msg.Text), strings.TrimSpace(msg.Text)
+	if msg.ReplyID != "" {
+		input, answer = normalizeInput(msg.ReplyID), msg.ReplyID
+	}
+	if conversation.Step == domain.StepCompleted || conversation.IsExpired(now, e.SessionTimeout) || restartKeywords[input] {
+		conversation.Restart(now)
+	}
+
+	reply := e.advance(ctx, conversation, input, answer, now)
+
+	if err := e.conversations.Save(ctx, conversation); err != nil {
+		return fmt.Errorf("error saving conversation: %w", err)
+	}
+
+	// A lead that failed to be captured must not leave the user without a reply
+	if err := e.leads.CaptureLead(ctx, conversation, msg.ProfileName); err != nil {
+		e.logger.Error("Error capturing lead of %s: %v", msg.From, err)
+	}
+	if conversation.IsHandedOff() {
+		if err := e.handoffs.RequestHandoff(ctx, conversation, msg.ProfileName); err != nil {
+			e.logger.Error("Error handing %s off to the agents: %v", msg.From, err)
+		}
+	}
+
+	result, err := e.send(ctx, msg.From, reply)
+	if errors.Is(err, domain.ErrRecipientUnavailable) {
+		// Retrying or redelivering the webhook would fail the same way
+		e.logger.Warn("Recipient %s can not receive messages: %v", msg.From, err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error sending reply: %w", err)
+	}
+
+	e.logger.Debug("Conversation with %s moved to step %s, replied with message %s", msg.From, conversation.Step, result.MessageID)
+	return nil
+}
+
+// leaveToAgents records a message of a user handed off to the agents, without replying to it
+func (e *Engine) leaveToAgents(ctx context.Context, c *domain.Conversation, msg domain.InboundMessage) error {
+	if err := e.handoffs.RequestHandoff(ctx, c, msg.ProfileName); err != nil {
+		return fmt.Errorf("error recording message of handoff: %w", err)
+	}
+	e.logger.Debug("Conversation with %s is handed off, message %s left to the agents", msg.From, msg.ID)
+	return nil
+}
+
+// receiveMedia stores the attachment of the message and acknowledges it, unless the
+// conversation is handed off to the agents
+func (e *Engine) receiveMedia(ctx context.Context, msg domain.InboundMessage) error {
+	if _, err := e.media.Ingest(ctx, msg); err != nil {
+		if _, sendErr := e.sender.SendTextMessageContext(ctx, msg.From, mediaFailedText); sendErr != nil {
+			e.logger.Error("Error sending media failure notice to %s: %v", msg.From, sendErr)
+		}
+		return fmt.Errorf("error receiving media: %w", err)
+	}
+
+	conversation, err := e.conversations.FindByPhone(ctx, msg.From)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("error loading conversation: %w", err)
+	}
+	if conversation != nil && conversation.IsHandedOff() {
+		return e.leaveToAgents(ctx, conversation, msg)
+	}
+
+	if _, err := e.sender.SendTextMessageContext(ctx, msg.From, mediaReceivedText); err != nil {
+		return fmt.Errorf("error sending reply: %w", err)
+	}
+	return nil
+}
+
+// send sends the reply using the message type it was built for
+func (e *Engine) send(ctx context.Context, to string, r reply) (*domain.SendResult, error) {
+	switch {
+	case r.list != nil:
+		return e.sender.SendListMessageContext(ctx, to, *r.list)
+	case r.buttons != nil:
+		return e.sender.SendButtonMessageContext(ctx, to, *r.buttons)
+	default:
+		return e.sender.SendTextMessageContext(ctx, to, r.text)
+	}
+}
+
+// advance applies the input to the conversation and returns the reply to be sent.
+// input is the normalized text used to match options, answer is the text as typed by the user
+// or the reply ID of the tapped row.
+func (e *Engine) advance(ctx context.Context, c *domain.Conversation, input, answer string, now time.Time) reply {
+	if handoffKeywords[input] {
+		return e.handOff(c, domain.HandoffReasonRequested, now, requestedFormat)
+	}
+	if simulationKeywords[input] {
+		// The simulation replaces the current flow, whose answers must not be taken for a lead
+		// completed when the simulation ends
+		c.Restart(now)
+		c.MoveTo(domain.StepSimulationRevenue, now)
+		return textReply(askRevenueText)
+	}
+
+	switch c.Step {
+	case domain.StepMainMenu:
+		return e.handleMainMenu(c, input, now)
+	case domain.StepCRMQuestion:
+		return e.handleCRMQuestion(c, input, now)
+	case domain.StepCRMNumber:
+		return e.handleCRMNumber(ctx, c, answer, now)
+	case domain.StepSpecialty:
+		return e.handleSpecialty(c, answer, now)
+	case domain.StepState:
+		return e.handleState(c, answer, now)
+	case domain.StepMunicipality:
+		return e.handleMunicipality(c, answer, now)
+	case domain.StepQuestion:
+		return e.handleQuestion(ctx, c, answer, now)
+	case domain.StepSimulationRevenue:
+		return e.handleRevenue(c, answer, now)
+	case domain.StepSimulationProLabore:
+		return e.handleProLabore(c, answer, now)
+	default:
+		c.MoveTo(domain.StepMainMenu, now)
+		return mainMenu(welcomeText(now.In(c.Location())))
+	}
+}
+
+// handleMainMenu handles the answer to the main menu
+func (e *Engine) handleMainMenu(c *domain.Conversation, input string, now time.Time) reply {
+	switch input {
+	case "1":
+		c.MenuOption = domain.OptionHasCompany
+		c.MoveTo(domain.StepCompleted, now)
+		return textReply(e.promise(c, now, hasCompanyFormat))
+	case "2":
+		c.MenuOption = domain.OptionOpenCompany
+		c.MoveTo(domain.StepCRMQuestion, now)
+		return crmMenu("")
+	case "3":
+		c.MenuOption = domain.OptionQuestions
+		c.MoveTo(domain.StepQuestion, now)
+		return textReply(askQuestionText)
+	case "4":
+		c.MenuOption = domain.OptionOther
+		return e.handOff(c, domain.HandoffReasonOther, now, otherFormat)
+	default:
+		return e.misunderstood(c, now, mainMenu(invalidOptionText))
+	}
+}
+
+// handleCRMQuestion handles the answer to the CRM question
+func (e *Engine) handleCRMQuestion(c *domain.Conversation, input string, now time.Time) reply {
+	switch input {
+	case "1":
+		c.HasCRM = true
+		c.MoveTo(domain.StepCRMNumber, now)
+		return textReply(askCRMText)
+	case "2":
+		c.HasCRM = false
+		c.MoveTo(domain.StepSpecialty, now)
+		return textReply(askSpecialtyText)
+	default:
+		return e.misunderstood(c, now, crmMenu(invalidOptionText))
+	}
+}
+
+// handleCRMNumber validates the CRM informed by the user and checks it with the federal council.
+// Registrations that can not be checked are accepted, only the ones unknown to the council are refused.
+func (e *Engine) handleCRMNumber(ctx context.Context, c *domain.Conversation, answer string, now time.Time) reply {
+	crm, err := domain.ParseCRM(answer)
+	if err != nil {
+		return e.misunderstood(c, now, textReply(crmErrorText(err)))
+	}
+
+	verification, err := e.crms.VerifyCRM(ctx, crm)
+	if err != nil {
+		e.logger.Warn("Error verifying %s of %s: %v", crm, c.Phone, err)
+		verification = domain.CRMUnverified
+	}
+	if verification == domain.CRMNotFound {
+		return e.misunderstood(c, now, textReply(fmt.Sprintf(crmNotFoundFormat, crm)))
+	}
+
+	c.CRM = crm
+	c.CRMVerified = verification == domain.CRMVerified
+	c.MoveTo(domain.StepSpecialty, now)
+	return textReply(askSpecialtyText)
+}
+
+// handleSpecialty keeps the specialty as typed by the user, for the sales team
+func (e *Engine) handleSpecialty(c *domain.Conversation, answer string, now time.Time) reply {
+	if answer == "" {
+		return e.misunderstood(c, now, textReply(emptyAnswerText+"\n\n"+askSpecialtyText))
+	}
+
+	c.Specialty = answer
+	c.MoveTo(domain.StepState, now)
+	return textReply(askStateText)
+}
+
+// handleState looks the state up in the catalog, asking the user to pick one when the answer is ambiguous
+func (e *Engine) handleState(c *domain.Conversation, answer string, now time.Time) reply {
+	if answer == "" {
+		return e.misunderstood(c, now, textReply(emptyAnswerText+"\n\n"+askStateText))
+	}
+
+	match := e.locations.MatchState(answer)
+	switch {
+	case match.Found():
+		c.State = match.UF
+		c.Locate()
+		c.MoveTo(domain.StepMunicipality, now)
+		return textReply(askMunicipalityText)
+	case len(match.Candidates) > 0:
+		return stateList(match.Candidates)
+	default:
+		return e.misunderstood(c, now, textReply(stateNotFoundText))
+	}
+}
+
+// handleMunicipality looks the municipality up in the catalog, asking the user to pick one when the
+// answer is ambiguous. A municipality typed along with another state moves the conversation to that state.
+func (e *Engine) handleMunicipality(c *domain.Conversation, answer string, now time.Time) reply {
+	if answer == "" {
+		return textReply(emptyAnswerText + "\n\n" + askMunicipalityText)
+	}
+
+	match := e.locations.MatchMunicipality(c.State, answer)
+	switch {
+	case match.Found():
+		c.State = match.Municipality.UF
+		c.Municipality = match.Municipality.Name
+		c.MunicipalityCode = match.Municipality.Code
+	case len(match.Candidates) > 0:
+		return municipalityList(match.Candidates)
+	case c.Attempts+1 < maxMunicipalityAttempts:
+		c.Attempts++
+		return textReply(municipalityNotFoundText)
+	default:
+		e.logger.Warn("Municipality %q of %s not found in the catalog, keeping it as typed", answer, c.Phone)
+		c.Municipality = answer
+		c.MunicipalityCode = ""
+	}
+	c.Locate()
+
+	c.MoveTo(domain.StepCompleted, now)
+	return textReply(e.promise(c, now, openCompanyDoneFormat, c.Municipality, c.State) + "\n\n" + simulationOfferText)
+}
+
+// handleQuestion answers the question of the user from the knowledge base, asking the user to pick an
+// entry when several may answer it. The conversation stays at the step for the next questions, and the
+// questions the knowledge base can not answer are handed off to the specialists.
+func (e *Engine) handleQuestion(ctx context.Context, c *domain.Conversation, answer string, now time.Time) reply {
+	if answer == "" {
+		return textReply(emptyAnswerText + "\n\n" + askQuestionText)
+	}
+
+	// Tapped rows carry the ID of the entry
+	if id, ok := strings.CutPrefix(answer, faqRowPrefix); ok {
+		entry, err := e.questions.FindFAQ(ctx, id)
+		if err != nil {
+			e.logger.Warn("Error loading FAQ entry %s picked by %s: %v", id, c.Phone, err)
+			return e.handOff(c, domain.HandoffReasonQuestion, now, unansweredFormat)
+		}
+		c.MoveTo(domain.StepQuestion, now)
+		return textReply(faqAnswerText(*entry))
+	}
+
+	match, err := e.questions.SearchFAQ(ctx, answer)
+	if err != nil {
+		e.logger.Error("Error searching the question of %s: %v", c.Phone, err)
+		return e.handOff(c, domain.HandoffReasonQuestion, now, unansweredFormat)
+	}
+	switch {
+	case match.Found():
+		c.MoveTo(domain.StepQuestion, now)
+		return textReply(faqAnswerText(*match.Entry))
+	case len(match.Candidates) > 0:
+		return faqList(match.Candidates)
+	default:
+		e.logger.Info("Question of %s not found in the knowledge base, handing off to the specialists", c.Phone)
+		return e.handOff(c, domain.HandoffReasonQuestion, now, unansweredFormat)
+	}
+}
+
+// handleRevenue keeps the monthly revenue of the tax simulation and asks for the pró-labore
+func (e *Engine) handleRevenue(c *domain.Conversation, answer string, now time.Time) reply {
+	revenue, err := domain.ParseAmount(answer)
+	if err != nil {
+		return e.misunderstood(c, now, textReply(amountErrorText(err, revenueExampleText)))
+	}
+	if revenue == 0 {
+		return e.misunderstood(c, now, textReply(zeroRevenueText))
+	}
+
+	c.Revenue = revenue
+	c.MoveTo(domain.StepSimulationProLabore, now)
+	return textReply(askProLaboreText)
+}
+
+// handleProLabore simulates the taxes with the revenue and the pró-labore informed and sends the
+// comparison of the regimes, which completes the conversation
+func (e *Engine) handleProLabore(c *domain.Conversation, answer string, now time.Time) reply {
+	proLabore, err := domain.ParseAmount(answer)
+	if err != nil {
+		return e.misunderstood(c, now, textReply(amountErrorText(err, proLaboreExampleText)))
+	}
+
+	// The revenue is valid, only a pró-labore above it is refused
+	simulation, err := domain.SimulateTaxes(c.Revenue, proLabore, e.TaxRules)
+	if err != nil {
+		return e.misunderstood(c, now, textReply(fmt.Sprintf(proLaboreTooHighFormat, formatMoney(c.Revenue))))
+	}
+
+	e.logger.Debug("Taxes of %s simulated, %s is the cheapest regime", c.Phone, simulation.Best().Regime)
+	c.MoveTo(domain.StepCompleted, now)
+	return textReply(taxSummaryText(simulation))
+}
+
+// handOff pauses the bot so that an agent answers the user, telling them when to expect the answer
+func (e *Engine) handOff(c *domain.Conversation, reason domain.HandoffReason, now time.Time, format string) reply {
+	c.HandOff(reason, now)
+	return textReply(e.promise(c, now, format))
+}
+
+// misunderstood counts an answer the bot could not understand and returns the retry, handing the
+// conversation off to the agents instead after maxMisunderstoodAnswers answers in a row
+func (e *Engine) misunderstood(c *domain.Conversation, now time.Time, retry reply) reply {
+	c.Attempts++
+	if c.Attempts < maxMisunderstoodAnswers {
+		return retry
+	}
+	e.logger.Info("Handing %s off to the agents after %d answers not understood at step %s", c.Phone, c.Attempts, c.Step)
+	return e.handOff(c, domain.HandoffReasonUnhandled, now, unhandledFormat)
+}
+
+// promise fills the format, whose last verb is when the team will answer the user. Outside business
+// hours the user is told when the team starts working again, in their own time, instead of "em breve".
+func (e *Engine) promise(c *domain.Conversation, now time.Time, format string, args ...interface{}) string {
+	if e.Hours == nil || e.Hours.IsOpen(now) {
+		return fmt.Sprintf(format, append(args, soonText)...)
+	}
+
+	when := asSoonAsPossibleText
+	if opening := e.Hours.NextOpening(now); !opening.IsZero() {
+		loc := c.Location()
+		when = openingText(opening.In(loc), now.In(loc))
+	}
+	notice := closedText
+	if holiday, ok := e.Hours.HolidayOn(now); ok {
+		notice = fmt.Sprintf(holidayFormat, holiday.Name)
+	}
+	return fmt.Sprintf(format, append(args, when)...) + "\n\n" + notice
+}
+
+// normalizeInput trims the input and strips keycap emojis so that "1️⃣" is read as "1"
+func normalizeInput(text string) string {
+	text = strings.NewReplacer("\ufe0f", "", "\u20e3", "").Replace(text)
+	return strings.ToLower(strings.TrimSpace(text))
+}
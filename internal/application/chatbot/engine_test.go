@@ -0,0 +1,1454 @@
+package chatbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/ibge"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/memory"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{})  {}
+func (m *mockLogger) Error(format string, args ...interface{}) {}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+// sentMessage is a message sent through the mocked sender
+type sentMessage struct {
+	ctx     context.Context
+	to      string
+	kind    string
+	body    string
+	buttons *domain.ButtonMessage
+	list    *domain.ListMessage
+}
+
+// outbox records the messages sent through a mocked ports.MessageSender.
+// When err is set, the sends fail with it.
+type outbox struct {
+	messages []sentMessage
+	err      error
+}
+
+// newOutbox makes the sender accept any message, recording it in the returned outbox
+func newOutbox(sender *mocks.MockMessageSender) *outbox {
+	o := &outbox{}
+	sender.EXPECT().SendTextMessageContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, to, message string) (*domain.SendResult, error) {
+			return o.record(sentMessage{ctx: ctx, to: to, kind: "text", body: message})
+		}).AnyTimes()
+	sender.EXPECT().SendButtonMessageContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, to string, message domain.ButtonMessage) (*domain.SendResult, error) {
+			return o.record(sentMessage{ctx: ctx, to: to, kind: "button", body: message.Body, buttons: &message})
+		}).AnyTimes()
+	sender.EXPECT().SendListMessageContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, to string, message domain.ListMessage) (*domain.SendResult, error) {
+			return o.record(sentMessage{ctx: ctx, to: to, kind: "list", body: message.Body, list: &message})
+		}).AnyTimes()
+	sender.EXPECT().SendTemplateMessageContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, to string, template domain.TemplateMessage) (*domain.SendResult, error) {
+			return o.record(sentMessage{ctx: ctx, to: to, kind: "template", body: template.Name})
+		}).AnyTimes()
+	sender.EXPECT().SendMediaMessageContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, to string, message domain.MediaMessage) (*domain.SendResult, error) {
+			return o.record(sentMessage{ctx: ctx, to: to, kind: string(message.Type), body: message.Caption})
+		}).AnyTimes()
+	return o
+}
+
+func (o *outbox) record(message sentMessage) (*domain.SendResult, error) {
+	o.messages = append(o.messages, message)
+	if o.err != nil {
+		return nil, o.err
+	}
+	return &domain.SendResult{MessageID: fmt.Sprintf("wamid.out.%d", len(o.messages))}, nil
+}
+
+func (o *outbox) lastMessage() sentMessage {
+	if len(o.messages) == 0 {
+		return sentMessage{}
+	}
+	return o.messages[len(o.messages)-1]
+}
+
+func (o *outbox) last() string {
+	return o.lastMessage().body
+}
+
+var (
+	maringa     = domain.Municipality{Code: "4115200", Name: "Maringá", UF: "PR"}
+	cascavelCE  = domain.Municipality{Code: "2303501", Name: "Cascavel", UF: "CE"}
+	vilaBela    = domain.Municipality{Code: "5105507", Name: "Vila Bela da Santíssima Trindade", UF: "MT"}
+	nossaSenhor = domain.Municipality{Code: "5106000", Name: "Nossa Senhora do Livramento", UF: "MT"}
+
+	// testStates and testMunicipalities are the matches of the mocked location catalog for
+	// lowercase texts. Municipalities are keyed by "UF|text".
+	testStates = map[string]domain.StateMatch{
+		"pr":     {UF: "PR"},
+		"paraná": {UF: "PR"},
+		"mt":     {UF: "MT"},
+		"rio":    {Candidates: []string{"RJ", "RS", "RN"}},
+		"rj":     {UF: "RJ"},
+	}
+	testMunicipalities = map[string]domain.MunicipalityMatch{
+		"PR|maringá":     {Municipality: &maringa},
+		"PR|cascavel/ce": {Municipality: &cascavelCE},
+		"MT|n":           {Candidates: []domain.Municipality{vilaBela, nossaSenhor}},
+		"MT|5105507":     {Municipality: &vilaBela},
+	}
+)
+
+// newLocationCatalog returns a mocked ports.LocationCatalog answering with testStates and testMunicipalities
+func newLocationCatalog(ctrl *gomock.Controller) *mocks.MockLocationCatalog {
+	catalog := mocks.NewMockLocationCatalog(ctrl)
+	catalog.EXPECT().MatchState(gomock.Any()).DoAndReturn(func(text string) domain.StateMatch {
+		return testStates[strings.ToLower(text)]
+	}).AnyTimes()
+	catalog.EXPECT().MatchMunicipality(gomock.Any(), gomock.Any()).DoAndReturn(func(uf, text string) domain.MunicipalityMatch {
+		return testMunicipalities[uf+"|"+strings.ToLower(text)]
+	}).AnyTimes()
+	return catalog
+}
+
+var (
+	proLaboreEntry = domain.FAQEntry{ID: "pro-labore", Question: "O que é o pró-labore?", Answer: "É a remuneração dos sócios."}
+	valorEntry     = domain.FAQEntry{ID: "valor-pro-labore", Question: "Qual deve ser o valor do pró-labore?", Answer: "Pelo menos um salário mínimo."}
+)
+
+const testPhone = "554499887766"
+
+// brasilia is the zone of the test phone, from Paraná
+var brasilia = domain.Timezone(testPhone, "", "")
+
+// newTestEngine creates an engine backed by an in-memory conversation repository and mocks of
+// the other ports. The sent messages are recorded in the returned outbox; the other mocks accept
+// any call, so tests checking them replace them with mocks of their own.
+func newTestEngine(t *testing.T, at time.Time) (*Engine, *outbox, *memory.ConversationRepository) {
+	ctrl := gomock.NewController(t)
+	sender := mocks.NewMockMessageSender(ctrl)
+	repo := memory.NewConversationRepository()
+	media := mocks.NewMockMediaIngester(ctrl)
+	leads := mocks.NewMockLeadCapturer(ctrl)
+	handoffs := mocks.NewMockHandoffRequester(ctrl)
+	crms := mocks.NewMockCRMVerifier(ctrl)
+
+	media.EXPECT().Ingest(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, msg domain.InboundMessage) (*domain.StoredMedia, error) {
+		return &domain.StoredMedia{MessageID: msg.ID, MediaID: msg.Media.ID}, nil
+	}).AnyTimes()
+	leads.EXPECT().CaptureLead(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	crms.EXPECT().VerifyCRM(gomock.Any(), gomock.Any()).Return(domain.CRMUnverified, nil).AnyTimes()
+
+	engine := NewEngine(sender, repo, media, leads, handoffs, crms, newLocationCatalog(ctrl), mocks.NewMockFAQSearcher(ctrl), &mockLogger{})
+	engine.clock = clock.Fixed(at)
+	return engine, newOutbox(sender), repo
+}
+
+// storedConversation returns the conversation saved for the phone, or an empty one
+func storedConversation(repo *memory.ConversationRepository, phone string) domain.Conversation {
+	conversation, err := repo.FindByPhone(context.Background(), phone)
+	if err != nil {
+		return domain.Conversation{}
+	}
+	return *conversation
+}
+
+func send(t *testing.T, engine *Engine, text string) {
+	t.Helper()
+	err := engine.ProcessMessage(context.Background(), domain.InboundMessage{
+		ID:   "wamid.test",
+		From: testPhone,
+		Type: domain.MessageTypeText,
+		Text: text,
+	})
+	assert.NoError(t, err)
+}
+
+func TestEngineGreeting(t *testing.T) {
+	tests := []struct {
+		hour     int
+		expected string
+	}{
+		{hour: 8, expected: "Bom dia"},
+		{hour: 14, expected: "Boa tarde"},
+		{hour: 21, expected: "Boa noite"},
+		{hour: 3, expected: "Boa noite"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should greet with "+tt.expected, func(t *testing.T) {
+			// arrange
+			engine, sender, repo := newTestEngine(t, time.Date(2025, 3, 10, tt.hour, 0, 0, 0, brasilia))
+
+			// act
+			send(t, engine, "Oi")
+
+			// assert
+			assert.Len(t, sender.messages, 1)
+			assert.Equal(t, testPhone, sender.messages[0].to)
+			assert.Contains(t, sender.last(), tt.expected)
+			assert.Equal(t, "list", sender.lastMessage().kind)
+			assert.Len(t, sender.lastMessage().list.Sections[0].Rows, 4)
+			assert.Equal(t, "Quero abrir empresa", sender.lastMessage().list.Sections[0].Rows[1].Title)
+			assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+		})
+	}
+
+	t.Run("should greet with the time of the area code of the phone", func(t *testing.T) {
+		// arrange
+		engine, sender, _ := newTestEngine(t, time.Date(2025, 3, 10, 13, 0, 0, 0, brasilia))
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: "556899887766", Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, sender.last(), "Bom dia")
+	})
+
+	t.Run("should greet with the time of the state informed in a previous conversation", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, time.Date(2025, 3, 10, 12, 30, 0, 0, brasilia))
+		conversation := domain.NewConversation(testPhone, time.Date(2025, 3, 10, 12, 0, 0, 0, brasilia))
+		conversation.State = "MT"
+		conversation.Locate()
+		conversation.MoveTo(domain.StepCompleted, conversation.StartedAt)
+		repo.Save(context.Background(), conversation)
+
+		// act
+		send(t, engine, "Oi")
+
+		// assert
+		assert.Contains(t, sender.last(), "Bom dia")
+		assert.Equal(t, "America/Cuiaba", storedConversation(repo, testPhone).Timezone)
+	})
+
+	t.Run("should greet with the time of a municipality whose zone differs from its state", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, time.Date(2025, 3, 10, 13, 30, 0, 0, brasilia))
+		catalog, err := ibge.NewCatalog()
+		assert.NoError(t, err)
+		engine.locations = catalog
+		phone := "559799887766"
+		for _, text := range []string{"Oi", "2", "2", "Pediatria", "Amazonas", "Eirunepé"} {
+			assert.NoError(t, engine.ProcessMessage(context.Background(), domain.InboundMessage{From: phone, Text: text}))
+		}
+		code := storedConversation(repo, phone).MunicipalityCode
+
+		// act
+		err = engine.ProcessMessage(context.Background(), domain.InboundMessage{From: phone, Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1301407", code)
+		assert.Equal(t, "America/Eirunepe", storedConversation(repo, phone).Timezone)
+		assert.Contains(t, sender.last(), "Bom dia")
+	})
+}
+
+func TestEngineFlow(t *testing.T) {
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia)
+
+	t.Run("should walk the open company flow", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+
+		// act & assert
+		send(t, engine, "Olá")
+		send(t, engine, "2")
+		assert.Contains(t, sender.last(), crmQuestion)
+		assert.Equal(t, "button", sender.lastMessage().kind)
+		assert.Len(t, sender.lastMessage().buttons.Buttons, 2)
+
+		send(t, engine, "1️⃣")
+		assert.Equal(t, askCRMText, sender.last())
+
+		send(t, engine, "crm/pr 12.345")
+		assert.Equal(t, askSpecialtyText, sender.last())
+
+		send(t, engine, " Pediatria ")
+		assert.Equal(t, askStateText, sender.last())
+
+		send(t, engine, " Paraná ")
+		assert.Equal(t, askMunicipalityText, sender.last())
+
+		send(t, engine, "Maringá")
+		assert.Contains(t, sender.last(), "Maringá/PR")
+		assert.Contains(t, sender.last(), simulationOfferText)
+
+		conversation := storedConversation(repo, testPhone)
+		assert.Equal(t, domain.StepCompleted, conversation.Step)
+		assert.Equal(t, domain.OptionOpenCompany, conversation.MenuOption)
+		assert.True(t, conversation.HasCRM)
+		assert.Equal(t, domain.CRM{Number: "12345", State: "PR"}, conversation.CRM)
+		assert.Equal(t, "Pediatria", conversation.Specialty)
+		assert.Equal(t, "PR", conversation.State)
+		assert.Equal(t, "Maringá", conversation.Municipality)
+		assert.Equal(t, "4115200", conversation.MunicipalityCode)
+		assert.Equal(t, "America/Sao_Paulo", conversation.Timezone)
+	})
+
+	t.Run("should complete the conversation of users who have a company", func(t *testing.T) {
+		// arrange
+		engine, _, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+
+		// act
+		send(t, engine, "1")
+
+		// assert
+		assert.Equal(t, domain.OptionHasCompany, storedConversation(repo, testPhone).MenuOption)
+		assert.Equal(t, domain.StepCompleted, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should repeat the main menu on invalid option", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+
+		// act
+		send(t, engine, "7")
+
+		// assert
+		assert.Contains(t, sender.last(), invalidOptionText)
+		assert.Contains(t, sender.last(), mainMenuQuestion)
+		assert.Equal(t, "list", sender.lastMessage().kind)
+		assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should repeat the CRM question on invalid answer", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+		send(t, engine, "2")
+
+		// act
+		send(t, engine, "talvez")
+
+		// assert
+		assert.Contains(t, sender.last(), invalidOptionText)
+		assert.Contains(t, sender.last(), crmQuestion)
+		assert.Equal(t, domain.StepCRMQuestion, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should restart the flow on menu keyword", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+		send(t, engine, "2")
+
+		// act
+		send(t, engine, "Menu")
+
+		// assert
+		assert.Contains(t, sender.last(), "Bom dia")
+		assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+		assert.Empty(t, storedConversation(repo, testPhone).MenuOption)
+	})
+
+	t.Run("should restart the flow after a completed conversation", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+		send(t, engine, "1")
+
+		// act
+		send(t, engine, "Oi de novo")
+
+		// assert
+		assert.Contains(t, sender.last(), mainMenuQuestion)
+		assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should restart the flow after the session timeout", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+		send(t, engine, "2")
+		engine.clock = clock.Fixed(now.Add(DefaultSessionTimeout + time.Minute))
+
+		// act
+		send(t, engine, "1")
+
+		// assert
+		assert.Contains(t, sender.last(), mainMenuQuestion)
+		assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+	})
+}
+
+func TestEngineCRM(t *testing.T) {
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia)
+
+	// newCRMEngine creates an engine waiting for the CRM number, checking it with the returned verifier
+	newCRMEngine := func(t *testing.T) (*Engine, *outbox, *memory.ConversationRepository, *mocks.MockCRMVerifier) {
+		engine, sender, repo := newTestEngine(t, now)
+		verifier := mocks.NewMockCRMVerifier(gomock.NewController(t))
+		engine.crms = verifier
+		send(t, engine, "Oi")
+		send(t, engine, "2")
+		send(t, engine, "1")
+		return engine, sender, repo, verifier
+	}
+
+	t.Run("should skip the CRM number when the user has no CRM", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+		send(t, engine, "2")
+
+		// act
+		send(t, engine, "2")
+
+		// assert
+		assert.Equal(t, askSpecialtyText, sender.last())
+		assert.False(t, storedConversation(repo, testPhone).HasCRM)
+		assert.Empty(t, storedConversation(repo, testPhone).CRM.Number)
+	})
+
+	t.Run("should ask the CRM again explaining what is wrong", func(t *testing.T) {
+		inputs := map[string]string{
+			"SP":                "Não encontramos o número do CRM",
+			"CRM-SP 1234567890": "até 7 números",
+			"123456":            "Faltou o estado",
+			"CRM-XX 123456":     "sigla da UF",
+		}
+
+		for input, text := range inputs {
+			// arrange
+			engine, sender, repo, _ := newCRMEngine(t)
+
+			// act
+			send(t, engine, input)
+
+			// assert
+			assert.Contains(t, sender.last(), text, input)
+			assert.Contains(t, sender.last(), "CRM-SP 123456", input)
+			assert.Equal(t, domain.StepCRMNumber, storedConversation(repo, testPhone).Step, input)
+		}
+	})
+
+	t.Run("should keep the result of the verification", func(t *testing.T) {
+		// arrange
+		engine, _, repo, verifier := newCRMEngine(t)
+		verifier.EXPECT().VerifyCRM(gomock.Any(), domain.CRM{Number: "123456", State: "SP"}).Return(domain.CRMVerified, nil)
+
+		// act
+		send(t, engine, "CRM-SP 123456")
+
+		// assert
+		assert.True(t, storedConversation(repo, testPhone).CRMVerified)
+		assert.Equal(t, domain.StepSpecialty, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should keep the suffix of the CRM", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, verifier := newCRMEngine(t)
+		verifier.EXPECT().VerifyCRM(gomock.Any(), domain.CRM{Number: "123456", State: "SP", Suffix: "P"}).Return(domain.CRMUnverified, nil)
+
+		// act
+		send(t, engine, "CRM-SP 123456-P")
+
+		// assert
+		assert.Equal(t, domain.CRM{Number: "123456", State: "SP", Suffix: "P"}, storedConversation(repo, testPhone).CRM)
+		assert.Equal(t, askSpecialtyText, sender.last())
+	})
+
+	t.Run("should ask the CRM again when the council does not know it", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, verifier := newCRMEngine(t)
+		verifier.EXPECT().VerifyCRM(gomock.Any(), gomock.Any()).Return(domain.CRMNotFound, nil)
+
+		// act
+		send(t, engine, "CRM-SP 123456")
+
+		// assert
+		assert.Equal(t, fmt.Sprintf(crmNotFoundFormat, "CRM-SP 123456"), sender.last())
+		assert.Equal(t, domain.StepCRMNumber, storedConversation(repo, testPhone).Step)
+		assert.Empty(t, storedConversation(repo, testPhone).CRM.Number)
+	})
+
+	t.Run("should accept the CRM unverified when the council can not be reached", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, verifier := newCRMEngine(t)
+		verifier.EXPECT().VerifyCRM(gomock.Any(), gomock.Any()).Return(domain.CRMUnverified, errors.New("timeout"))
+
+		// act
+		send(t, engine, "CRM-SP 123456")
+
+		// assert
+		assert.Equal(t, askSpecialtyText, sender.last())
+		assert.Equal(t, "123456", storedConversation(repo, testPhone).CRM.Number)
+		assert.False(t, storedConversation(repo, testPhone).CRMVerified)
+	})
+}
+
+func TestEngineLocations(t *testing.T) {
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia)
+
+	// atState returns an engine whose conversation waits for the state
+	atState := func(t *testing.T) (*Engine, *outbox, *memory.ConversationRepository) {
+		engine, sender, repo := newTestEngine(t, now)
+		for _, text := range []string{"Oi", "2", "2", "Pediatria"} {
+			send(t, engine, text)
+		}
+		return engine, sender, repo
+	}
+
+	// tap sends the tapped row of a list message
+	tap := func(t *testing.T, engine *Engine, row domain.ListRow) {
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{
+			From:       testPhone,
+			Type:       domain.MessageTypeInteractive,
+			Text:       row.Title,
+			ReplyID:    row.ID,
+			ReplyTitle: row.Title,
+		})
+		assert.NoError(t, err)
+	}
+
+	t.Run("should list the states of an ambiguous answer and accept the tapped one", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := atState(t)
+
+		// act
+		send(t, engine, "Rio")
+		list := sender.lastMessage().list
+		step := storedConversation(repo, testPhone).Step
+		tap(t, engine, list.Sections[0].Rows[0])
+
+		// assert
+		assert.Equal(t, domain.StepState, step)
+		assert.Equal(t, ambiguousStateText, list.Body)
+		assert.Equal(t, []domain.ListRow{
+			{ID: "RJ", Title: "Rio de Janeiro", Description: "RJ"},
+			{ID: "RS", Title: "Rio Grande do Sul", Description: "RS"},
+			{ID: "RN", Title: "Rio Grande do Norte", Description: "RN"},
+		}, list.Sections[0].Rows)
+		assert.Equal(t, askMunicipalityText, sender.last())
+		assert.Equal(t, "RJ", storedConversation(repo, testPhone).State)
+	})
+
+	t.Run("should ask again for an unknown state", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := atState(t)
+
+		// act
+		send(t, engine, "Atlântida")
+
+		// assert
+		assert.Equal(t, stateNotFoundText, sender.last())
+		assert.Equal(t, domain.StepState, storedConversation(repo, testPhone).Step)
+		assert.Empty(t, storedConversation(repo, testPhone).State)
+	})
+
+	t.Run("should list the municipalities of an ambiguous answer with short titles", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := atState(t)
+		send(t, engine, "MT")
+
+		// act
+		send(t, engine, "N")
+		list := sender.lastMessage().list
+		tap(t, engine, list.Sections[0].Rows[0])
+
+		// assert
+		assert.Equal(t, ambiguousMunicipalityText, list.Body)
+		assert.Equal(t, domain.ListRow{
+			ID:          "5105507",
+			Title:       "Vila Bela da Santíssima…",
+			Description: "Vila Bela da Santíssima Trindade/MT",
+		}, list.Sections[0].Rows[0])
+		assert.Len(t, []rune(list.Sections[0].Rows[0].Title), maxRowTitleLength)
+		conversation := storedConversation(repo, testPhone)
+		assert.Equal(t, domain.StepCompleted, conversation.Step)
+		assert.Equal(t, "Vila Bela da Santíssima Trindade", conversation.Municipality)
+		assert.Equal(t, "5105507", conversation.MunicipalityCode)
+	})
+
+	t.Run("should move to the state typed along with the municipality", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := atState(t)
+		send(t, engine, "PR")
+
+		// act
+		send(t, engine, "Cascavel/CE")
+
+		// assert
+		assert.Contains(t, sender.last(), "Cascavel/CE")
+		assert.Equal(t, "CE", storedConversation(repo, testPhone).State)
+		assert.Equal(t, "2303501", storedConversation(repo, testPhone).MunicipalityCode)
+	})
+
+	t.Run("should keep an unknown municipality as typed after asking again", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := atState(t)
+		send(t, engine, "PR")
+
+		// act
+		send(t, engine, "Nova Esperança do Sul")
+		first := sender.last()
+		attempts := storedConversation(repo, testPhone).Attempts
+		send(t, engine, "Nova Esperança do Sul")
+
+		// assert
+		assert.Equal(t, municipalityNotFoundText, first)
+		assert.Equal(t, 1, attempts)
+		conversation := storedConversation(repo, testPhone)
+		assert.Equal(t, domain.StepCompleted, conversation.Step)
+		assert.Equal(t, "Nova Esperança do Sul", conversation.Municipality)
+		assert.Empty(t, conversation.MunicipalityCode)
+		assert.Zero(t, conversation.Attempts)
+		assert.Contains(t, sender.last(), "Nova Esperança do Sul/PR")
+	})
+}
+
+func TestEngineReplies(t *testing.T) {
+	t.Run("should use the reply ID of a tapped button as the menu option", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia))
+		send(t, engine, "Oi")
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{
+			From:       testPhone,
+			Type:       domain.MessageTypeInteractive,
+			Text:       "Quero abrir uma empresa",
+			ReplyID:    "2",
+			ReplyTitle: "Quero abrir uma empresa",
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, sender.last(), crmQuestion)
+		assert.Equal(t, domain.OptionOpenCompany, storedConversation(repo, testPhone).MenuOption)
+	})
+}
+
+func TestEngineBusinessHours(t *testing.T) {
+	newEngine := func(t *testing.T, at time.Time) (*Engine, *outbox) {
+		t.Helper()
+		engine, sender, _ := newTestEngine(t, at)
+		hours, err := domain.ParseBusinessHours("08:00-18:00", "mon-fri", brasilia)
+		assert.NoError(t, err)
+		engine.Hours = hours
+		return engine, sender
+	}
+
+	t.Run("should promise an answer soon within business hours", func(t *testing.T) {
+		// arrange
+		engine, sender := newEngine(t, time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia))
+		send(t, engine, "Oi")
+
+		// act
+		send(t, engine, "1")
+
+		// assert
+		assert.Contains(t, sender.last(), "em breve")
+		assert.NotContains(t, sender.last(), closedText)
+	})
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected string
+	}{
+		{name: "later today", at: time.Date(2025, 3, 10, 6, 0, 0, 0, brasilia), expected: "hoje, a partir das 08:00"},
+		{name: "tomorrow", at: time.Date(2025, 3, 10, 20, 0, 0, 0, brasilia), expected: "amanhã, a partir das 08:00"},
+		{name: "after the weekend", at: time.Date(2025, 3, 15, 10, 0, 0, 0, brasilia), expected: "a partir de segunda-feira, 17/03, às 08:00"},
+	}
+	for _, tt := range tests {
+		t.Run("should tell after hours users the team answers "+tt.name, func(t *testing.T) {
+			// arrange
+			engine, sender := newEngine(t, tt.at)
+			send(t, engine, "Oi")
+
+			// act
+			send(t, engine, "1")
+
+			// assert
+			assert.Contains(t, sender.last(), tt.expected)
+			assert.Contains(t, sender.last(), closedText)
+			assert.NotContains(t, sender.last(), "em breve")
+		})
+	}
+
+	t.Run("should tell users the name of the holiday", func(t *testing.T) {
+		// arrange
+		engine, sender := newEngine(t, time.Date(2025, 4, 18, 10, 0, 0, 0, brasilia))
+		send(t, engine, "Oi")
+
+		// act
+		send(t, engine, "4")
+
+		// assert
+		assert.Contains(t, sender.last(), "a partir de terça-feira, 22/04, às 08:00")
+		assert.Contains(t, sender.last(), "Hoje é feriado (Sexta-feira Santa)")
+	})
+
+	t.Run("should tell the opening in the time of the user", func(t *testing.T) {
+		// arrange
+		engine, sender := newEngine(t, time.Date(2025, 3, 10, 20, 0, 0, 0, brasilia))
+		acre := "556899887766"
+		assert.NoError(t, engine.ProcessMessage(context.Background(), domain.InboundMessage{From: acre, Text: "Oi"}))
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: acre, Text: "1"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, sender.last(), "amanhã, a partir das 06:00")
+	})
+}
+
+func TestEngineHandoff(t *testing.T) {
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia)
+	// newEngine creates an engine requesting the handoffs to the returned requester
+	newEngine := func(t *testing.T) (*Engine, *outbox, *memory.ConversationRepository, *mocks.MockHandoffRequester) {
+		engine, sender, repo := newTestEngine(t, now)
+		handoffs := mocks.NewMockHandoffRequester(gomock.NewController(t))
+		engine.handoffs = handoffs
+		return engine, sender, repo, handoffs
+	}
+
+	t.Run("should hand the other main menu option off to the agents", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, handoffs := newEngine(t)
+		send(t, engine, "Oi")
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), "Dra. Ana").Return(nil)
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, ProfileName: "Dra. Ana", Text: "4"})
+
+		// assert
+		assert.NoError(t, err)
+		conversation := storedConversation(repo, testPhone)
+		assert.Equal(t, domain.StepHandoff, conversation.Step)
+		assert.Equal(t, domain.OptionOther, conversation.MenuOption)
+		assert.Equal(t, domain.HandoffReasonOther, conversation.HandoffReason)
+		assert.Contains(t, sender.last(), "atendentes responderá em breve")
+	})
+
+	t.Run("should hand off users asking for a person at any step", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, handoffs := newEngine(t)
+		send(t, engine, "Oi")
+		send(t, engine, "2")
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		send(t, engine, "Atendente")
+
+		// assert
+		assert.Equal(t, domain.StepHandoff, storedConversation(repo, testPhone).Step)
+		assert.Equal(t, domain.HandoffReasonRequested, storedConversation(repo, testPhone).HandoffReason)
+		assert.Contains(t, sender.last(), "Vamos transferir você")
+	})
+
+	t.Run("should hand off users whose answers are not understood", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, handoffs := newEngine(t)
+		send(t, engine, "Oi")
+		send(t, engine, "quero falar sobre impostos")
+		send(t, engine, "impostos")
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		send(t, engine, "???")
+
+		// assert
+		assert.Equal(t, domain.StepHandoff, storedConversation(repo, testPhone).Step)
+		assert.Equal(t, domain.HandoffReasonUnhandled, storedConversation(repo, testPhone).HandoffReason)
+		assert.Contains(t, sender.last(), "não consegui entender")
+	})
+
+	t.Run("should count only the answers not understood in a row at the same step", func(t *testing.T) {
+		// arrange
+		engine, _, repo, _ := newEngine(t)
+		send(t, engine, "Oi")
+		send(t, engine, "x")
+		send(t, engine, "x")
+
+		// act
+		send(t, engine, "2")
+		send(t, engine, "x")
+
+		// assert
+		assert.Equal(t, domain.StepCRMQuestion, storedConversation(repo, testPhone).Step)
+		assert.Equal(t, 1, storedConversation(repo, testPhone).Attempts)
+	})
+
+	t.Run("should not promise an immediate answer outside business hours", func(t *testing.T) {
+		// arrange
+		engine, sender, _, handoffs := newEngine(t)
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		engine.clock = clock.Fixed(time.Date(2025, 3, 10, 20, 0, 0, 0, brasilia))
+		hours, err := domain.ParseBusinessHours("08:00-18:00", "mon-fri", brasilia)
+		assert.NoError(t, err)
+		engine.Hours = hours
+		send(t, engine, "Oi")
+
+		// act
+		send(t, engine, "atendente")
+
+		// assert
+		assert.Contains(t, sender.last(), "amanhã, a partir das 08:00")
+		assert.NotContains(t, sender.last(), "em breve")
+	})
+
+	t.Run("should stay silent while the agents answer the user", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, handoffs := newEngine(t)
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(4)
+		send(t, engine, "Oi")
+		send(t, engine, "4")
+		sent := len(sender.messages)
+
+		// act
+		for _, text := range []string{"Preciso de ajuda com a minha declaração", "menu", "1"} {
+			err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, ProfileName: "Dra. Ana", Text: text})
+			assert.NoError(t, err)
+		}
+
+		// assert
+		assert.Len(t, sender.messages, sent)
+		assert.Equal(t, domain.StepHandoff, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should not expire conversations handed off to the agents", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, handoffs := newEngine(t)
+		conversation := domain.NewConversation(testPhone, now.Add(-72*time.Hour))
+		conversation.HandOff(domain.HandoffReasonOther, conversation.StartedAt)
+		repo.Save(context.Background(), conversation)
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		send(t, engine, "Oi")
+
+		// assert
+		assert.Empty(t, sender.messages)
+		assert.Equal(t, domain.StepHandoff, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should store media of users handed off without acknowledging it", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, handoffs := newEngine(t)
+		media := mocks.NewMockMediaIngester(gomock.NewController(t))
+		engine.media = media
+		conversation := domain.NewConversation(testPhone, now)
+		conversation.HandOff(domain.HandoffReasonOther, now)
+		repo.Save(context.Background(), conversation)
+		photo := domain.InboundMessage{
+			ID:    "wamid.photo",
+			From:  testPhone,
+			Type:  domain.MessageTypeImage,
+			Media: &domain.Media{ID: "media.1", MimeType: "image/jpeg"},
+		}
+		media.EXPECT().Ingest(gomock.Any(), photo).Return(&domain.StoredMedia{MessageID: photo.ID, MediaID: "media.1"}, nil)
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		err := engine.ProcessMessage(context.Background(), photo)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, sender.messages)
+	})
+
+	t.Run("should greet users again once the agents give the conversation back", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, _ := newEngine(t)
+		conversation := domain.NewConversation(testPhone, now)
+		conversation.HandOff(domain.HandoffReasonOther, now)
+		conversation.MoveTo(domain.StepCompleted, now)
+		repo.Save(context.Background(), conversation)
+
+		// act
+		send(t, engine, "Oi")
+
+		// assert
+		assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+		assert.Empty(t, storedConversation(repo, testPhone).HandoffReason)
+		assert.Equal(t, "list", sender.lastMessage().kind)
+	})
+
+	t.Run("should return an error when the message of a user handed off can not be recorded", func(t *testing.T) {
+		// arrange
+		engine, _, repo, handoffs := newEngine(t)
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("database down"))
+		conversation := domain.NewConversation(testPhone, now)
+		conversation.HandOff(domain.HandoffReasonOther, now)
+		repo.Save(context.Background(), conversation)
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.ErrorContains(t, err, "error recording message of handoff")
+	})
+
+	t.Run("should reply when the handoff can not be opened", func(t *testing.T) {
+		// arrange
+		engine, sender, _, handoffs := newEngine(t)
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("database down"))
+		send(t, engine, "Oi")
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "4"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, sender.last(), "atendentes responderá")
+	})
+}
+
+func TestEngineQuestions(t *testing.T) {
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia)
+	// newEngine creates an engine at the main menu searching the questions with the returned searcher
+	newEngine := func(t *testing.T) (*Engine, *outbox, *memory.ConversationRepository, *mocks.MockFAQSearcher) {
+		engine, sender, repo := newTestEngine(t, now)
+		questions := mocks.NewMockFAQSearcher(gomock.NewController(t))
+		engine.questions = questions
+		send(t, engine, "Oi")
+		return engine, sender, repo, questions
+	}
+
+	t.Run("should ask for the question of the user", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, _ := newEngine(t)
+
+		// act
+		send(t, engine, "3")
+
+		// assert
+		conversation := storedConversation(repo, testPhone)
+		assert.Equal(t, domain.OptionQuestions, conversation.MenuOption)
+		assert.Equal(t, domain.StepQuestion, conversation.Step)
+		assert.Equal(t, askQuestionText, sender.last())
+	})
+
+	t.Run("should answer from the knowledge base and wait for more questions", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, questions := newEngine(t)
+		send(t, engine, "3")
+		questions.EXPECT().SearchFAQ(gomock.Any(), "O que é pró-labore?").Return(domain.FAQMatch{Entry: &proLaboreEntry}, nil)
+
+		// act
+		send(t, engine, "O que é pró-labore?")
+
+		// assert
+		assert.Equal(t, "text", sender.lastMessage().kind)
+		assert.Equal(t, "*O que é o pró-labore?*\n\nÉ a remuneração dos sócios.\n\n"+moreQuestionsText, sender.last())
+		assert.Equal(t, domain.StepQuestion, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should offer the candidates of an ambiguous question as a list", func(t *testing.T) {
+		// arrange
+		engine, sender, _, questions := newEngine(t)
+		send(t, engine, "3")
+		questions.EXPECT().SearchFAQ(gomock.Any(), "Pró-labore").Return(domain.FAQMatch{Candidates: []domain.FAQEntry{proLaboreEntry, valorEntry}}, nil)
+
+		// act
+		send(t, engine, "Pró-labore")
+
+		// assert
+		message := sender.lastMessage()
+		assert.Equal(t, "list", message.kind)
+		assert.Equal(t, ambiguousQuestionText, message.body)
+		rows := message.list.Sections[0].Rows
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "faq:pro-labore", rows[0].ID)
+		assert.Equal(t, "O que é o pró-labore?", rows[0].Title)
+		assert.Equal(t, "Qual deve ser o valor d…", rows[1].Title)
+		assert.Equal(t, "Qual deve ser o valor do pró-labore?", rows[1].Description)
+	})
+
+	t.Run("should answer the entry picked from the list", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, questions := newEngine(t)
+		questions.EXPECT().SearchFAQ(gomock.Any(), "Pró-labore").Return(domain.FAQMatch{Candidates: []domain.FAQEntry{proLaboreEntry, valorEntry}}, nil)
+		questions.EXPECT().FindFAQ(gomock.Any(), "valor-pro-labore").Return(&valorEntry, nil)
+		send(t, engine, "3")
+		send(t, engine, "Pró-labore")
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, ReplyID: "faq:valor-pro-labore", Text: "Qual deve ser o valor d…"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, sender.last(), "Pelo menos um salário mínimo.")
+		assert.Equal(t, domain.StepQuestion, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should hand the questions not found off to the specialists", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, questions := newEngine(t)
+		handoffs := mocks.NewMockHandoffRequester(gomock.NewController(t))
+		engine.handoffs = handoffs
+		send(t, engine, "3")
+		questions.EXPECT().SearchFAQ(gomock.Any(), "Vocês atendem dentistas?").Return(domain.FAQMatch{}, nil)
+		handoffs.EXPECT().RequestHandoff(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		send(t, engine, "Vocês atendem dentistas?")
+
+		// assert
+		conversation := storedConversation(repo, testPhone)
+		assert.Equal(t, domain.StepHandoff, conversation.Step)
+		assert.Equal(t, domain.HandoffReasonQuestion, conversation.HandoffReason)
+		assert.Contains(t, sender.last(), "especialistas, que responderá em breve")
+	})
+
+	t.Run("should hand the question off when the knowledge base fails", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, questions := newEngine(t)
+		questions.EXPECT().SearchFAQ(gomock.Any(), gomock.Any()).Return(domain.FAQMatch{}, errors.New("database down"))
+		send(t, engine, "3")
+
+		// act
+		send(t, engine, "O que é pró-labore?")
+
+		// assert
+		assert.Equal(t, domain.StepHandoff, storedConversation(repo, testPhone).Step)
+		assert.Contains(t, sender.last(), "Não encontrei uma resposta")
+	})
+
+	t.Run("should hand off the entries picked after they were deleted", func(t *testing.T) {
+		// arrange
+		engine, _, repo, questions := newEngine(t)
+		questions.EXPECT().FindFAQ(gomock.Any(), "removed").Return(nil, domain.ErrNotFound)
+		send(t, engine, "3")
+
+		// act
+		engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, ReplyID: "faq:removed"})
+
+		// assert
+		assert.Equal(t, domain.StepHandoff, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should ask again for empty questions", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, _ := newEngine(t)
+		send(t, engine, "3")
+
+		// act
+		send(t, engine, "   ")
+
+		// assert
+		assert.Contains(t, sender.last(), askQuestionText)
+		assert.Equal(t, domain.StepQuestion, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should go back to the main menu", func(t *testing.T) {
+		// arrange
+		engine, sender, repo, _ := newEngine(t)
+		send(t, engine, "3")
+
+		// act
+		send(t, engine, "menu")
+
+		// assert
+		assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+		assert.Contains(t, sender.last(), mainMenuQuestion)
+	})
+}
+
+func TestEngineTaxSimulation(t *testing.T) {
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, brasilia)
+
+	t.Run("should simulate the taxes with the figures of the user", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "Oi")
+
+		// act & assert
+		send(t, engine, "Simular")
+		assert.Equal(t, askRevenueText, sender.last())
+		assert.Equal(t, domain.StepSimulationRevenue, storedConversation(repo, testPhone).Step)
+
+		send(t, engine, "R$ 30.000,00")
+		assert.Equal(t, askProLaboreText, sender.last())
+		assert.Equal(t, domain.StepSimulationProLabore, storedConversation(repo, testPhone).Step)
+		assert.Equal(t, 30_000.0, storedConversation(repo, testPhone).Revenue)
+
+		send(t, engine, "3 mil")
+		assert.Contains(t, sender.last(), "📊 *Simulação de impostos*")
+		assert.Contains(t, sender.last(), "🏆 Simples Nacional, Anexo III com pró-labore de R$ 8.400,00")
+		assert.Equal(t, domain.StepCompleted, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should start the simulation after the open company flow", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		for _, text := range []string{"Oi", "2", "2", "Pediatria", "PR", "Maringá"} {
+			send(t, engine, text)
+		}
+
+		// act
+		send(t, engine, "simulação")
+
+		// assert
+		assert.Equal(t, askRevenueText, sender.last())
+		assert.Equal(t, domain.StepSimulationRevenue, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should leave the open company flow when the simulation starts in the middle of it", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		leads := mocks.NewMockLeadCapturer(gomock.NewController(t))
+		engine.leads = leads
+		var captured []domain.Conversation
+		leads.EXPECT().CaptureLead(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, conversation *domain.Conversation, name string) error {
+			captured = append(captured, *conversation)
+			return nil
+		}).AnyTimes()
+		for _, text := range []string{"Oi", "2", "1", "CRM-PR 12345", "Pediatria", "PR"} {
+			send(t, engine, text)
+		}
+
+		// act
+		send(t, engine, "simular")
+		send(t, engine, "30000")
+		send(t, engine, "3000")
+
+		// assert
+		assert.Contains(t, sender.last(), "📊 *Simulação de impostos*")
+		conversation := storedConversation(repo, testPhone)
+		assert.Equal(t, domain.StepCompleted, conversation.Step)
+		assert.Empty(t, conversation.MenuOption)
+		assert.Empty(t, conversation.CRM.Number)
+		assert.Empty(t, conversation.State)
+		last := captured[len(captured)-1]
+		assert.Equal(t, domain.StepCompleted, last.Step)
+		assert.Empty(t, last.MenuOption)
+	})
+
+	t.Run("should ask again for amounts not understood", func(t *testing.T) {
+		inputs := map[string]string{
+			"não sei":   amountErrorText(domain.ErrAmountRequired, revenueExampleText),
+			"10.00.000": amountErrorText(domain.ErrAmountInvalid, revenueExampleText),
+			"0":         zeroRevenueText,
+		}
+
+		for input, expected := range inputs {
+			// arrange
+			engine, sender, repo := newTestEngine(t, now)
+			send(t, engine, "simular")
+
+			// act
+			send(t, engine, input)
+
+			// assert
+			assert.Equal(t, expected, sender.last(), input)
+			assert.Equal(t, domain.StepSimulationRevenue, storedConversation(repo, testPhone).Step, input)
+			assert.Equal(t, 1, storedConversation(repo, testPhone).Attempts, input)
+		}
+	})
+
+	t.Run("should refuse a pró-labore above the revenue", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, now)
+		send(t, engine, "simular")
+		send(t, engine, "10000")
+
+		// act
+		send(t, engine, "12000")
+
+		// assert
+		assert.Equal(t, "O pró-labore não pode ser maior que o faturamento de R$ 10.000,00. "+proLaboreExampleText, sender.last())
+		assert.Equal(t, domain.StepSimulationProLabore, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should hand off after three amounts not understood", func(t *testing.T) {
+		// arrange
+		engine, _, repo := newTestEngine(t, now)
+		send(t, engine, "simular")
+
+		// act
+		for i := 0; i < maxMisunderstoodAnswers; i++ {
+			send(t, engine, "depende")
+		}
+
+		// assert
+		assert.Equal(t, domain.StepHandoff, storedConversation(repo, testPhone).Step)
+		assert.Equal(t, domain.HandoffReasonUnhandled, storedConversation(repo, testPhone).HandoffReason)
+	})
+
+	t.Run("should forget the revenue when the conversation restarts", func(t *testing.T) {
+		// arrange
+		engine, _, repo := newTestEngine(t, now)
+		send(t, engine, "simular")
+		send(t, engine, "10000")
+
+		// act
+		send(t, engine, "menu")
+
+		// assert
+		assert.Zero(t, storedConversation(repo, testPhone).Revenue)
+	})
+}
+
+func TestTaxSummaryText(t *testing.T) {
+	t.Run("should compare the regimes, the cheapest first", func(t *testing.T) {
+		// arrange
+		simulation, _ := domain.SimulateTaxes(30_000, 3_000, domain.TaxRules2026)
+
+		// act
+		text := taxSummaryText(simulation)
+
+		// assert
+		assert.Equal(t, "📊 *Simulação de impostos*\n\n"+
+			"Faturamento: R$ 30.000,00 por mês\n"+
+			"Pró-labore: R$ 3.000,00 (Fator R de 10,00%)\n\n"+
+			"No Simples Nacional, com o Fator R abaixo de 28%, a empresa fica no Anexo V, com alíquota efetiva de 16,75%. "+
+			"Com um pró-labore de R$ 8.400,00, ela iria para o Anexo III, com 8,60%.\n\n"+
+			"*Impostos por mês*, somando os da empresa ao INSS e IR do médico:\n"+
+			"🏆 Simples Nacional, Anexo III com pró-labore de R$ 8.400,00: R$ 4.651,17 (15,50%)\n"+
+			"• Simples Nacional, Anexo V: R$ 5.355,00 (17,85%)\n"+
+			"• Lucro Presumido: R$ 5.829,00 (19,43%)\n"+
+			"• Pessoa física (carnê-leão): R$ 8.570,22 (28,57%)\n\n"+
+			fmt.Sprintf(simulationNoticeFormat, 2026), text)
+	})
+
+	t.Run("should tell a company in Anexo III its rate", func(t *testing.T) {
+		// arrange
+		simulation, _ := domain.SimulateTaxes(10_000, 5_000, domain.TaxRules2026)
+
+		// act
+		text := taxSummaryText(simulation)
+
+		// assert
+		assert.Contains(t, text, "a empresa fica no Anexo III, com alíquota efetiva de 6,00% (seria 15,50% no Anexo V)")
+		assert.Contains(t, text, "🏆 Simples Nacional, Anexo III: R$ 1.150,00 (11,50%)")
+	})
+
+	t.Run("should leave the Simples Nacional out above its limit", func(t *testing.T) {
+		// arrange
+		simulation, _ := domain.SimulateTaxes(500_000, 20_000, domain.TaxRules2026)
+
+		// act
+		text := taxSummaryText(simulation)
+
+		// assert
+		assert.Contains(t, text, "passa do limite do Simples Nacional")
+		assert.NotContains(t, text, "Anexo")
+		assert.Contains(t, text, "🏆 Lucro Presumido: R$ ")
+	})
+}
+
+func TestFormatMoney(t *testing.T) {
+	amounts := map[float64]string{
+		0:            "R$ 0,00",
+		999.999:      "R$ 1.000,00",
+		1_518:        "R$ 1.518,00",
+		30_000.5:     "R$ 30.000,50",
+		1_234_567.89: "R$ 1.234.567,89",
+	}
+
+	for amount, expected := range amounts {
+		assert.Equal(t, expected, formatMoney(amount))
+	}
+}
+
+func TestEngineContext(t *testing.T) {
+	t.Run("should send the reply with the context of the inbound message", func(t *testing.T) {
+		// arrange
+		type key struct{}
+		engine, sender, _ := newTestEngine(t, time.Now())
+		ctx := context.WithValue(context.Background(), key{}, "request-1")
+
+		// act
+		err := engine.ProcessMessage(ctx, domain.InboundMessage{From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "request-1", sender.lastMessage().ctx.Value(key{}))
+	})
+}
+
+func TestEngineWithMocks(t *testing.T) {
+	t.Run("should save the conversation before replying with the main menu", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		sender := mocks.NewMockMessageSender(ctrl)
+		conversations := mocks.NewMockConversationRepository(ctrl)
+		leads := mocks.NewMockLeadCapturer(ctrl)
+		engine := NewEngine(sender, conversations, mocks.NewMockMediaIngester(ctrl), leads, mocks.NewMockHandoffRequester(ctrl), mocks.NewMockCRMVerifier(ctrl), mocks.NewMockLocationCatalog(ctrl), mocks.NewMockFAQSearcher(ctrl), &mockLogger{})
+
+		conversations.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		save := conversations.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, c *domain.Conversation) error {
+			assert.Equal(t, domain.StepMainMenu, c.Step)
+			return nil
+		})
+		leads.EXPECT().CaptureLead(gomock.Any(), gomock.Any(), "").After(save).Return(nil)
+		sender.EXPECT().SendListMessageContext(gomock.Any(), testPhone, gomock.Any()).After(save).Return(&domain.SendResult{MessageID: "wamid.out.1"}, nil)
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not reply when the conversation cannot be saved", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		sender := mocks.NewMockMessageSender(ctrl)
+		conversations := mocks.NewMockConversationRepository(ctrl)
+		engine := NewEngine(sender, conversations, mocks.NewMockMediaIngester(ctrl), mocks.NewMockLeadCapturer(ctrl), mocks.NewMockHandoffRequester(ctrl), mocks.NewMockCRMVerifier(ctrl), mocks.NewMockLocationCatalog(ctrl), mocks.NewMockFAQSearcher(ctrl), &mockLogger{})
+
+		conversations.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		conversations.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("database down"))
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.ErrorContains(t, err, "error saving conversation")
+	})
+}
+
+func TestEngineLeads(t *testing.T) {
+	t.Run("should capture the lead with the answers of every step", func(t *testing.T) {
+		// arrange
+		engine, _, _ := newTestEngine(t, time.Now())
+		leads := mocks.NewMockLeadCapturer(gomock.NewController(t))
+		engine.leads = leads
+		var captured []domain.Conversation
+		leads.EXPECT().CaptureLead(gomock.Any(), gomock.Any(), "Dra. Ana").DoAndReturn(func(ctx context.Context, conversation *domain.Conversation, name string) error {
+			captured = append(captured, *conversation)
+			return nil
+		}).Times(7)
+
+		// act
+		for _, text := range []string{"Oi", "2", "1", "CRM-PR 12345", "Pediatria", "PR", "Maringá"} {
+			err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, ProfileName: "Dra. Ana", Text: text})
+			assert.NoError(t, err)
+		}
+
+		// assert
+		last := captured[6]
+		assert.Equal(t, domain.StepCompleted, last.Step)
+		assert.Equal(t, domain.OptionOpenCompany, last.MenuOption)
+		assert.Equal(t, "12345", last.CRM.Number)
+		assert.Equal(t, "Pediatria", last.Specialty)
+		assert.Equal(t, "Maringá", last.Municipality)
+	})
+
+	t.Run("should reply when the lead can not be captured", func(t *testing.T) {
+		// arrange
+		engine, sender, _ := newTestEngine(t, time.Now())
+		leads := mocks.NewMockLeadCapturer(gomock.NewController(t))
+		leads.EXPECT().CaptureLead(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("database down")).AnyTimes()
+		engine.leads = leads
+		send(t, engine, "Oi")
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "2"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "button", sender.lastMessage().kind)
+	})
+}
+
+func TestEngineMedia(t *testing.T) {
+	photo := domain.InboundMessage{
+		ID:    "wamid.photo",
+		From:  testPhone,
+		Type:  domain.MessageTypeImage,
+		Media: &domain.Media{ID: "media.1", MimeType: "image/jpeg"},
+	}
+
+	t.Run("should store media without moving the conversation forward", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, time.Now())
+		media := mocks.NewMockMediaIngester(gomock.NewController(t))
+		engine.media = media
+		media.EXPECT().Ingest(gomock.Any(), photo).Return(&domain.StoredMedia{MessageID: photo.ID, MediaID: "media.1"}, nil)
+		send(t, engine, "Oi")
+		send(t, engine, "2")
+		send(t, engine, "1")
+
+		// act
+		err := engine.ProcessMessage(context.Background(), photo)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, mediaReceivedText, sender.last())
+		assert.Equal(t, domain.StepCRMNumber, storedConversation(repo, testPhone).Step)
+	})
+
+	t.Run("should notify the user when media cannot be stored", func(t *testing.T) {
+		// arrange
+		engine, sender, _ := newTestEngine(t, time.Now())
+		media := mocks.NewMockMediaIngester(gomock.NewController(t))
+		media.EXPECT().Ingest(gomock.Any(), photo).Return(nil, errors.New("hash mismatch"))
+		engine.media = media
+
+		// act
+		err := engine.ProcessMessage(context.Background(), photo)
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error receiving media")
+		assert.Equal(t, mediaFailedText, sender.last())
+	})
+}
+
+func TestEngineErrors(t *testing.T) {
+	t.Run("should return error when the conversation cannot be loaded", func(t *testing.T) {
+		// arrange
+		engine, sender, _ := newTestEngine(t, time.Now())
+		conversations := mocks.NewMockConversationRepository(gomock.NewController(t))
+		conversations.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, errors.New("database down"))
+		engine.conversations = conversations
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error loading conversation")
+		assert.Empty(t, sender.messages)
+	})
+
+	t.Run("should return error when the reply cannot be sent", func(t *testing.T) {
+		// arrange
+		engine, sender, _ := newTestEngine(t, time.Now())
+		sender.err = errors.New("API error")
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error sending reply")
+	})
+
+	t.Run("should not fail when the recipient can not receive messages", func(t *testing.T) {
+		// arrange
+		engine, sender, repo := newTestEngine(t, time.Now())
+		sender.err = fmt.Errorf("undeliverable: %w", domain.ErrRecipientUnavailable)
+
+		// act
+		err := engine.ProcessMessage(context.Background(), domain.InboundMessage{From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StepMainMenu, storedConversation(repo, testPhone).Step)
+	})
+}
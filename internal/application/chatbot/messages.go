@@ -0,0 +1,306 @@
+package chatbot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+const (
+	// maxRowTitleLength is the maximum length of the title of a list row accepted by WhatsApp
+	maxRowTitleLength = 24
+	// maxRowDescriptionLength is the maximum length of the description of a list row accepted by WhatsApp
+	maxRowDescriptionLength = 72
+)
+
+// faqRowPrefix marks the reply IDs of the rows picking an entry of the knowledge base
+const faqRowPrefix = "faq:"
+
+const (
+	mainMenuQuestion         = "Como podemos ajudar?"
+	crmQuestion              = "Você já possui CRM?"
+	invalidOptionText        = "Desculpe, não entendi sua resposta. 😕"
+	hasCompanyFormat         = "Que ótimo! Um de nossos contadores entrará em contato %s para conhecer a sua empresa. 🩺"
+	askQuestionText          = "Claro! Qual é a sua dúvida? Escreva em poucas palavras, por exemplo: Como funciona o pró-labore? 📚"
+	unansweredFormat         = "Não encontrei uma resposta para a sua dúvida. 🤔 Vamos encaminhá-la para um de nossos especialistas, que responderá %s. 📚"
+	moreQuestionsText        = "Ficou com outra dúvida? É só enviar. Para falar com um especialista, digite *atendente*, ou *menu* para voltar ao início."
+	ambiguousQuestionText    = "Encontramos mais de um assunto parecido com a sua dúvida. Qual deles?"
+	questionListFooter       = "Se não estiver na lista, escreva de outro jeito"
+	otherFormat              = "Certo! Conte pra gente como podemos ajudar e um de nossos atendentes responderá %s. 💬"
+	requestedFormat          = "Certo! Vamos transferir você para um de nossos atendentes, que responderá %s. 💬"
+	unhandledFormat          = "Desculpe, não consegui entender. 😕 Vamos transferir você para um de nossos atendentes, que responderá %s. 💬"
+	soonText                 = "em breve"
+	asSoonAsPossibleText     = "assim que possível"
+	closedText               = "⏰ No momento estamos fora do horário de atendimento."
+	holidayFormat            = "⏰ Hoje é feriado (%s) e nosso time não está atendendo."
+	askCRMText               = "Qual é o seu CRM? Informe o número e o estado, por exemplo: CRM-SP 123456"
+	crmExampleText           = "Envie o número e o estado, por exemplo: CRM-SP 123456"
+	crmNotFoundFormat        = "Não encontramos o %s no Conselho Federal de Medicina. 😕 Confira o número e o estado e envie novamente."
+	askSpecialtyText         = "Qual é a sua especialidade? Por exemplo: Pediatria. Se ainda não tem, envie *nenhuma*."
+	askStateText             = "Em qual Estado você pretende atuar?"
+	askMunicipalityText      = "E em qual Município?"
+	stateNotFoundText        = "Não reconhecemos esse estado. 😕 Envie o nome ou a sigla da UF, por exemplo: Paraná ou PR."
+	ambiguousStateText       = "Encontramos mais de um estado parecido. Qual deles?"
+	municipalityNotFoundText = "Não encontramos esse município no estado informado. 😕 Confira o nome e envie novamente. " +
+		"Se ele for de outro estado, envie também a UF, por exemplo: Maringá - PR"
+	ambiguousMunicipalityText = "Encontramos mais de um município parecido. Qual deles?"
+	locationListFooter        = "Se não estiver na lista, digite novamente"
+	mediaReceivedText         = "Recebemos o seu arquivo, obrigado! 📎"
+	mediaFailedText           = "Não conseguimos receber o seu arquivo. 😕 Por favor, tente enviá-lo novamente."
+	emptyAnswerText           = "Não recebemos uma resposta válida. Por favor, tente novamente."
+	openCompanyDoneFormat     = "Obrigado! Registramos seu interesse em abrir uma empresa em %s/%s. " +
+		"Um de nossos especialistas entrará em contato %s. 🚀"
+	simulationOfferText = "Enquanto isso, quer saber quanto pagaria de impostos? Digite *simular*. 🧮"
+	askRevenueText      = "Vamos simular quanto você pagaria de impostos! 🧮 Qual é o faturamento mensal previsto? " +
+		"Por exemplo: R$ 30.000,00"
+	askProLaboreText       = "E quanto pretende retirar de pró-labore por mês? Por exemplo: R$ 5.000,00. Se ainda não sabe, envie 0."
+	revenueExampleText     = "Envie o faturamento mensal em reais, por exemplo: R$ 30.000,00"
+	proLaboreExampleText   = "Envie o pró-labore mensal em reais, por exemplo: R$ 5.000,00"
+	zeroRevenueText        = "O faturamento precisa ser maior que zero. " + revenueExampleText
+	proLaboreTooHighFormat = "O pró-labore não pode ser maior que o faturamento de %s. " + proLaboreExampleText
+	simulationNoticeFormat = "_Estimativa com as regras de %d, o ISS máximo no Lucro Presumido, sem despesas dedutíveis " +
+		"nem a tributação dos lucros distribuídos. Para um cálculo exato, digite *atendente* e fale com um de nossos contadores._"
+)
+
+// crmErrorText returns the message asking the user to inform the CRM again after an invalid answer
+func crmErrorText(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrCRMNumberRequired):
+		return "Não encontramos o número do CRM na sua resposta. " + crmExampleText
+	case errors.Is(err, domain.ErrCRMNumberInvalid):
+		return "O número do CRM deve ter apenas dígitos, com até 7 números. " + crmExampleText
+	case errors.Is(err, domain.ErrCRMStateRequired):
+		return "Faltou o estado (UF) do seu CRM. " + crmExampleText
+	case errors.Is(err, domain.ErrCRMStateInvalid):
+		return "Não reconhecemos o estado do CRM. Use a sigla da UF. " + crmExampleText
+	default:
+		return invalidOptionText + " " + crmExampleText
+	}
+}
+
+// amountErrorText returns the message asking the user to inform an amount again after an invalid answer
+func amountErrorText(err error, example string) string {
+	if errors.Is(err, domain.ErrAmountRequired) {
+		return "Não encontramos um valor na sua resposta. " + example
+	}
+	return "Não entendemos esse valor. 😕 " + example
+}
+
+// taxSummaryText returns the comparison of the monthly taxes in each regime, the cheapest first
+func taxSummaryText(s *domain.TaxSimulation) string {
+	var b strings.Builder
+	b.WriteString("📊 *Simulação de impostos*\n\n")
+	fmt.Fprintf(&b, "Faturamento: %s por mês\n", formatMoney(s.Revenue))
+	fmt.Fprintf(&b, "Pró-labore: %s (Fator R de %s)\n\n", formatMoney(s.ProLabore), formatPercent(s.FatorR))
+
+	simples, ok := s.Simples()
+	switch {
+	case !ok:
+		b.WriteString("O faturamento passa do limite do Simples Nacional, de R$ 4,8 milhões por ano, e a comparação fica entre os demais regimes.\n\n")
+	case simples.Regime == domain.TaxRegimeSimplesAnexoIII:
+		fmt.Fprintf(&b, "No Simples Nacional, com o Fator R de 28%% ou mais, a empresa fica no Anexo III, com alíquota efetiva de %s (seria %s no Anexo V).\n\n",
+			formatPercent(s.AnexoIIIRate), formatPercent(s.AnexoVRate))
+	default:
+		fmt.Fprintf(&b, "No Simples Nacional, com o Fator R abaixo de 28%%, a empresa fica no Anexo V, com alíquota efetiva de %s. "+
+			"Com um pró-labore de %s, ela iria para o Anexo III, com %s.\n\n",
+			formatPercent(s.AnexoVRate), formatMoney(s.FatorRProLabore), formatPercent(s.AnexoIIIRate))
+	}
+
+	b.WriteString("*Impostos por mês*, somando os da empresa ao INSS e IR do médico:\n")
+	for i, estimate := range s.Estimates {
+		bullet := "•"
+		if i == 0 {
+			bullet = "🏆"
+		}
+		fmt.Fprintf(&b, "%s %s: %s (%s)\n", bullet, regimeName(s, estimate), formatMoney(estimate.Total()), formatPercent(estimate.Total()/s.Revenue))
+	}
+
+	b.WriteString("\n" + fmt.Sprintf(simulationNoticeFormat, s.Year))
+	return b.String()
+}
+
+// regimeName returns the name of the regime of the estimate, with its pró-labore when it is not the informed one
+func regimeName(s *domain.TaxSimulation, estimate domain.TaxEstimate) string {
+	var name string
+	switch estimate.Regime {
+	case domain.TaxRegimeSimplesAnexoIII:
+		name = "Simples Nacional, Anexo III"
+	case domain.TaxRegimeSimplesAnexoV:
+		name = "Simples Nacional, Anexo V"
+	case domain.TaxRegimeLucroPresumido:
+		name = "Lucro Presumido"
+	case domain.TaxRegimeCarneLeao:
+		return "Pessoa física (carnê-leão)"
+	}
+	if estimate.ProLabore != s.ProLabore {
+		name += " com pró-labore de " + formatMoney(estimate.ProLabore)
+	}
+	return name
+}
+
+// formatMoney formats the amount in reais, like "R$ 1.234,56"
+func formatMoney(amount float64) string {
+	integer, cents, _ := strings.Cut(fmt.Sprintf("%.2f", amount), ".")
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return "R$ " + grouped.String() + "," + cents
+}
+
+// formatPercent formats the ratio as a percentage with two decimals, like "8,60%"
+func formatPercent(ratio float64) string {
+	return strings.Replace(fmt.Sprintf("%.2f%%", ratio*100), ".", ",", 1)
+}
+
+// reply is a message sent back to the user, either plain text or interactive
+type reply struct {
+	text    string
+	buttons *domain.ButtonMessage
+	list    *domain.ListMessage
+}
+
+// textReply creates a plain text reply
+func textReply(text string) reply {
+	return reply{text: text}
+}
+
+// mainMenu returns the main menu as a list message preceded by the given intro
+func mainMenu(intro string) reply {
+	return reply{list: &domain.ListMessage{
+		Body:       intro + "\n\n" + mainMenuQuestion,
+		Footer:     "Se preferir, digite o número da opção",
+		ButtonText: "Ver opções",
+		Sections: []domain.ListSection{
+			{
+				Rows: []domain.ListRow{
+					{ID: "1", Title: "Já tenho empresa", Description: "Já tenho uma empresa médica constituída"},
+					{ID: "2", Title: "Quero abrir empresa", Description: "Quero abrir uma empresa"},
+					{ID: "3", Title: "Tirar dúvidas", Description: "Gostaria de tirar dúvidas"},
+					{ID: "4", Title: "Outros", Description: "Outros assuntos"},
+				},
+			},
+		},
+	}}
+}
+
+// crmMenu returns the CRM question as a button message preceded by the given intro
+func crmMenu(intro string) reply {
+	body := crmQuestion
+	if intro != "" {
+		body = intro + "\n\n" + crmQuestion
+	}
+	return reply{buttons: &domain.ButtonMessage{
+		Body: body,
+		Buttons: []domain.ReplyButton{
+			{ID: "1", Title: "Já tenho CRM"},
+			{ID: "2", Title: "Ainda não possuo CRM"},
+		},
+	}}
+}
+
+// stateList returns the states the user may have meant as a list message
+func stateList(ufs []string) reply {
+	rows := make([]domain.ListRow, len(ufs))
+	for i, uf := range ufs {
+		rows[i] = domain.ListRow{ID: uf, Title: domain.States[uf], Description: uf}
+	}
+	return locationList(ambiguousStateText, rows)
+}
+
+// municipalityList returns the municipalities the user may have meant as a list message,
+// identified by their IBGE code
+func municipalityList(municipalities []domain.Municipality) reply {
+	rows := make([]domain.ListRow, len(municipalities))
+	for i, m := range municipalities {
+		rows[i] = domain.ListRow{ID: m.Code, Title: truncate(m.Name, maxRowTitleLength), Description: m.String()}
+	}
+	return locationList(ambiguousMunicipalityText, rows)
+}
+
+// faqAnswerText returns the answer of the entry under its question, inviting the user to ask again
+func faqAnswerText(entry domain.FAQEntry) string {
+	return "*" + entry.Question + "*\n\n" + entry.Answer + "\n\n" + moreQuestionsText
+}
+
+// faqList returns the entries that may answer the question of the user as a list message
+func faqList(entries []domain.FAQEntry) reply {
+	rows := make([]domain.ListRow, len(entries))
+	for i, entry := range entries {
+		rows[i] = domain.ListRow{
+			ID:          faqRowPrefix + entry.ID,
+			Title:       truncate(entry.Question, maxRowTitleLength),
+			Description: truncate(entry.Question, maxRowDescriptionLength),
+		}
+	}
+	return reply{list: &domain.ListMessage{
+		Body:       ambiguousQuestionText,
+		Footer:     questionListFooter,
+		ButtonText: "Ver opções",
+		Sections:   []domain.ListSection{{Rows: rows}},
+	}}
+}
+
+// locationList returns a list message asking the user to pick one of the rows
+func locationList(body string, rows []domain.ListRow) reply {
+	return reply{list: &domain.ListMessage{
+		Body:       body,
+		Footer:     locationListFooter,
+		ButtonText: "Ver opções",
+		Sections:   []domain.ListSection{{Rows: rows}},
+	}}
+}
+
+// truncate shortens the text to max characters, marking the cut with an ellipsis
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// weekdayNames are the names of the weekdays in Portuguese
+var weekdayNames = [...]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+
+// openingText tells when the team starts working again relative to now, like "amanhã, a partir das 08:00".
+// Both times must be in the zone of the user.
+func openingText(opening, now time.Time) string {
+	hour := opening.Format("15:04")
+	switch dateOf(opening).Sub(dateOf(now)) / (24 * time.Hour) {
+	case 0:
+		return "hoje, a partir das " + hour
+	case 1:
+		return "amanhã, a partir das " + hour
+	default:
+		return fmt.Sprintf("a partir de %s, %s, às %s", weekdayNames[opening.Weekday()], opening.Format("02/01"), hour)
+	}
+}
+
+// dateOf returns the calendar date of the time, as midnight UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// greeting returns the greeting for the given time of the day
+func greeting(now time.Time) string {
+	switch hour := now.Hour(); {
+	case hour >= 5 && hour < 12:
+		return "Bom dia"
+	case hour >= 12 && hour < 18:
+		return "Boa tarde"
+	default:
+		return "Boa noite"
+	}
+}
+
+// welcomeText returns the greeting shown before the main menu
+func welcomeText(now time.Time) string {
+	return fmt.Sprintf("Olá, %s! 👋 Bem-vindo(a) à ContaMed, a contabilidade digital para médicos.", greeting(now))
+}
@@ -0,0 +1,85 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// DefaultTTL is how long handled events are remembered. WhatsApp keeps retrying
+// undelivered webhooks for up to seven days.
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Filter drops the webhook events that were already handled, so redelivered webhooks
+// do not make the bot reply twice. Messages are keyed by their ID and statuses by the
+// ID of their message and the status.
+type Filter struct {
+	messages ports.MessageProcessor
+	statuses ports.StatusProcessor
+	store    ports.DedupStore
+	logger   logger.Logger
+	TTL      time.Duration
+}
+
+var (
+	_ ports.MessageProcessor = (*Filter)(nil)
+	_ ports.StatusProcessor  = (*Filter)(nil)
+)
+
+// NewFilter creates a filter handing the events seen for the first time over to the processors
+func NewFilter(messages ports.MessageProcessor, statuses ports.StatusProcessor, store ports.DedupStore, log logger.Logger) *Filter {
+	return &Filter{
+		messages: messages,
+		statuses: statuses,
+		store:    store,
+		logger:   log,
+		TTL:      DefaultTTL,
+	}
+}
+
+// ProcessMessage processes the message unless it was already processed. When processing
+// fails the message is released, so that a redelivery can try again.
+func (f *Filter) ProcessMessage(ctx context.Context, msg domain.InboundMessage) error {
+	return f.once(ctx, "message:"+msg.ID, msg.ID != "", func() error {
+		return f.messages.ProcessMessage(ctx, msg)
+	})
+}
+
+// ProcessStatus processes the status event unless it was already processed
+func (f *Filter) ProcessStatus(ctx context.Context, event domain.StatusEvent) error {
+	key := fmt.Sprintf("status:%s:%s", event.MessageID, event.Status)
+	return f.once(ctx, key, event.MessageID != "", func() error {
+		return f.statuses.ProcessStatus(ctx, event)
+	})
+}
+
+// once runs process if the key is claimed for the first time. Events without ID and
+// events whose key can not be claimed are processed anyway, since replying twice is
+// better than not replying at all.
+func (f *Filter) once(ctx context.Context, key string, hasID bool, process func() error) error {
+	if !hasID {
+		return process()
+	}
+
+	claimed, err := f.store.Claim(ctx, key, f.TTL)
+	if err != nil {
+		f.logger.Error("Error checking duplicate %s: %v", key, err)
+		return process()
+	}
+	if !claimed {
+		f.logger.Info("Ignoring duplicate %s", key)
+		return nil
+	}
+
+	if err := process(); err != nil {
+		if releaseErr := f.store.Release(ctx, key); releaseErr != nil {
+			f.logger.Error("Error releasing %s: %v", key, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/memory"
+	"github.com/2rprbm/conta-med-backend/internal/application/worker"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{})  {}
+func (m *mockLogger) Error(format string, args ...interface{}) {}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+var message = domain.InboundMessage{ID: "wamid.1", From: "554499887766", Type: domain.MessageTypeText, Text: "Oi"}
+
+func TestFilterMessages(t *testing.T) {
+	t.Run("should process a redelivered message only once", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		processor := mocks.NewMockMessageProcessor(ctrl)
+		filter := NewFilter(processor, mocks.NewMockStatusProcessor(ctrl), memory.NewDedupStore(), &mockLogger{})
+
+		processor.EXPECT().ProcessMessage(gomock.Any(), message).Return(nil).Times(1)
+
+		// act
+		err1 := filter.ProcessMessage(context.Background(), message)
+		err2 := filter.ProcessMessage(context.Background(), message)
+
+		// assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+	})
+
+	t.Run("should process the message again when processing failed", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		processor := mocks.NewMockMessageProcessor(ctrl)
+		filter := NewFilter(processor, mocks.NewMockStatusProcessor(ctrl), memory.NewDedupStore(), &mockLogger{})
+
+		gomock.InOrder(
+			processor.EXPECT().ProcessMessage(gomock.Any(), message).Return(errors.New("send failed")),
+			processor.EXPECT().ProcessMessage(gomock.Any(), message).Return(nil),
+		)
+
+		// act
+		err1 := filter.ProcessMessage(context.Background(), message)
+		err2 := filter.ProcessMessage(context.Background(), message)
+
+		// assert
+		assert.Error(t, err1)
+		assert.NoError(t, err2)
+	})
+
+	t.Run("should accept the redelivery of a message the queue refused", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		processor := mocks.NewMockMessageProcessor(ctrl)
+		pool := worker.NewPool(processor, 1, 1, &mockLogger{})
+		filter := NewFilter(pool, mocks.NewMockStatusProcessor(ctrl), memory.NewDedupStore(), &mockLogger{})
+		busy := domain.InboundMessage{ID: "wamid.busy", From: message.From}
+		queued := domain.InboundMessage{ID: "wamid.queued", From: message.From}
+		started, release, drained := make(chan struct{}), make(chan struct{}), make(chan struct{})
+
+		processor.EXPECT().ProcessMessage(gomock.Any(), busy).DoAndReturn(func(ctx context.Context, msg domain.InboundMessage) error {
+			close(started)
+			<-release
+			return nil
+		})
+		processor.EXPECT().ProcessMessage(gomock.Any(), queued).DoAndReturn(func(ctx context.Context, msg domain.InboundMessage) error {
+			close(drained)
+			return nil
+		})
+		processor.EXPECT().ProcessMessage(gomock.Any(), message).Return(nil).Times(1)
+
+		assert.NoError(t, filter.ProcessMessage(context.Background(), busy))
+		<-started
+		assert.NoError(t, filter.ProcessMessage(context.Background(), queued))
+
+		// act
+		refusedErr := filter.ProcessMessage(context.Background(), message)
+		close(release)
+		<-drained
+		redeliveredErr := filter.ProcessMessage(context.Background(), message)
+		pool.Shutdown(context.Background())
+
+		// assert
+		assert.ErrorIs(t, refusedErr, domain.ErrOverloaded)
+		assert.NoError(t, redeliveredErr)
+	})
+
+	t.Run("should remember the messages for the configured time", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		processor := mocks.NewMockMessageProcessor(ctrl)
+		store := mocks.NewMockDedupStore(ctrl)
+		filter := NewFilter(processor, mocks.NewMockStatusProcessor(ctrl), store, &mockLogger{})
+		filter.TTL = 48 * time.Hour
+
+		store.EXPECT().Claim(gomock.Any(), "message:wamid.1", 48*time.Hour).Return(true, nil)
+		processor.EXPECT().ProcessMessage(gomock.Any(), message).Return(nil)
+
+		// act
+		err := filter.ProcessMessage(context.Background(), message)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should process the message when the store fails", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		processor := mocks.NewMockMessageProcessor(ctrl)
+		store := mocks.NewMockDedupStore(ctrl)
+		filter := NewFilter(processor, mocks.NewMockStatusProcessor(ctrl), store, &mockLogger{})
+
+		store.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, errors.New("disk full"))
+		processor.EXPECT().ProcessMessage(gomock.Any(), message).Return(nil)
+
+		// act
+		err := filter.ProcessMessage(context.Background(), message)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should process messages without ID every time", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		processor := mocks.NewMockMessageProcessor(ctrl)
+		filter := NewFilter(processor, mocks.NewMockStatusProcessor(ctrl), mocks.NewMockDedupStore(ctrl), &mockLogger{})
+		msg := domain.InboundMessage{From: "554499887766", Text: "Oi"}
+
+		processor.EXPECT().ProcessMessage(gomock.Any(), msg).Return(nil).Times(2)
+
+		// act
+		filter.ProcessMessage(context.Background(), msg)
+		filter.ProcessMessage(context.Background(), msg)
+	})
+}
+
+func TestFilterStatuses(t *testing.T) {
+	t.Run("should process each status of a message once", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		statuses := mocks.NewMockStatusProcessor(ctrl)
+		filter := NewFilter(mocks.NewMockMessageProcessor(ctrl), statuses, memory.NewDedupStore(), &mockLogger{})
+		sent := domain.StatusEvent{MessageID: "wamid.out.1", Status: domain.StatusSent}
+		delivered := domain.StatusEvent{MessageID: "wamid.out.1", Status: domain.StatusDelivered}
+
+		statuses.EXPECT().ProcessStatus(gomock.Any(), sent).Return(nil).Times(1)
+		statuses.EXPECT().ProcessStatus(gomock.Any(), delivered).Return(nil).Times(1)
+
+		// act
+		filter.ProcessStatus(context.Background(), sent)
+		filter.ProcessStatus(context.Background(), delivered)
+		filter.ProcessStatus(context.Background(), sent)
+		filter.ProcessStatus(context.Background(), delivered)
+	})
+}
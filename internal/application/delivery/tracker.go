@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// Tracker records the delivery statuses of outbound messages and answers queries about them
+type Tracker struct {
+	statuses ports.StatusRepository
+	logger   logger.Logger
+}
+
+var (
+	_ ports.StatusProcessor = (*Tracker)(nil)
+	_ ports.DeliveryTracker = (*Tracker)(nil)
+)
+
+// NewTracker creates a new delivery tracker
+func NewTracker(statuses ports.StatusRepository, log logger.Logger) *Tracker {
+	return &Tracker{
+		statuses: statuses,
+		logger:   log,
+	}
+}
+
+// ProcessStatus appends the status event to the timeline of its message
+func (t *Tracker) ProcessStatus(ctx context.Context, event domain.StatusEvent) error {
+	if event.MessageID == "" {
+		return fmt.Errorf("status event has no message ID")
+	}
+
+	if err := t.statuses.AppendStatus(ctx, event); err != nil {
+		return fmt.Errorf("error storing status: %w", err)
+	}
+
+	if event.Status == domain.StatusFailed {
+		for _, statusErr := range event.Errors {
+			t.logger.Warn("Message %s to %s failed: %d %s %s", event.MessageID, event.RecipientID, statusErr.Code, statusErr.Title, statusErr.Details)
+		}
+	} else {
+		t.logger.Debug("Message %s to %s is %s", event.MessageID, event.RecipientID, event.Status)
+	}
+	return nil
+}
+
+// Timeline returns the delivery timeline of an outbound message or domain.ErrNotFound
+func (t *Tracker) Timeline(ctx context.Context, messageID string) (*domain.DeliveryTimeline, error) {
+	events, err := t.statuses.FindStatuses(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading statuses: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &domain.DeliveryTimeline{MessageID: messageID, Events: events}, nil
+}
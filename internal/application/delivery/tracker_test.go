@@ -0,0 +1,100 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/memory"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct {
+	warnMessages []string
+}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{}) {
+	m.warnMessages = append(m.warnMessages, format)
+}
+func (m *mockLogger) Error(format string, args ...interface{}) {}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("should build the timeline of a message", func(t *testing.T) {
+		// arrange
+		tracker := NewTracker(memory.NewStatusRepository(), &mockLogger{})
+		tracker.ProcessStatus(context.Background(), domain.StatusEvent{MessageID: "wamid.1", Status: domain.StatusSent, Timestamp: now})
+		tracker.ProcessStatus(context.Background(), domain.StatusEvent{MessageID: "wamid.1", Status: domain.StatusDelivered, Timestamp: now.Add(time.Second)})
+
+		// act
+		timeline, err := tracker.Timeline(context.Background(), "wamid.1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "wamid.1", timeline.MessageID)
+		assert.Len(t, timeline.Events, 2)
+		assert.Equal(t, domain.StatusDelivered, timeline.Current())
+	})
+
+	t.Run("should log the errors of failed messages", func(t *testing.T) {
+		// arrange
+		logger := &mockLogger{}
+		tracker := NewTracker(memory.NewStatusRepository(), logger)
+
+		// act
+		err := tracker.ProcessStatus(context.Background(), domain.StatusEvent{
+			MessageID: "wamid.1",
+			Status:    domain.StatusFailed,
+			Errors:    []domain.StatusError{{Code: 131047, Title: "Re-engagement message"}},
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, logger.warnMessages, 1)
+	})
+
+	t.Run("should return not found for unknown messages", func(t *testing.T) {
+		// arrange
+		tracker := NewTracker(memory.NewStatusRepository(), &mockLogger{})
+
+		// act
+		_, err := tracker.Timeline(context.Background(), "wamid.unknown")
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should reject events without message ID", func(t *testing.T) {
+		// arrange
+		tracker := NewTracker(memory.NewStatusRepository(), &mockLogger{})
+
+		// act
+		err := tracker.ProcessStatus(context.Background(), domain.StatusEvent{Status: domain.StatusSent})
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		// arrange
+		statuses := mocks.NewMockStatusRepository(gomock.NewController(t))
+		tracker := NewTracker(statuses, &mockLogger{})
+		statuses.EXPECT().AppendStatus(gomock.Any(), gomock.Any()).Return(errors.New("database down"))
+
+		// act
+		err := tracker.ProcessStatus(context.Background(), domain.StatusEvent{MessageID: "wamid.1", Status: domain.StatusSent})
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error storing status")
+	})
+}
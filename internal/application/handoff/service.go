@@ -0,0 +1,209 @@
+package handoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// Service keeps the inbox of the agents: the conversations the bot handed off to a person.
+// The agents read the message history of the users, answer them through the message sender
+// and give the conversations back to the bot when they are done.
+type Service struct {
+	handoffs      ports.HandoffRepository
+	conversations ports.ConversationRepository
+	messages      ports.MessageRepository
+	sender        ports.MessageSender
+	events        ports.EventPublisher
+	logger        logger.Logger
+	clock         clock.Clock
+}
+
+var (
+	_ ports.HandoffRequester = (*Service)(nil)
+	_ ports.HandoffManager   = (*Service)(nil)
+)
+
+// NewService creates a new handoff service
+func NewService(handoffs ports.HandoffRepository, conversations ports.ConversationRepository, messages ports.MessageRepository, sender ports.MessageSender, events ports.EventPublisher, log logger.Logger) *Service {
+	return &Service{
+		handoffs:      handoffs,
+		conversations: conversations,
+		messages:      messages,
+		sender:        sender,
+		events:        events,
+		logger:        log,
+		clock:         clock.System,
+	}
+}
+
+// maxSaveAttempts is how many times a change is applied to the latest version of a handoff
+// when other changes keep saving it first
+const maxSaveAttempts = 3
+
+// RequestHandoff opens a handoff for a conversation handed off to the agents, or records a new
+// message of the user when the handoff is already open. Other conversations are ignored.
+func (s *Service) RequestHandoff(ctx context.Context, conversation *domain.Conversation, name string) error {
+	if !conversation.IsHandedOff() {
+		return nil
+	}
+	now := s.clock.Now()
+
+	for attempt := 1; ; attempt++ {
+		handoff, err := s.handoffs.FindByPhone(ctx, conversation.Phone)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			handoff = domain.NewHandoff(conversation.Phone, name, conversation.HandoffReason, now)
+		case err != nil:
+			return fmt.Errorf("error loading handoff: %w", err)
+		case !handoff.IsOpen():
+			// The new handoff replaces the closed one, so it continues its versions
+			version := handoff.Version
+			handoff = domain.NewHandoff(conversation.Phone, name, conversation.HandoffReason, now)
+			handoff.Version = version
+		default:
+			handoff.Receive(name, now)
+		}
+
+		err = s.save(ctx, handoff)
+		if errors.Is(err, domain.ErrConflict) && attempt < maxSaveAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+// ListHandoffs returns the handoffs selected by the filter, the longest waiting first
+func (s *Service) ListHandoffs(ctx context.Context, filter domain.HandoffFilter) ([]domain.Handoff, error) {
+	handoffs, err := s.handoffs.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing handoffs: %w", err)
+	}
+	return handoffs, nil
+}
+
+// FindHandoff returns the handoff of the given phone number
+func (s *Service) FindHandoff(ctx context.Context, phone string) (*domain.Handoff, error) {
+	handoff, err := s.handoffs.FindByPhone(ctx, phone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading handoff: %w", err)
+	}
+	return handoff, nil
+}
+
+// ListMessages returns the last limit messages exchanged with the user of a handoff,
+// including the ones exchanged with the bot before the handoff
+func (s *Service) ListMessages(ctx context.Context, phone string, limit int) ([]domain.MessageRecord, error) {
+	if _, err := s.FindHandoff(ctx, phone); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.messages.FindByPhone(ctx, phone, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error loading messages: %w", err)
+	}
+	return messages, nil
+}
+
+// Reply sends a text message of the agent to the user of an open handoff.
+// The agent takes the handoff when no agent has it yet.
+func (s *Service) Reply(ctx context.Context, phone, agent, text string) (*domain.SendResult, error) {
+	handoff, err := s.FindHandoff(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	if err := handoff.Reply(agent, now); err != nil {
+		return nil, err
+	}
+
+	result, err := s.sender.SendTextMessageContext(ctx, phone, text)
+	if err != nil {
+		return nil, fmt.Errorf("error sending reply: %w", err)
+	}
+	// The message is already sent, so the reply is returned even if the handoff fails to be saved
+	err = s.save(ctx, handoff)
+	if errors.Is(err, domain.ErrConflict) {
+		_, err = s.update(ctx, phone, func(handoff *domain.Handoff) error {
+			return handoff.Reply(agent, now)
+		})
+	}
+	if err != nil {
+		s.logger.Error("Error saving handoff of %s after a reply of %s: %v", phone, agent, err)
+	}
+	return result, nil
+}
+
+// Assign gives the handoff to the agent, transferring it from the agent who had it
+func (s *Service) Assign(ctx context.Context, phone, agent string) (*domain.Handoff, error) {
+	now := s.clock.Now()
+	return s.update(ctx, phone, func(handoff *domain.Handoff) error {
+		return handoff.Assign(agent, now)
+	})
+}
+
+// Close closes the handoff and gives the conversation back to the bot, which greets the user
+// again on their next message
+func (s *Service) Close(ctx context.Context, phone string) (*domain.Handoff, error) {
+	now := s.clock.Now()
+	return s.update(ctx, phone, func(handoff *domain.Handoff) error {
+		if err := handoff.Close(now); err != nil {
+			return err
+		}
+
+		conversation, err := s.conversations.FindByPhone(ctx, phone)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error loading conversation: %w", err)
+		}
+		if conversation != nil && conversation.IsHandedOff() {
+			conversation.MoveTo(domain.StepCompleted, now)
+			if err := s.conversations.Save(ctx, conversation); err != nil {
+				return fmt.Errorf("error saving conversation: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// update loads the handoff, applies the change and saves it. When the handoff was saved by
+// another change in the meantime, the change is applied again to the latest version.
+func (s *Service) update(ctx context.Context, phone string, change func(*domain.Handoff) error) (*domain.Handoff, error) {
+	for attempt := 1; ; attempt++ {
+		handoff, err := s.FindHandoff(ctx, phone)
+		if err != nil {
+			return nil, err
+		}
+		if err := change(handoff); err != nil {
+			return nil, err
+		}
+
+		err = s.save(ctx, handoff)
+		if errors.Is(err, domain.ErrConflict) && attempt < maxSaveAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return handoff, nil
+	}
+}
+
+// save stores the handoff and publishes its events. The handoff is already saved when an event
+// fails to be published, so publishing errors are only logged.
+func (s *Service) save(ctx context.Context, handoff *domain.Handoff) error {
+	if err := s.handoffs.Save(ctx, handoff); err != nil {
+		return fmt.Errorf("error saving handoff: %w", err)
+	}
+
+	for _, event := range handoff.PullEvents() {
+		if err := s.events.Publish(ctx, event); err != nil {
+			s.logger.Error("Error publishing %s event of handoff %s: %v", event.EventName(), handoff.Phone, err)
+		}
+	}
+	return nil
+}
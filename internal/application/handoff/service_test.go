@@ -0,0 +1,317 @@
+package handoff
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/memory"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testPhone = "554499887766"
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct {
+	errorMessages []string
+}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{})  {}
+func (m *mockLogger) Error(format string, args ...interface{}) {
+	m.errorMessages = append(m.errorMessages, fmt.Sprintf(format, args...))
+}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+// testService is a handoff service backed by in-memory repositories, a mocked sender and a fixed clock
+type testService struct {
+	*Service
+	handoffs      *memory.HandoffRepository
+	conversations *memory.ConversationRepository
+	messages      *memory.MessageRepository
+	sender        *mocks.MockMessageSender
+	events        []domain.Event
+	logger        *mockLogger
+}
+
+func newTestService(t *testing.T, now time.Time) *testService {
+	ctrl := gomock.NewController(t)
+	ts := &testService{
+		handoffs:      memory.NewHandoffRepository(),
+		conversations: memory.NewConversationRepository(),
+		messages:      memory.NewMessageRepository(),
+		sender:        mocks.NewMockMessageSender(ctrl),
+		logger:        &mockLogger{},
+	}
+	events := memory.NewEventBus()
+	events.Subscribe(domain.HandoffOpenedEvent, func(ctx context.Context, event domain.Event) error {
+		ts.events = append(ts.events, event)
+		return nil
+	})
+	ts.Service = NewService(ts.handoffs, ts.conversations, ts.messages, ts.sender, events, ts.logger)
+	ts.Service.clock = clock.Fixed(now)
+	return ts
+}
+
+// handOff saves a conversation handed off to the agents and opens its handoff
+func (ts *testService) handOff(t *testing.T, at time.Time) *domain.Conversation {
+	t.Helper()
+	conversation := domain.NewConversation(testPhone, at)
+	conversation.HandOff(domain.HandoffReasonOther, at)
+	assert.NoError(t, ts.conversations.Save(context.Background(), conversation))
+	handoff := domain.NewHandoff(testPhone, "Dra. Ana", domain.HandoffReasonOther, at)
+	handoff.PullEvents()
+	assert.NoError(t, ts.handoffs.Save(context.Background(), handoff))
+	return conversation
+}
+
+func TestServiceRequestHandoff(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should open a handoff and announce it", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		conversation := domain.NewConversation(testPhone, now)
+		conversation.HandOff(domain.HandoffReasonUnhandled, now)
+
+		// act
+		err := service.RequestHandoff(context.Background(), conversation, "Dra. Ana")
+
+		// assert
+		assert.NoError(t, err)
+		handoff, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.Equal(t, domain.HandoffWaiting, handoff.Status)
+		assert.Equal(t, domain.HandoffReasonUnhandled, handoff.Reason)
+		assert.Equal(t, "Dra. Ana", handoff.Name)
+		assert.Len(t, service.events, 1)
+	})
+
+	t.Run("should record the messages of an open handoff", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		conversation := service.handOff(t, now.Add(-time.Hour))
+
+		// act
+		err := service.RequestHandoff(context.Background(), conversation, "")
+
+		// assert
+		assert.NoError(t, err)
+		handoff, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.Equal(t, now.Add(-time.Hour), handoff.OpenedAt)
+		assert.Equal(t, now, handoff.LastMessageAt)
+		assert.Empty(t, service.events)
+	})
+
+	t.Run("should open a new handoff after the previous one was closed", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		conversation := service.handOff(t, now.Add(-time.Hour))
+		closed, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		closed.Assign("maria", now.Add(-time.Hour))
+		closed.Close(now.Add(-time.Minute))
+		service.handoffs.Save(context.Background(), closed)
+
+		// act
+		err := service.RequestHandoff(context.Background(), conversation, "")
+
+		// assert
+		assert.NoError(t, err)
+		handoff, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.Equal(t, domain.HandoffWaiting, handoff.Status)
+		assert.Empty(t, handoff.Agent)
+		assert.Equal(t, now, handoff.OpenedAt)
+		assert.Len(t, service.events, 1)
+	})
+
+	t.Run("should ignore conversations not handed off", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+
+		// act
+		err := service.RequestHandoff(context.Background(), domain.NewConversation(testPhone, now), "")
+
+		// assert
+		assert.NoError(t, err)
+		_, findErr := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.ErrorIs(t, findErr, domain.ErrNotFound)
+	})
+}
+
+func TestServiceAgents(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should list the open handoffs", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now)
+
+		// act
+		handoffs, err := service.ListHandoffs(context.Background(), domain.HandoffFilter{})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, handoffs, 1)
+	})
+
+	t.Run("should return the message history of a handoff", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now)
+		for i := 1; i <= 3; i++ {
+			service.messages.Save(context.Background(), domain.MessageRecord{
+				ID:        fmt.Sprintf("wamid.%d", i),
+				Phone:     testPhone,
+				Direction: domain.DirectionInbound,
+				Timestamp: now.Add(time.Duration(i) * time.Minute),
+			})
+		}
+
+		// act
+		messages, err := service.ListMessages(context.Background(), testPhone, 2)
+		_, unknownErr := service.ListMessages(context.Background(), "554400000000", 2)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "wamid.3", messages[1].ID)
+		assert.ErrorIs(t, unknownErr, domain.ErrNotFound)
+	})
+
+	t.Run("should send the reply and assign the handoff to the agent", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now.Add(-time.Hour))
+		service.sender.EXPECT().SendTextMessageContext(gomock.Any(), testPhone, "Olá, sou a Maria!").Return(&domain.SendResult{MessageID: "wamid.out.1"}, nil)
+
+		// act
+		result, err := service.Reply(context.Background(), testPhone, "maria", "Olá, sou a Maria!")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "wamid.out.1", result.MessageID)
+		handoff, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.Equal(t, "maria", handoff.Agent)
+		assert.Equal(t, domain.HandoffAssigned, handoff.Status)
+		assert.Equal(t, now, handoff.LastReplyAt)
+	})
+
+	t.Run("should keep the messages received while the reply was being sent", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		conversation := service.handOff(t, now.Add(-time.Hour))
+		service.sender.EXPECT().SendTextMessageContext(gomock.Any(), testPhone, "Olá!").DoAndReturn(
+			func(ctx context.Context, to, text string) (*domain.SendResult, error) {
+				assert.NoError(t, service.RequestHandoff(ctx, conversation, "Dra. Ana Souza"))
+				return &domain.SendResult{MessageID: "wamid.out.1"}, nil
+			})
+
+		// act
+		_, err := service.Reply(context.Background(), testPhone, "maria", "Olá!")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, service.logger.errorMessages)
+		handoff, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.Equal(t, "maria", handoff.Agent)
+		assert.Equal(t, now, handoff.LastReplyAt)
+		assert.Equal(t, "Dra. Ana Souza", handoff.Name)
+		assert.Equal(t, now, handoff.LastMessageAt)
+		assert.Equal(t, 3, handoff.Version)
+	})
+
+	t.Run("should not send replies of agents not assigned to the handoff", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now)
+		_, err := service.Assign(context.Background(), testPhone, "joao")
+		assert.NoError(t, err)
+
+		// act
+		result, err := service.Reply(context.Background(), testPhone, "maria", "Olá!")
+
+		// assert
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrHandoffAssigned)
+	})
+
+	t.Run("should not assign the handoff when the reply can not be sent", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now)
+		service.sender.EXPECT().SendTextMessageContext(gomock.Any(), testPhone, "Olá!").Return(nil, domain.ErrTemplateRequired)
+
+		// act
+		_, err := service.Reply(context.Background(), testPhone, "maria", "Olá!")
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrTemplateRequired)
+		handoff, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.Empty(t, handoff.Agent)
+	})
+
+	t.Run("should transfer the handoff to another agent", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now)
+		service.Assign(context.Background(), testPhone, "maria")
+
+		// act
+		handoff, err := service.Assign(context.Background(), testPhone, "joao")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "joao", handoff.Agent)
+		stored, _ := service.handoffs.FindByPhone(context.Background(), testPhone)
+		assert.Equal(t, "joao", stored.Agent)
+	})
+
+	t.Run("should give the conversation back to the bot when the handoff is closed", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now.Add(-time.Hour))
+
+		// act
+		handoff, err := service.Close(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.HandoffClosed, handoff.Status)
+		conversation, _ := service.conversations.FindByPhone(context.Background(), testPhone)
+		assert.Equal(t, domain.StepCompleted, conversation.Step)
+		assert.False(t, conversation.IsHandedOff())
+		open, _ := service.ListHandoffs(context.Background(), domain.HandoffFilter{})
+		assert.Empty(t, open)
+	})
+
+	t.Run("should not close a handoff twice", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+		service.handOff(t, now)
+		service.Close(context.Background(), testPhone)
+
+		// act
+		_, err := service.Close(context.Background(), testPhone)
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrHandoffClosed)
+	})
+
+	t.Run("should return not found for unknown handoffs", func(t *testing.T) {
+		// arrange
+		service := newTestService(t, now)
+
+		// act
+		_, assignErr := service.Assign(context.Background(), testPhone, "maria")
+		_, closeErr := service.Close(context.Background(), testPhone)
+
+		// assert
+		assert.ErrorIs(t, assignErr, domain.ErrNotFound)
+		assert.ErrorIs(t, closeErr, domain.ErrNotFound)
+	})
+}
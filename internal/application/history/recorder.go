@@ -0,0 +1,154 @@
+package history
+
+import (
+	"context"
+	"errors"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// Recorder keeps the contacts and the message history. It wraps the message processor to
+// record the inbound messages and the message sender to record the outbound ones.
+// Recording failures are logged without interrupting the conversation.
+type Recorder struct {
+	contacts ports.ContactRepository
+	messages ports.MessageRepository
+	logger   logger.Logger
+	clock    clock.Clock
+}
+
+// NewRecorder creates a new message history recorder
+func NewRecorder(contacts ports.ContactRepository, messages ports.MessageRepository, log logger.Logger) *Recorder {
+	return &Recorder{
+		contacts: contacts,
+		messages: messages,
+		logger:   log,
+		clock:    clock.System,
+	}
+}
+
+// Processor returns a message processor recording the messages before handing them over to next
+func (r *Recorder) Processor(next ports.MessageProcessor) ports.MessageProcessor {
+	return &processor{recorder: r, next: next}
+}
+
+// Sender returns a message sender recording the messages sent by next
+func (r *Recorder) Sender(next ports.MessageSender) ports.MessageSender {
+	return &sender{recorder: r, next: next}
+}
+
+// recordInbound updates the contact of the sender and stores the message
+func (r *Recorder) recordInbound(ctx context.Context, msg domain.InboundMessage) {
+	timestamp := msg.Timestamp
+	if timestamp.IsZero() {
+		timestamp = r.clock.Now()
+	}
+
+	contact, err := r.contacts.FindByPhone(ctx, msg.From)
+	if errors.Is(err, domain.ErrNotFound) {
+		contact = domain.NewContact(msg.From, timestamp)
+	} else if err != nil {
+		r.logger.Error("Error loading contact %s: %v", msg.From, err)
+		contact = nil
+	}
+	if contact != nil {
+		contact.Seen(msg.ProfileName, timestamp)
+		if err := r.contacts.Save(ctx, contact); err != nil {
+			r.logger.Error("Error saving contact %s: %v", msg.From, err)
+		}
+	}
+
+	text := msg.Text
+	if msg.ReplyTitle != "" {
+		text = msg.ReplyTitle
+	} else if msg.Media != nil && msg.Media.Caption != "" {
+		text = msg.Media.Caption
+	}
+	r.record(ctx, domain.MessageRecord{
+		ID:        msg.ID,
+		Phone:     msg.From,
+		Direction: domain.DirectionInbound,
+		Type:      msg.Type,
+		Text:      text,
+		Timestamp: timestamp,
+	})
+}
+
+// recordOutbound stores a message accepted by WhatsApp
+func (r *Recorder) recordOutbound(ctx context.Context, to string, result *domain.SendResult, messageType domain.MessageType, text string) {
+	r.record(ctx, domain.MessageRecord{
+		ID:        result.MessageID,
+		Phone:     to,
+		Direction: domain.DirectionOutbound,
+		Type:      messageType,
+		Text:      text,
+		Timestamp: r.clock.Now(),
+	})
+}
+
+// record stores the message, logging errors
+func (r *Recorder) record(ctx context.Context, message domain.MessageRecord) {
+	if err := r.messages.Save(ctx, message); err != nil {
+		r.logger.Error("Error saving %s message %s of %s: %v", message.Direction, message.ID, message.Phone, err)
+	}
+}
+
+// processor records the inbound messages
+type processor struct {
+	recorder *Recorder
+	next     ports.MessageProcessor
+}
+
+func (p *processor) ProcessMessage(ctx context.Context, msg domain.InboundMessage) error {
+	p.recorder.recordInbound(ctx, msg)
+	return p.next.ProcessMessage(ctx, msg)
+}
+
+// sender records the outbound messages
+type sender struct {
+	recorder *Recorder
+	next     ports.MessageSender
+}
+
+func (s *sender) SendTextMessageContext(ctx context.Context, to, message string) (*domain.SendResult, error) {
+	result, err := s.next.SendTextMessageContext(ctx, to, message)
+	if err == nil {
+		s.recorder.recordOutbound(ctx, to, result, domain.MessageTypeText, message)
+	}
+	return result, err
+}
+
+func (s *sender) SendButtonMessageContext(ctx context.Context, to string, message domain.ButtonMessage) (*domain.SendResult, error) {
+	result, err := s.next.SendButtonMessageContext(ctx, to, message)
+	if err == nil {
+		s.recorder.recordOutbound(ctx, to, result, domain.MessageTypeInteractive, message.Body)
+	}
+	return result, err
+}
+
+func (s *sender) SendListMessageContext(ctx context.Context, to string, message domain.ListMessage) (*domain.SendResult, error) {
+	result, err := s.next.SendListMessageContext(ctx, to, message)
+	if err == nil {
+		s.recorder.recordOutbound(ctx, to, result, domain.MessageTypeInteractive, message.Body)
+	}
+	return result, err
+}
+
+func (s *sender) SendTemplateMessageContext(ctx context.Context, to string, template domain.TemplateMessage) (*domain.SendResult, error) {
+	result, err := s.next.SendTemplateMessageContext(ctx, to, template)
+	if err == nil {
+		s.recorder.recordOutbound(ctx, to, result, domain.MessageTypeTemplate, template.Name)
+	}
+	return result, err
+}
+
+func (s *sender) SendMediaMessageContext(ctx context.Context, to string, message domain.MediaMessage) (*domain.SendResult, error) {
+	result, err := s.next.SendMediaMessageContext(ctx, to, message)
+	if err == nil {
+		s.recorder.recordOutbound(ctx, to, result, message.Type, message.Caption)
+	}
+	return result, err
+}
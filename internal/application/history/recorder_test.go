@@ -0,0 +1,221 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testPhone = "554499887766"
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct {
+	errorMessages []string
+}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{})  {}
+func (m *mockLogger) Error(format string, args ...interface{}) {
+	m.errorMessages = append(m.errorMessages, fmt.Sprintf(format, args...))
+}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+// newTestRecorder creates a recorder backed by mock repositories and a fixed clock
+func newTestRecorder(t *testing.T, now time.Time) (*Recorder, *mocks.MockContactRepository, *mocks.MockMessageRepository, *mockLogger) {
+	ctrl := gomock.NewController(t)
+	contacts := mocks.NewMockContactRepository(ctrl)
+	messages := mocks.NewMockMessageRepository(ctrl)
+	logger := &mockLogger{}
+	recorder := NewRecorder(contacts, messages, logger)
+	recorder.clock = clock.Fixed(now)
+	return recorder, contacts, messages, logger
+}
+
+func TestRecorderProcessor(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should record a new contact and the message before processing it", func(t *testing.T) {
+		// arrange
+		recorder, contacts, messages, _ := newTestRecorder(t, now)
+		next := mocks.NewMockMessageProcessor(gomock.NewController(t))
+		msg := domain.InboundMessage{ID: "wamid.1", From: testPhone, ProfileName: "Ana", Timestamp: now.Add(-time.Minute), Type: domain.MessageTypeText, Text: "Oi"}
+
+		contacts.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		saveContact := contacts.EXPECT().Save(gomock.Any(), &domain.Contact{
+			Phone:       testPhone,
+			Name:        "Ana",
+			FirstSeenAt: now.Add(-time.Minute),
+			LastSeenAt:  now.Add(-time.Minute),
+		}).Return(nil)
+		saveMessage := messages.EXPECT().Save(gomock.Any(), domain.MessageRecord{
+			ID:        "wamid.1",
+			Phone:     testPhone,
+			Direction: domain.DirectionInbound,
+			Type:      domain.MessageTypeText,
+			Text:      "Oi",
+			Timestamp: now.Add(-time.Minute),
+		}).Return(nil)
+		next.EXPECT().ProcessMessage(gomock.Any(), msg).After(saveContact).After(saveMessage).Return(nil)
+
+		// act
+		err := recorder.Processor(next).ProcessMessage(context.Background(), msg)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should update a known contact", func(t *testing.T) {
+		// arrange
+		recorder, contacts, messages, _ := newTestRecorder(t, now)
+		next := mocks.NewMockMessageProcessor(gomock.NewController(t))
+		contact := domain.NewContact(testPhone, now.Add(-24*time.Hour))
+		contact.Seen("Ana", now.Add(-24*time.Hour))
+
+		contacts.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(contact, nil)
+		contacts.EXPECT().Save(gomock.Any(), contact).Return(nil)
+		messages.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		next.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		err := recorder.Processor(next).ProcessMessage(context.Background(), domain.InboundMessage{ID: "wamid.2", From: testPhone, Timestamp: now, Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Ana", contact.Name)
+		assert.Equal(t, now.Add(-24*time.Hour), contact.FirstSeenAt)
+		assert.Equal(t, now, contact.LastSeenAt)
+	})
+
+	t.Run("should record the title of tapped options and the caption of attachments", func(t *testing.T) {
+		// arrange
+		recorder, contacts, messages, _ := newTestRecorder(t, now)
+		next := mocks.NewMockMessageProcessor(gomock.NewController(t))
+		contacts.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound).Times(2)
+		contacts.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		var texts []string
+		messages.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, message domain.MessageRecord) error {
+			texts = append(texts, message.Text)
+			return nil
+		}).Times(2)
+		next.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		// act
+		recorder.Processor(next).ProcessMessage(context.Background(), domain.InboundMessage{
+			ID: "wamid.1", From: testPhone, Type: domain.MessageTypeInteractive, ReplyID: "1", ReplyTitle: "Já tenho empresa",
+		})
+		recorder.Processor(next).ProcessMessage(context.Background(), domain.InboundMessage{
+			ID: "wamid.2", From: testPhone, Type: domain.MessageTypeDocument, Media: &domain.Media{ID: "media-1", Caption: "Diploma"},
+		})
+
+		// assert
+		assert.Equal(t, []string{"Já tenho empresa", "Diploma"}, texts)
+	})
+
+	t.Run("should use the current time for messages without timestamp", func(t *testing.T) {
+		// arrange
+		recorder, contacts, messages, _ := newTestRecorder(t, now)
+		next := mocks.NewMockMessageProcessor(gomock.NewController(t))
+		contacts.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		contacts.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		messages.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, message domain.MessageRecord) error {
+			assert.Equal(t, now, message.Timestamp)
+			return nil
+		})
+		next.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		err := recorder.Processor(next).ProcessMessage(context.Background(), domain.InboundMessage{ID: "wamid.1", From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should process the message when the history cannot be recorded", func(t *testing.T) {
+		// arrange
+		recorder, contacts, messages, logger := newTestRecorder(t, now)
+		next := mocks.NewMockMessageProcessor(gomock.NewController(t))
+		contacts.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, errors.New("database down"))
+		messages.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("database down"))
+		next.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		// act
+		err := recorder.Processor(next).ProcessMessage(context.Background(), domain.InboundMessage{ID: "wamid.1", From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"Error loading contact 554499887766: database down",
+			"Error saving inbound message wamid.1 of 554499887766: database down",
+		}, logger.errorMessages)
+	})
+
+	t.Run("should return the processing error", func(t *testing.T) {
+		// arrange
+		recorder, contacts, messages, _ := newTestRecorder(t, now)
+		next := mocks.NewMockMessageProcessor(gomock.NewController(t))
+		contacts.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		contacts.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		messages.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		next.EXPECT().ProcessMessage(gomock.Any(), gomock.Any()).Return(errors.New("send failed"))
+
+		// act
+		err := recorder.Processor(next).ProcessMessage(context.Background(), domain.InboundMessage{ID: "wamid.1", From: testPhone, Text: "Oi"})
+
+		// assert
+		assert.EqualError(t, err, "send failed")
+	})
+}
+
+func TestRecorderSender(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	result := &domain.SendResult{MessageID: "wamid.out.1"}
+
+	t.Run("should record the messages accepted by WhatsApp", func(t *testing.T) {
+		// arrange
+		recorder, _, messages, _ := newTestRecorder(t, now)
+		next := mocks.NewMockMessageSender(gomock.NewController(t))
+		next.EXPECT().SendTextMessageContext(gomock.Any(), testPhone, "Olá").Return(result, nil)
+		next.EXPECT().SendListMessageContext(gomock.Any(), testPhone, gomock.Any()).Return(result, nil)
+		next.EXPECT().SendTemplateMessageContext(gomock.Any(), testPhone, gomock.Any()).Return(result, nil)
+		var records []domain.MessageRecord
+		messages.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, message domain.MessageRecord) error {
+			records = append(records, message)
+			return nil
+		}).Times(3)
+		sender := recorder.Sender(next)
+
+		// act
+		sender.SendTextMessageContext(context.Background(), testPhone, "Olá")
+		sender.SendListMessageContext(context.Background(), testPhone, domain.ListMessage{Body: "Escolha uma opção"})
+		sender.SendTemplateMessageContext(context.Background(), testPhone, domain.TemplateMessage{Name: "follow_up"})
+
+		// assert
+		assert.Equal(t, []domain.MessageRecord{
+			{ID: "wamid.out.1", Phone: testPhone, Direction: domain.DirectionOutbound, Type: domain.MessageTypeText, Text: "Olá", Timestamp: now},
+			{ID: "wamid.out.1", Phone: testPhone, Direction: domain.DirectionOutbound, Type: domain.MessageTypeInteractive, Text: "Escolha uma opção", Timestamp: now},
+			{ID: "wamid.out.1", Phone: testPhone, Direction: domain.DirectionOutbound, Type: domain.MessageTypeTemplate, Text: "follow_up", Timestamp: now},
+		}, records)
+	})
+
+	t.Run("should not record messages that failed to be sent", func(t *testing.T) {
+		// arrange
+		recorder, _, _, _ := newTestRecorder(t, now)
+		next := mocks.NewMockMessageSender(gomock.NewController(t))
+		next.EXPECT().SendTextMessageContext(gomock.Any(), testPhone, "Olá").Return(nil, errors.New("rate limited"))
+
+		// act
+		_, err := recorder.Sender(next).SendTextMessageContext(context.Background(), testPhone, "Olá")
+
+		// assert
+		assert.EqualError(t, err, "rate limited")
+	})
+}
@@ -0,0 +1,277 @@
+package knowledge
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/pkg/textnorm"
+)
+
+// Weights of a term by the field of the entry where it appears. The highest one counts.
+const (
+	keywordWeight  = 3
+	questionWeight = 2
+	answerWeight   = 1
+)
+
+// phrases joins the expressions of several words into a single term, so that "pró-labore"
+// does not match "pro" and "imposto de renda" reads like "IRPF"
+var phrases = map[string]string{
+	"pro labore":          "prolabore",
+	"imposto de renda":    "irpf",
+	"imposto renda":       "irpf",
+	"simples nacional":    "simples",
+	"lucro presumido":     "presumido",
+	"lucro real":          "lucroreal",
+	"pessoa juridica":     "pj",
+	"pessoas juridicas":   "pj",
+	"pessoa fisica":       "pf",
+	"pessoas fisicas":     "pf",
+	"carne leao":          "carneleao",
+	"fator r":             "fatorr",
+	"livro caixa":         "livrocaixa",
+	"nota fiscal":         "notafiscal",
+	"notas fiscais":       "notafiscal",
+	"regime tributario":   "regime",
+	"regimes tributarios": "regime",
+}
+
+// maxPhraseWords is the number of words of the longest phrase
+const maxPhraseWords = 3
+
+// synonyms maps the words users type to the term used by the knowledge base
+var synonyms = map[string]string{
+	"ir":                 "irpf",
+	"leao":               "carneleao",
+	"retirada":           "prolabore",
+	"retiradas":          "prolabore",
+	"cnpj":               "pj",
+	"empresa":            "pj",
+	"empresas":           "pj",
+	"cpf":                "pf",
+	"autonomo":           "pf",
+	"autonoma":           "pf",
+	"imposto":            "tributo",
+	"impostos":           "tributo",
+	"tributacao":         "tributo",
+	"tributario":         "tributo",
+	"tributaria":         "tributo",
+	"tributos":           "tributo",
+	"dividendo":          "lucro",
+	"dividendos":         "lucro",
+	"previdencia":        "inss",
+	"aposentadoria":      "inss",
+	"nf":                 "notafiscal",
+	"nfs":                "notafiscal",
+	"nfse":               "notafiscal",
+	"microempreendedor":  "mei",
+	"microempreendedora": "mei",
+}
+
+// stopwords are the words too common in questions to tell them apart
+var stopwords = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "um": true, "uma": true, "uns": true, "umas": true,
+	"de": true, "da": true, "do": true, "das": true, "dos": true, "e": true, "em": true, "no": true,
+	"na": true, "nos": true, "nas": true, "ao": true, "aos": true, "para": true, "pra": true, "por": true,
+	"com": true, "sem": true, "que": true, "se": true, "ou": true, "mais": true, "muito": true,
+	"qual": true, "quais": true, "como": true, "quando": true, "onde": true, "porque": true,
+	"eu": true, "meu": true, "minha": true, "meus": true, "minhas": true, "voce": true, "voces": true,
+	"me": true, "sou": true, "ser": true, "tem": true, "ter": true, "tenho": true, "posso": true,
+	"pode": true, "preciso": true, "precisa": true, "devo": true, "deve": true, "quanto": true, "quanta": true,
+	"gostaria": true, "queria": true, "quero": true,
+	"saber": true, "sobre": true, "duvida": true, "duvidas": true, "ola": true, "oi": true,
+	"bom": true, "boa": true, "dia": true, "tarde": true, "noite": true, "obrigado": true, "obrigada": true,
+	"isso": true, "esse": true, "essa": true, "este": true, "esta": true, "ja": true, "nao": true, "sim": true,
+	"funciona": true, "medico": true, "medica": true, "medicos": true, "medicas": true,
+}
+
+// analyze returns the terms of the text: its words lowercased and without accents, with the
+// phrases joined, the synonyms replaced, the stopwords removed and the rest reduced to their stem
+func analyze(text string) []string {
+	words := joinPhrases(textnorm.Words(text))
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if canonical, ok := synonyms[word]; ok {
+			word = canonical
+		}
+		if stopwords[word] || len(word) < 2 {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+// joinPhrases replaces the words of the known phrases with their term, longest phrases first
+func joinPhrases(words []string) []string {
+	joined := make([]string, 0, len(words))
+	for i := 0; i < len(words); {
+		n := 1
+		word := words[i]
+		for size := maxPhraseWords; size > 1; size-- {
+			if i+size > len(words) {
+				continue
+			}
+			if term, ok := phrases[strings.Join(words[i:i+size], " ")]; ok {
+				n, word = size, term
+				break
+			}
+		}
+		joined = append(joined, word)
+		i += n
+	}
+	return joined
+}
+
+// pluralRules turn the plural endings of Portuguese into the singular, like "declarações" into "declaracao"
+var pluralRules = []struct{ suffix, singular string }{
+	{"oes", "ao"}, {"aes", "ao"}, {"ais", "al"}, {"eis", "el"}, {"ois", "ol"}, {"ns", "m"}, {"res", "r"}, {"s", ""},
+}
+
+// suffixes are the endings of the derived words and verbs, so that "declaração" and "declarar" share a stem
+var suffixes = []string{
+	"amento", "imento", "idade", "mente", "acao", "icao", "ucao", "ador", "edor", "idor",
+	"ante", "ente", "avel", "ivel", "ario", "aria", "ista", "ismo", "ar", "er", "ir",
+}
+
+// stem reduces a word without accents to its stem, removing the plural, a derivation suffix or
+// the final vowel marking the gender. It is a light stemmer: it only needs to reduce the words of
+// the users and of the knowledge base the same way.
+func stem(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+	for _, rule := range pluralRules {
+		if strings.HasSuffix(word, rule.suffix) && len(word)-len(rule.suffix) >= 3 {
+			word = strings.TrimSuffix(word, rule.suffix) + rule.singular
+			break
+		}
+	}
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	if len(word) > 4 && strings.ContainsAny(word[len(word)-1:], "aeo") {
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// questionShare is the share of the relevance given by the terms of the question of the entry
+// found in the question of the user, which ranks first the entries asking what the user asked
+const questionShare = 0.25
+
+// document is an entry of the knowledge base with the weight of each of its terms
+type document struct {
+	entry     domain.FAQEntry
+	weights   map[string]float64
+	questions []string
+}
+
+// index looks the questions of the users up among the entries of the knowledge base
+type index struct {
+	documents []document
+	// frequencies counts the documents where each term appears
+	frequencies map[string]int
+}
+
+// newIndex indexes the entries by the terms of their keywords, question, category and answer
+func newIndex(entries []domain.FAQEntry) *index {
+	ix := &index{frequencies: make(map[string]int)}
+	for _, entry := range entries {
+		doc := document{entry: entry, weights: make(map[string]float64)}
+		add := func(text string, weight float64) {
+			for _, term := range analyze(text) {
+				doc.weights[term] = math.Max(doc.weights[term], weight)
+			}
+		}
+		add(entry.Answer, answerWeight)
+		add(entry.Question, questionWeight)
+		doc.questions = unique(analyze(entry.Question))
+		add(strings.ReplaceAll(entry.Category, "-", " "), questionWeight)
+		for _, keyword := range entry.Keywords {
+			add(keyword, keywordWeight)
+		}
+
+		for term := range doc.weights {
+			ix.frequencies[term]++
+		}
+		ix.documents = append(ix.documents, doc)
+	}
+	return ix
+}
+
+// result is an entry with its relevance to a question, between 0 and 1
+type result struct {
+	entry     domain.FAQEntry
+	relevance float64
+}
+
+// search returns the entries sharing terms with the question, the most relevant first.
+// The relevance mostly adds up the weights of the terms of the question found in the entry, the
+// rarer terms counting more, relative to an entry having every term of the question as a keyword.
+// The rest is the share of the question of the entry found in the question.
+func (ix *index) search(question string) []result {
+	terms := unique(analyze(question))
+
+	var total float64
+	asked := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		total += ix.idf(term) * keywordWeight
+		asked[term] = true
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var results []result
+	for _, doc := range ix.documents {
+		var score float64
+		for _, term := range terms {
+			score += doc.weights[term] * ix.idf(term)
+		}
+		if score == 0 {
+			continue
+		}
+
+		var covered, questions float64
+		for _, term := range doc.questions {
+			questions += ix.idf(term)
+			if asked[term] {
+				covered += ix.idf(term)
+			}
+		}
+		relevance := (1 - questionShare) * score / total
+		if questions > 0 {
+			relevance += questionShare * covered / questions
+		}
+		results = append(results, result{entry: doc.entry, relevance: relevance})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].relevance > results[j].relevance
+	})
+	return results
+}
+
+// idf is the inverse document frequency of the term, which makes the terms found in few entries
+// count more than the ones found in most of them
+func (ix *index) idf(term string) float64 {
+	return math.Log(1 + float64(len(ix.documents))/float64(1+ix.frequencies[term]))
+}
+
+// unique returns the terms without repetitions, in their original order
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	result := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			result = append(result, term)
+		}
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package knowledge
+
+import (
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Run("should fold accents and drop the stopwords", func(t *testing.T) {
+		assert.Equal(t, []string{"aliquot", "simpl"}, analyze("Qual é a ALÍQUOTA do Simples?"))
+		assert.Empty(t, analyze("Olá, bom dia!"))
+	})
+
+	t.Run("should join phrases and replace synonyms", func(t *testing.T) {
+		assert.Equal(t, analyze("pró-labore"), analyze("pro labore"))
+		assert.Equal(t, analyze("prolabore"), analyze("retirada"))
+		assert.Equal(t, analyze("IRPF"), analyze("imposto de renda"))
+		assert.Equal(t, analyze("IR"), analyze("Imposto de Renda"))
+		assert.Equal(t, analyze("PJ"), analyze("pessoa jurídica"))
+		assert.Equal(t, analyze("lucros"), analyze("dividendos"))
+		assert.NotContains(t, analyze("pró-labore"), "pro")
+	})
+
+	t.Run("should reduce the inflections to the same stem", func(t *testing.T) {
+		assert.Equal(t, analyze("declaração"), analyze("declarações"))
+		assert.Equal(t, analyze("declaração"), analyze("declarar"))
+		assert.Equal(t, analyze("despesa"), analyze("despesas"))
+		assert.Equal(t, analyze("plantão"), analyze("plantões"))
+		assert.Equal(t, analyze("fiscal"), analyze("fiscais"))
+		assert.Equal(t, analyze("tributação"), analyze("tributos"))
+	})
+}
+
+func TestStem(t *testing.T) {
+	assert.Equal(t, "declar", stem("declaracoes"))
+	assert.Equal(t, "valor", stem("valores"))
+	assert.Equal(t, "anex", stem("anexos"))
+	assert.Equal(t, "iss", stem("iss"))
+	assert.Equal(t, "irpf", stem("irpf"))
+}
+
+func TestIndex(t *testing.T) {
+	entries := []domain.FAQEntry{
+		{ID: "fator-r", Question: "O que é o Fator R?", Answer: "É a razão entre a folha de pagamento e o faturamento."},
+		{ID: "pro-labore", Question: "O que é o pró-labore?", Answer: "É a remuneração dos sócios, que entra no Fator R.", Keywords: []string{"retirada"}},
+	}
+	ix := newIndex(entries)
+
+	t.Run("should rank the entry asking what the user asked first", func(t *testing.T) {
+		// act
+		results := ix.search("como funciona o fator r")
+
+		// assert
+		assert.Len(t, results, 2)
+		assert.Equal(t, "fator-r", results[0].entry.ID)
+		assert.InDelta(t, 1.0, results[0].relevance, 0.34)
+		assert.Less(t, results[1].relevance, results[0].relevance)
+	})
+
+	t.Run("should match the keywords", func(t *testing.T) {
+		// act
+		results := ix.search("Qual retirada devo fazer por mês?")
+
+		// assert
+		assert.NotEmpty(t, results)
+		assert.Equal(t, "pro-labore", results[0].entry.ID)
+	})
+
+	t.Run("should find nothing for unrelated questions", func(t *testing.T) {
+		assert.Empty(t, ix.search("Qual é o horário de vocês?"))
+		assert.Empty(t, ix.search("bom dia"))
+	})
+}
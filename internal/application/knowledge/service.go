@@ -0,0 +1,198 @@
+// Package knowledge answers the questions of the users from the knowledge base kept by the team
+package knowledge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// MaxCandidates is the maximum number of entries offered when no entry clearly answers a question
+const MaxCandidates = 3
+
+const (
+	// minRelevance is the relevance under which an entry is not offered to the user
+	minRelevance = 0.2
+	// answerRelevance is the relevance from which the best entry may be sent as the answer
+	answerRelevance = 0.4
+	// answerMargin is how many times more relevant than the second the best entry must be
+	// to be sent as the answer
+	answerMargin = 1.25
+)
+
+// Service keeps the knowledge base and looks the questions of the users up in it.
+// The knowledge base is small, so every search indexes the entries as they are stored,
+// and the edits are seen by the next search.
+type Service struct {
+	entries ports.FAQRepository
+	logger  logger.Logger
+	clock   clock.Clock
+}
+
+var (
+	_ ports.FAQSearcher = (*Service)(nil)
+	_ ports.FAQManager  = (*Service)(nil)
+)
+
+// NewService creates a new knowledge base service
+func NewService(entries ports.FAQRepository, log logger.Logger) *Service {
+	return &Service{
+		entries: entries,
+		logger:  log,
+		clock:   clock.System,
+	}
+}
+
+// Seed stores the entries of the versioned file that are new or changed since the last seed, and
+// returns the number of entries stored. The entries edited through the API are kept until the file
+// changes them, and the entries created through the API are never touched.
+func (s *Service) Seed(ctx context.Context, entries []domain.FAQEntry) (int, error) {
+	stored, err := s.entries.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error listing FAQ entries: %w", err)
+	}
+	versions := make(map[string]string, len(stored))
+	for _, entry := range stored {
+		versions[entry.ID] = entry.SeedVersion
+	}
+
+	seeded := 0
+	for _, entry := range entries {
+		entry.SeedVersion = seedVersion(entry)
+		if version, ok := versions[entry.ID]; ok && version == entry.SeedVersion {
+			continue
+		}
+		if _, err := s.save(ctx, entry); err != nil {
+			return 0, err
+		}
+		seeded++
+	}
+	return seeded, nil
+}
+
+// seedVersion returns a hash of the content of an entry of the versioned file
+func seedVersion(entry domain.FAQEntry) string {
+	h := sha256.New()
+	for _, field := range append([]string{entry.Category, entry.Question, entry.Answer}, entry.Keywords...) {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// SearchFAQ looks the question up in the knowledge base. The best entry is the answer when it is
+// relevant enough and clearly ahead of the others, else up to MaxCandidates relevant entries are
+// returned for the user to pick.
+func (s *Service) SearchFAQ(ctx context.Context, question string) (domain.FAQMatch, error) {
+	entries, err := s.entries.List(ctx)
+	if err != nil {
+		return domain.FAQMatch{}, fmt.Errorf("error listing FAQ entries: %w", err)
+	}
+
+	var relevant []result
+	for _, r := range newIndex(entries).search(question) {
+		if r.relevance >= minRelevance {
+			relevant = append(relevant, r)
+		}
+	}
+	if len(relevant) == 0 {
+		return domain.FAQMatch{}, nil
+	}
+
+	best := relevant[0]
+	if best.relevance >= answerRelevance && (len(relevant) == 1 || best.relevance >= answerMargin*relevant[1].relevance) {
+		return domain.FAQMatch{Entry: &best.entry}, nil
+	}
+
+	var match domain.FAQMatch
+	for i := 0; i < len(relevant) && i < MaxCandidates; i++ {
+		match.Candidates = append(match.Candidates, relevant[i].entry)
+	}
+	return match, nil
+}
+
+// ListFAQ returns the entries of the category, or all of them when category is empty, ordered by ID
+func (s *Service) ListFAQ(ctx context.Context, category string) ([]domain.FAQEntry, error) {
+	entries, err := s.entries.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing FAQ entries: %w", err)
+	}
+	if category == "" {
+		return entries, nil
+	}
+
+	selected := entries[:0]
+	for _, entry := range entries {
+		if entry.Category == category {
+			selected = append(selected, entry)
+		}
+	}
+	return selected, nil
+}
+
+// FindFAQ returns the entry with the given ID
+func (s *Service) FindFAQ(ctx context.Context, id string) (*domain.FAQEntry, error) {
+	entry, err := s.entries.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading FAQ entry: %w", err)
+	}
+	return entry, nil
+}
+
+// SaveFAQ validates and stores the entry, replacing the entry with the same ID. The entry keeps the
+// version of the file it was seeded from, so that the edit is not undone by the next seed.
+func (s *Service) SaveFAQ(ctx context.Context, entry domain.FAQEntry) (*domain.FAQEntry, error) {
+	stored, err := s.entries.FindByID(ctx, entry.ID)
+	switch {
+	case err == nil:
+		entry.SeedVersion = stored.SeedVersion
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, fmt.Errorf("error loading FAQ entry: %w", err)
+	}
+
+	saved, err := s.save(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("FAQ entry %s saved", saved.ID)
+	return saved, nil
+}
+
+// save trims the entry, drops its blank keywords, validates it and stores it
+func (s *Service) save(ctx context.Context, entry domain.FAQEntry) (*domain.FAQEntry, error) {
+	entry.Question = strings.TrimSpace(entry.Question)
+	entry.Answer = strings.TrimSpace(entry.Answer)
+	keywords := make([]string, 0, len(entry.Keywords))
+	for _, keyword := range entry.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	entry.Keywords = keywords
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	entry.UpdatedAt = s.clock.Now()
+	if err := s.entries.Save(ctx, &entry); err != nil {
+		return nil, fmt.Errorf("error saving FAQ entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// DeleteFAQ removes the entry with the given ID
+func (s *Service) DeleteFAQ(ctx context.Context, id string) error {
+	if err := s.entries.Delete(ctx, id); err != nil {
+		return fmt.Errorf("error deleting FAQ entry: %w", err)
+	}
+	s.logger.Info("FAQ entry %s deleted", id)
+	return nil
+}
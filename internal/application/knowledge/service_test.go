@@ -0,0 +1,270 @@
+package knowledge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/faq"
+	"github.com/2rprbm/conta-med-backend/internal/adapters/secondary/memory"
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{})  {}
+func (m *mockLogger) Error(format string, args ...interface{}) {}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+var now = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// newTestService creates a service on an empty in-memory repository with a fixed clock
+func newTestService() (*Service, *memory.FAQRepository) {
+	repo := memory.NewFAQRepository()
+	service := NewService(repo, &mockLogger{})
+	service.clock = clock.Fixed(now)
+	return service, repo
+}
+
+// newSeededService creates a service with the knowledge base embedded in the binary
+func newSeededService(t *testing.T) *Service {
+	t.Helper()
+	entries, err := faq.Entries()
+	assert.NoError(t, err)
+	service, _ := newTestService()
+	_, err = service.Seed(context.Background(), entries)
+	assert.NoError(t, err)
+	return service
+}
+
+func TestServiceSeed(t *testing.T) {
+	entries := []domain.FAQEntry{
+		{ID: "fator-r", Question: "O que é o Fator R?", Answer: "É a razão entre a folha e o faturamento."},
+		{ID: "mei", Question: "Médico pode ser MEI?", Answer: "Não."},
+	}
+
+	t.Run("should store the entries in an empty knowledge base", func(t *testing.T) {
+		// arrange
+		service, repo := newTestService()
+
+		// act
+		seeded, err := service.Seed(context.Background(), entries)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, seeded)
+		stored, _ := repo.FindByID(context.Background(), "mei")
+		assert.Equal(t, now, stored.UpdatedAt)
+	})
+
+	t.Run("should add the new entries of the file to a knowledge base already seeded", func(t *testing.T) {
+		// arrange
+		service, repo := newTestService()
+		service.Seed(context.Background(), entries[:1])
+		service.SaveFAQ(context.Background(), domain.FAQEntry{ID: "plantoes", Question: "Plantões: PJ ou PF?", Answer: "Depende."})
+
+		// act
+		seeded, err := service.Seed(context.Background(), entries)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, seeded)
+		stored, _ := repo.List(context.Background())
+		assert.Equal(t, []string{"fator-r", "mei", "plantoes"}, []string{stored[0].ID, stored[1].ID, stored[2].ID})
+	})
+
+	t.Run("should keep the entries edited through the API until the file changes them", func(t *testing.T) {
+		// arrange
+		service, repo := newTestService()
+		service.Seed(context.Background(), entries)
+		service.SaveFAQ(context.Background(), domain.FAQEntry{ID: "mei", Question: "Posso ser MEI?", Answer: "Médicos não podem ser MEI."})
+
+		// act
+		unchanged, err := service.Seed(context.Background(), entries)
+		kept, _ := repo.FindByID(context.Background(), "mei")
+		changed := []domain.FAQEntry{entries[0], {ID: "mei", Question: "Médico pode ser MEI?", Answer: "Não, a medicina não é permitida ao MEI."}}
+		updated, updateErr := service.Seed(context.Background(), changed)
+		replaced, _ := repo.FindByID(context.Background(), "mei")
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, updateErr)
+		assert.Zero(t, unchanged)
+		assert.Equal(t, "Posso ser MEI?", kept.Question)
+		assert.Equal(t, 1, updated)
+		assert.Equal(t, "Não, a medicina não é permitida ao MEI.", replaced.Answer)
+	})
+
+	t.Run("should reject invalid entries", func(t *testing.T) {
+		// arrange
+		service, _ := newTestService()
+
+		// act
+		_, err := service.Seed(context.Background(), []domain.FAQEntry{{ID: "mei", Question: "Médico pode ser MEI?"}})
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrInvalidFAQEntry)
+	})
+}
+
+func TestServiceSearchFAQ(t *testing.T) {
+	t.Run("should answer the common questions of the users", func(t *testing.T) {
+		questions := map[string]string{
+			"Como funciona o pró-labore?":              "pro-labore",
+			"quanto devo tirar de prolabore":           "valor-pro-labore",
+			"o que é fator r":                          "fator-r",
+			"Qual o melhor regime tributário?":         "melhor-regime",
+			"SIMPLES NACIONAL":                         "simples-nacional",
+			"qual a aliquota do simples":               "simples-nacional",
+			"lucro presumido":                          "lucro-presumido",
+			"carne leão":                               "carne-leao",
+			"posso ser mei?":                           "mei",
+			"Plantões: PJ ou PF?":                      "plantoes",
+			"como abrir minha empresa":                 "abrir-empresa",
+			"quais despesas posso deduzir":             "livro-caixa",
+			"qual o prazo do IR":                       "irpf-obrigatoriedade",
+			"equiparação hospitalar":                   "equiparacao-hospitalar",
+			"como declarar os lucros da empresa no IR": "irpf-empresa",
+		}
+		service := newSeededService(t)
+
+		for question, id := range questions {
+			// act
+			match, err := service.SearchFAQ(context.Background(), question)
+
+			// assert
+			assert.NoError(t, err)
+			if assert.True(t, match.Found(), question) {
+				assert.Equal(t, id, match.Entry.ID, question)
+			}
+		}
+	})
+
+	t.Run("should offer up to three candidates for ambiguous questions", func(t *testing.T) {
+		// arrange
+		service := newSeededService(t)
+
+		// act
+		match, err := service.SearchFAQ(context.Background(), "preciso declarar imposto de renda?")
+
+		// assert
+		assert.NoError(t, err)
+		assert.False(t, match.Found())
+		assert.LessOrEqual(t, len(match.Candidates), MaxCandidates)
+		var ids []string
+		for _, candidate := range match.Candidates {
+			ids = append(ids, candidate.ID)
+		}
+		assert.Contains(t, ids, "irpf-obrigatoriedade")
+		assert.Contains(t, ids, "irpf-empresa")
+	})
+
+	t.Run("should find nothing for questions out of the knowledge base", func(t *testing.T) {
+		// arrange
+		service := newSeededService(t)
+
+		for _, question := range []string{"quanto custa a mensalidade de vocês?", "bom dia", ""} {
+			// act
+			match, err := service.SearchFAQ(context.Background(), question)
+
+			// assert
+			assert.NoError(t, err)
+			assert.False(t, match.Found(), question)
+			assert.Empty(t, match.Candidates, question)
+		}
+	})
+
+	t.Run("should see the entries edited through the API", func(t *testing.T) {
+		// arrange
+		service := newSeededService(t)
+		service.SaveFAQ(context.Background(), domain.FAQEntry{
+			ID:       "mensalidade",
+			Question: "Quanto custa a mensalidade da ContaMed?",
+			Answer:   "Os planos começam em R$ 199 por mês.",
+			Keywords: []string{"preço", "custa", "planos"},
+		})
+
+		// act
+		match, err := service.SearchFAQ(context.Background(), "quanto custa a mensalidade de vocês?")
+
+		// assert
+		assert.NoError(t, err)
+		if assert.True(t, match.Found()) {
+			assert.Equal(t, "mensalidade", match.Entry.ID)
+		}
+	})
+}
+
+func TestServiceManage(t *testing.T) {
+	t.Run("should trim and save an entry", func(t *testing.T) {
+		// arrange
+		service, repo := newTestService()
+
+		// act
+		saved, err := service.SaveFAQ(context.Background(), domain.FAQEntry{
+			ID:       "mei",
+			Category: "pessoa-juridica",
+			Question: " Médico pode ser MEI? ",
+			Answer:   "Não.\n",
+			Keywords: []string{" mei ", "", "  "},
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Médico pode ser MEI?", saved.Question)
+		assert.Equal(t, []string{"mei"}, saved.Keywords)
+		assert.Equal(t, now, saved.UpdatedAt)
+		stored, _ := repo.FindByID(context.Background(), "mei")
+		assert.Equal(t, saved, stored)
+	})
+
+	t.Run("should reject invalid entries", func(t *testing.T) {
+		// arrange
+		service, repo := newTestService()
+
+		// act
+		_, err := service.SaveFAQ(context.Background(), domain.FAQEntry{ID: "mei", Question: "Médico pode ser MEI?", Answer: "  "})
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrInvalidFAQEntry)
+		_, findErr := repo.FindByID(context.Background(), "mei")
+		assert.ErrorIs(t, findErr, domain.ErrNotFound)
+	})
+
+	t.Run("should list the entries of a category", func(t *testing.T) {
+		// arrange
+		service := newSeededService(t)
+
+		// act
+		all, err := service.ListFAQ(context.Background(), "")
+		irpf, _ := service.ListFAQ(context.Background(), "irpf")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Greater(t, len(all), len(irpf))
+		assert.NotEmpty(t, irpf)
+		for _, entry := range irpf {
+			assert.Equal(t, "irpf", entry.Category)
+		}
+	})
+
+	t.Run("should delete an entry", func(t *testing.T) {
+		// arrange
+		service := newSeededService(t)
+
+		// act
+		err := service.DeleteFAQ(context.Background(), "mei")
+		_, findErr := service.FindFAQ(context.Background(), "mei")
+		againErr := service.DeleteFAQ(context.Background(), "mei")
+
+		// assert
+		assert.NoError(t, err)
+		assert.ErrorIs(t, findErr, domain.ErrNotFound)
+		assert.ErrorIs(t, againErr, domain.ErrNotFound)
+	})
+}
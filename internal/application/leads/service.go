@@ -0,0 +1,138 @@
+package leads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// Service keeps the leads of the prospects who want to open a company, feeding them with the
+// answers of the conversation flow and letting the sales team move them along the funnel.
+// The events raised by the leads are published once they are saved.
+type Service struct {
+	leads  ports.LeadRepository
+	events ports.EventPublisher
+	logger logger.Logger
+	clock  clock.Clock
+}
+
+var (
+	_ ports.LeadCapturer = (*Service)(nil)
+	_ ports.LeadManager  = (*Service)(nil)
+)
+
+// NewService creates a new lead service
+func NewService(leads ports.LeadRepository, events ports.EventPublisher, log logger.Logger) *Service {
+	return &Service{
+		leads:  leads,
+		events: events,
+		logger: log,
+		clock:  clock.System,
+	}
+}
+
+// CaptureLead creates or updates the lead of a conversation in the company opening flow,
+// qualifying it when the flow is completed. Other conversations are ignored.
+func (s *Service) CaptureLead(ctx context.Context, conversation *domain.Conversation, name string) error {
+	if conversation.MenuOption != domain.OptionOpenCompany {
+		return nil
+	}
+	now := s.clock.Now()
+
+	lead, err := s.leads.FindByPhone(ctx, conversation.Phone)
+	if errors.Is(err, domain.ErrNotFound) {
+		lead = domain.NewLead(conversation.Phone, domain.LeadSourceWhatsApp, now)
+	} else if err != nil {
+		return fmt.Errorf("error loading lead: %w", err)
+	}
+
+	if name != "" {
+		lead.Name = name
+	}
+	// The CRM answer is only known once the conversation moved past the CRM question
+	if conversation.Step != domain.StepCRMQuestion {
+		lead.HasCRM = conversation.HasCRM
+	}
+	if conversation.CRM.Number != "" {
+		lead.CRMNumber = conversation.CRM.Number
+		lead.CRMState = conversation.CRM.State
+		lead.CRMSuffix = conversation.CRM.Suffix
+		lead.CRMVerified = conversation.CRMVerified
+	}
+	if conversation.Specialty != "" {
+		lead.Specialty = conversation.Specialty
+	}
+	if conversation.State != "" {
+		lead.State = conversation.State
+	}
+	if conversation.Municipality != "" {
+		lead.Municipality = conversation.Municipality
+		lead.MunicipalityCode = conversation.MunicipalityCode
+	}
+	lead.Touch(now)
+
+	if conversation.Step == domain.StepCompleted {
+		if err := lead.Qualify(now); err != nil {
+			return err
+		}
+	}
+
+	return s.save(ctx, lead)
+}
+
+// ListLeads returns the leads selected by the filter, the most recently updated first
+func (s *Service) ListLeads(ctx context.Context, filter domain.LeadFilter) ([]domain.Lead, error) {
+	leads, err := s.leads.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing leads: %w", err)
+	}
+	return leads, nil
+}
+
+// FindLead returns the lead of the given phone number
+func (s *Service) FindLead(ctx context.Context, phone string) (*domain.Lead, error) {
+	lead, err := s.leads.FindByPhone(ctx, phone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading lead: %w", err)
+	}
+	return lead, nil
+}
+
+// MoveLead moves the lead of the given phone number to a funnel stage
+func (s *Service) MoveLead(ctx context.Context, phone string, stage domain.LeadStage) (*domain.Lead, error) {
+	if !stage.IsValid() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidLeadStage, stage)
+	}
+
+	lead, err := s.FindLead(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if err := lead.MoveTo(stage, s.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.save(ctx, lead); err != nil {
+		return nil, err
+	}
+	return lead, nil
+}
+
+// save stores the lead and publishes its events. The lead is already saved when an event
+// fails to be published, so publishing errors are only logged.
+func (s *Service) save(ctx context.Context, lead *domain.Lead) error {
+	if err := s.leads.Save(ctx, lead); err != nil {
+		return fmt.Errorf("error saving lead: %w", err)
+	}
+
+	for _, event := range lead.PullEvents() {
+		if err := s.events.Publish(ctx, event); err != nil {
+			s.logger.Error("Error publishing %s event of lead %s: %v", event.EventName(), lead.Phone, err)
+		}
+	}
+	return nil
+}
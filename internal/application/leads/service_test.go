@@ -0,0 +1,216 @@
+package leads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testPhone = "554499887766"
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct {
+	errorMessages []string
+}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{})  {}
+func (m *mockLogger) Error(format string, args ...interface{}) {
+	m.errorMessages = append(m.errorMessages, fmt.Sprintf(format, args...))
+}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+// newTestService creates a lead service backed by mocks and a fixed clock
+func newTestService(t *testing.T, now time.Time) (*Service, *mocks.MockLeadRepository, *mocks.MockEventPublisher, *mockLogger) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLeadRepository(ctrl)
+	events := mocks.NewMockEventPublisher(ctrl)
+	logger := &mockLogger{}
+	service := NewService(repo, events, logger)
+	service.clock = clock.Fixed(now)
+	return service, repo, events, logger
+}
+
+// newConversation creates a company opening conversation at the given step
+func newConversation(step domain.ConversationStep, now time.Time) *domain.Conversation {
+	conversation := domain.NewConversation(testPhone, now)
+	conversation.MenuOption = domain.OptionOpenCompany
+	conversation.MoveTo(step, now)
+	return conversation
+}
+
+func TestServiceCaptureLead(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should create a new lead when the prospect chooses to open a company", func(t *testing.T) {
+		// arrange
+		service, repo, _, _ := newTestService(t, now)
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		repo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, lead *domain.Lead) error {
+			assert.Equal(t, testPhone, lead.Phone)
+			assert.Equal(t, "Dra. Ana", lead.Name)
+			assert.Equal(t, domain.LeadSourceWhatsApp, lead.Source)
+			assert.Equal(t, domain.LeadStageNew, lead.Stage)
+			assert.Equal(t, now, lead.CreatedAt)
+			return nil
+		})
+
+		// act
+		err := service.CaptureLead(context.Background(), newConversation(domain.StepCRMQuestion, now), "Dra. Ana")
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should update the lead with the answers and keep the known values", func(t *testing.T) {
+		// arrange
+		service, repo, _, _ := newTestService(t, now)
+		lead := domain.NewLead(testPhone, domain.LeadSourceWhatsApp, now.Add(-time.Hour))
+		lead.Name = "Dra. Ana"
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(lead, nil)
+		repo.EXPECT().Save(gomock.Any(), lead).Return(nil)
+		conversation := newConversation(domain.StepMunicipality, now)
+		conversation.HasCRM = true
+		conversation.CRM = domain.CRM{Number: "123456", State: "PR", Suffix: "P"}
+		conversation.Specialty = "Pediatria"
+		conversation.State = "PR"
+
+		// act
+		err := service.CaptureLead(context.Background(), conversation, "")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Dra. Ana", lead.Name)
+		assert.True(t, lead.HasCRM)
+		assert.Equal(t, "123456", lead.CRMNumber)
+		assert.Equal(t, "P", lead.CRMSuffix)
+		assert.Equal(t, "Pediatria", lead.Specialty)
+		assert.Equal(t, "PR", lead.State)
+		assert.Equal(t, now, lead.UpdatedAt)
+		assert.Equal(t, domain.LeadStageNew, lead.Stage)
+	})
+
+	t.Run("should qualify the lead and publish the event after saving it", func(t *testing.T) {
+		// arrange
+		service, repo, events, _ := newTestService(t, now)
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		save := repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		events.EXPECT().Publish(gomock.Any(), gomock.Any()).After(save).DoAndReturn(func(ctx context.Context, event domain.Event) error {
+			qualified := event.(domain.LeadQualified)
+			assert.Equal(t, domain.LeadStageQualified, qualified.Lead.Stage)
+			assert.Equal(t, "Maringá", qualified.Lead.Municipality)
+			assert.Equal(t, "4115200", qualified.Lead.MunicipalityCode)
+			return nil
+		})
+		conversation := newConversation(domain.StepCompleted, now)
+		conversation.State = "PR"
+		conversation.Municipality = "Maringá"
+		conversation.MunicipalityCode = "4115200"
+
+		// act
+		err := service.CaptureLead(context.Background(), conversation, "Dra. Ana")
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should ignore conversations of other options", func(t *testing.T) {
+		// arrange
+		service, _, _, _ := newTestService(t, now)
+		conversation := domain.NewConversation(testPhone, now)
+		conversation.MenuOption = domain.OptionQuestions
+
+		// act
+		err := service.CaptureLead(context.Background(), conversation, "Dra. Ana")
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should keep the lead saved when the event can not be published", func(t *testing.T) {
+		// arrange
+		service, repo, events, logger := newTestService(t, now)
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+		repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+		events.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("crm down"))
+		conversation := newConversation(domain.StepCompleted, now)
+		conversation.State = "PR"
+		conversation.Municipality = "Maringá"
+
+		// act
+		err := service.CaptureLead(context.Background(), conversation, "")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Error publishing lead.qualified event of lead 554499887766: crm down"}, logger.errorMessages)
+	})
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		// arrange
+		service, repo, _, _ := newTestService(t, now)
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, errors.New("database down"))
+
+		// act
+		err := service.CaptureLead(context.Background(), newConversation(domain.StepCRMQuestion, now), "")
+
+		// assert
+		assert.ErrorContains(t, err, "error loading lead")
+	})
+}
+
+func TestServiceMoveLead(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should move the lead and save it", func(t *testing.T) {
+		// arrange
+		service, repo, _, _ := newTestService(t, now)
+		lead := domain.NewLead(testPhone, domain.LeadSourceWhatsApp, now)
+		lead.Stage = domain.LeadStageQualified
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(lead, nil)
+		repo.EXPECT().Save(gomock.Any(), lead).Return(nil)
+
+		// act
+		moved, err := service.MoveLead(context.Background(), testPhone, domain.LeadStageContacted)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.LeadStageContacted, moved.Stage)
+	})
+
+	t.Run("should reject unknown stages and invalid moves", func(t *testing.T) {
+		// arrange
+		service, repo, _, _ := newTestService(t, now)
+		lead := domain.NewLead(testPhone, domain.LeadSourceWhatsApp, now)
+		lead.Stage = domain.LeadStageConverted
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(lead, nil)
+
+		// act
+		_, unknownErr := service.MoveLead(context.Background(), testPhone, "won")
+		_, moveErr := service.MoveLead(context.Background(), testPhone, domain.LeadStageLost)
+
+		// assert
+		assert.ErrorIs(t, unknownErr, domain.ErrInvalidLeadStage)
+		assert.ErrorIs(t, moveErr, domain.ErrInvalidLeadStage)
+	})
+
+	t.Run("should return not found for unknown leads", func(t *testing.T) {
+		// arrange
+		service, repo, _, _ := newTestService(t, now)
+		repo.EXPECT().FindByPhone(gomock.Any(), testPhone).Return(nil, domain.ErrNotFound)
+
+		// act
+		_, err := service.MoveLead(context.Background(), testPhone, domain.LeadStageContacted)
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
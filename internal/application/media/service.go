@@ -0,0 +1,144 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/clock"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// ErrHashMismatch is returned when the downloaded content does not match the hash sent in the webhook
+var ErrHashMismatch = errors.New("media hash mismatch")
+
+// extensions maps the mime types commonly sent through WhatsApp to file extensions
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"audio/ogg":       ".ogg",
+	"audio/mpeg":      ".mp3",
+	"audio/mp4":       ".m4a",
+	"audio/aac":       ".aac",
+	"video/mp4":       ".mp4",
+	"video/3gpp":      ".3gp",
+}
+
+// Service downloads the media attached to inbound messages, verifies their hash and stores them
+type Service struct {
+	downloader ports.MediaDownloader
+	store      ports.MediaStore
+	logger     logger.Logger
+	clock      clock.Clock
+}
+
+var _ ports.MediaIngester = (*Service)(nil)
+
+// NewService creates a new media ingestion service
+func NewService(downloader ports.MediaDownloader, store ports.MediaStore, log logger.Logger) *Service {
+	return &Service{
+		downloader: downloader,
+		store:      store,
+		logger:     log,
+		clock:      clock.System,
+	}
+}
+
+// Ingest downloads the media of the message, verifies it and persists it in the media store
+func (s *Service) Ingest(ctx context.Context, msg domain.InboundMessage) (*domain.StoredMedia, error) {
+	if msg.Media == nil {
+		return nil, fmt.Errorf("message %s has no media", msg.ID)
+	}
+
+	data, err := s.downloader.DownloadMediaContext(ctx, msg.Media.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading media: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if msg.Media.SHA256 == "" {
+		s.logger.Warn("Media %s of message %s has no hash to verify", msg.Media.ID, msg.ID)
+	} else if !matchesHash(sum[:], msg.Media.SHA256) {
+		return nil, fmt.Errorf("%w: media %s of message %s", ErrHashMismatch, msg.Media.ID, msg.ID)
+	}
+
+	location, err := s.store.Save(ctx, fileName(msg), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error storing media: %w", err)
+	}
+
+	s.logger.Info("Stored media %s from %s at %s", msg.Media.ID, msg.From, location)
+	return &domain.StoredMedia{
+		MessageID: msg.ID,
+		From:      msg.From,
+		MediaID:   msg.Media.ID,
+		MimeType:  msg.Media.MimeType,
+		SHA256:    hex.EncodeToString(sum[:]),
+		Filename:  msg.Media.Filename,
+		Caption:   msg.Media.Caption,
+		Location:  location,
+		Size:      int64(len(data)),
+		StoredAt:  s.clock.Now(),
+	}, nil
+}
+
+// matchesHash compares the digest with the expected hash, which WhatsApp may send hex or base64 encoded
+func matchesHash(sum []byte, expected string) bool {
+	expected = strings.TrimSpace(expected)
+	if strings.EqualFold(hex.EncodeToString(sum), expected) {
+		return true
+	}
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if encoding.EncodeToString(sum) == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// fileName builds the storage name of the media, grouping files by sender
+func fileName(msg domain.InboundMessage) string {
+	return sanitize(msg.From) + "/" + sanitize(msg.ID) + extension(msg.Media)
+}
+
+// extension returns the file extension of the media, from its filename or mime type
+func extension(media *domain.Media) string {
+	if ext := filepath.Ext(media.Filename); ext != "" {
+		return strings.ToLower(sanitize(ext))
+	}
+
+	mediaType, _, err := mime.ParseMediaType(media.MimeType)
+	if err != nil {
+		return ".bin"
+	}
+	if ext, ok := extensions[mediaType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// sanitize replaces every character that is not safe in a file name
+func sanitize(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+}
@@ -0,0 +1,236 @@
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct {
+	warnMessages []string
+}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{}) {
+	m.warnMessages = append(m.warnMessages, format)
+}
+func (m *mockLogger) Error(format string, args ...interface{}) {}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+// newTestService returns a service downloading data and keeping the stored files in memory
+func newTestService(t *testing.T, data []byte, logger *mockLogger) (*Service, map[string][]byte) {
+	ctrl := gomock.NewController(t)
+	downloader := mocks.NewMockMediaDownloader(ctrl)
+	store := mocks.NewMockMediaStore(ctrl)
+	files := map[string][]byte{}
+
+	downloader.EXPECT().DownloadMediaContext(gomock.Any(), gomock.Any()).Return(data, nil).AnyTimes()
+	store.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, name string, content io.Reader) (string, error) {
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return "", err
+		}
+		files[name] = data
+		return name, nil
+	}).AnyTimes()
+
+	return NewService(downloader, store, logger), files
+}
+
+func newMediaMessage(media *domain.Media) domain.InboundMessage {
+	return domain.InboundMessage{
+		ID:    "wamid.1",
+		From:  "554499887766",
+		Type:  domain.MessageTypeDocument,
+		Media: media,
+	}
+}
+
+func TestIngest(t *testing.T) {
+	content := []byte("%PDF-1.4 crm")
+	sum := sha256.Sum256(content)
+
+	t.Run("should store media with a valid base64 hash", func(t *testing.T) {
+		// arrange
+		service, files := newTestService(t, content, &mockLogger{})
+
+		// act
+		stored, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{
+			ID:       "media.1",
+			MimeType: "application/pdf",
+			SHA256:   base64.StdEncoding.EncodeToString(sum[:]),
+			Caption:  "Meu CRM",
+		}))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "554499887766/wamid.1.pdf", stored.Location)
+		assert.Equal(t, content, files["554499887766/wamid.1.pdf"])
+		assert.Equal(t, hex.EncodeToString(sum[:]), stored.SHA256)
+		assert.Equal(t, int64(len(content)), stored.Size)
+		assert.Equal(t, "Meu CRM", stored.Caption)
+	})
+
+	t.Run("should accept a hex hash and keep the original extension", func(t *testing.T) {
+		// arrange
+		service, _ := newTestService(t, content, &mockLogger{})
+
+		// act
+		stored, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{
+			ID:       "media.1",
+			MimeType: "application/octet-stream",
+			SHA256:   hex.EncodeToString(sum[:]),
+			Filename: "Comprovante Endereço.PDF",
+		}))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "554499887766/wamid.1.pdf", stored.Location)
+	})
+
+	t.Run("should use the mime type when there is no filename", func(t *testing.T) {
+		// arrange
+		service, _ := newTestService(t, content, &mockLogger{})
+
+		// act
+		stored, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{
+			ID:       "media.1",
+			MimeType: "audio/ogg; codecs=opus",
+			SHA256:   hex.EncodeToString(sum[:]),
+		}))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "554499887766/wamid.1.ogg", stored.Location)
+	})
+
+	t.Run("should reject media with a different hash", func(t *testing.T) {
+		// arrange
+		service, files := newTestService(t, content, &mockLogger{})
+
+		// act
+		stored, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{
+			ID:     "media.1",
+			SHA256: "bm90IHRoZSBoYXNo",
+		}))
+
+		// assert
+		assert.Nil(t, stored)
+		assert.ErrorIs(t, err, ErrHashMismatch)
+		assert.Empty(t, files)
+	})
+
+	t.Run("should warn and store media without hash", func(t *testing.T) {
+		// arrange
+		logger := &mockLogger{}
+		service, files := newTestService(t, content, logger)
+
+		// act
+		_, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{ID: "media.1", MimeType: "image/jpeg"}))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, files, "554499887766/wamid.1.jpg")
+		assert.Len(t, logger.warnMessages, 1)
+	})
+
+	t.Run("should return error when download fails", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		downloader := mocks.NewMockMediaDownloader(ctrl)
+		service := NewService(downloader, mocks.NewMockMediaStore(ctrl), &mockLogger{})
+		downloader.EXPECT().DownloadMediaContext(gomock.Any(), "media.1").Return(nil, errors.New("API error"))
+
+		// act
+		_, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{ID: "media.1"}))
+
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error downloading media")
+	})
+
+	t.Run("should return error for messages without media", func(t *testing.T) {
+		// arrange
+		service, _ := newTestService(t, nil, &mockLogger{})
+
+		// act
+		_, err := service.Ingest(context.Background(), newMediaMessage(nil))
+
+		// assert
+		assert.Error(t, err)
+	})
+}
+
+func TestIngestWithMocks(t *testing.T) {
+	content := []byte("%PDF-1.4 crm")
+	sum := sha256.Sum256(content)
+
+	t.Run("should download and store the media with the context of the message", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		downloader := mocks.NewMockMediaDownloader(ctrl)
+		store := mocks.NewMockMediaStore(ctrl)
+		service := NewService(downloader, store, &mockLogger{})
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "request-1")
+
+		downloader.EXPECT().DownloadMediaContext(ctx, "media-1").Return(content, nil)
+		store.EXPECT().Save(ctx, "554499887766/wamid.1.pdf", gomock.Any()).Return("/data/554499887766/wamid.1.pdf", nil)
+
+		// act
+		stored, err := service.Ingest(ctx, newMediaMessage(&domain.Media{
+			ID:       "media-1",
+			MimeType: "application/pdf",
+			SHA256:   hex.EncodeToString(sum[:]),
+		}))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "/data/554499887766/wamid.1.pdf", stored.Location)
+	})
+
+	t.Run("should not store media that could not be downloaded", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		downloader := mocks.NewMockMediaDownloader(ctrl)
+		store := mocks.NewMockMediaStore(ctrl)
+		service := NewService(downloader, store, &mockLogger{})
+
+		downloader.EXPECT().DownloadMediaContext(gomock.Any(), "media-1").Return(nil, errors.New("media expired"))
+		store.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		// act
+		_, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{ID: "media-1"}))
+
+		// assert
+		assert.ErrorContains(t, err, "media expired")
+	})
+
+	t.Run("should return error when the media cannot be stored", func(t *testing.T) {
+		// arrange
+		ctrl := gomock.NewController(t)
+		downloader := mocks.NewMockMediaDownloader(ctrl)
+		store := mocks.NewMockMediaStore(ctrl)
+		service := NewService(downloader, store, &mockLogger{})
+
+		downloader.EXPECT().DownloadMediaContext(gomock.Any(), "media-1").Return(content, nil)
+		store.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("disk full"))
+
+		// act
+		_, err := service.Ingest(context.Background(), newMediaMessage(&domain.Media{ID: "media-1"}))
+
+		// assert
+		assert.ErrorContains(t, err, "disk full")
+	})
+}
@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/2rprbm/conta-med-backend/pkg/logger"
+)
+
+// Default pool settings used when the configuration does not set them
+const (
+	DefaultWorkers   = 4
+	DefaultQueueSize = 100
+)
+
+// ErrPoolClosed is returned when a message is submitted after the pool was shut down
+var ErrPoolClosed = errors.New("worker pool closed")
+
+// job is an inbound message waiting to be processed
+type job struct {
+	ctx context.Context
+	msg domain.InboundMessage
+}
+
+// Pool processes inbound messages in the background with a bounded number of workers.
+// ProcessMessage only enqueues the message, so webhooks can be acknowledged immediately.
+//
+// Messages are sharded by sender: each worker has its own queue and all the messages of
+// a sender go to the same worker, so they are processed one at a time and in order,
+// while messages of different senders are processed in parallel.
+type Pool struct {
+	processor ports.MessageProcessor
+	logger    logger.Logger
+	queues    []chan job
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+
+	// ctx is cancelled when the shutdown period ends, aborting the jobs still running
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ ports.MessageProcessor = (*Pool)(nil)
+
+// NewPool creates a pool processing messages with the given number of workers and starts it.
+// At most queueSize messages, split evenly between the workers, wait to be processed;
+// further messages are rejected. Non-positive values use the defaults.
+func NewPool(processor ports.MessageProcessor, workers, queueSize int, log logger.Logger) *Pool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		processor: processor,
+		logger:    log,
+		queues:    make([]chan job, workers),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	shardSize := (queueSize + workers - 1) / workers
+	p.wg.Add(workers)
+	for i := range p.queues {
+		p.queues[i] = make(chan job, shardSize)
+		go p.work(p.queues[i])
+	}
+	return p
+}
+
+// ProcessMessage enqueues the message, returning domain.ErrOverloaded when the queue is full.
+// The message is processed with the values of ctx, but not bound to its cancellation,
+// since the context of a webhook request ends as soon as it is acknowledged.
+func (p *Pool) ProcessMessage(ctx context.Context, msg domain.InboundMessage) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	queue := p.queues[p.shard(msg.From)]
+	select {
+	case queue <- job{ctx: context.WithoutCancel(ctx), msg: msg}:
+		return nil
+	default:
+		return fmt.Errorf("queue of %d messages is full: %w", cap(queue), domain.ErrOverloaded)
+	}
+}
+
+// shard returns the index of the worker processing the messages of the sender
+func (p *Pool) shard(from string) int {
+	h := fnv.New32a()
+	h.Write([]byte(from))
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+// Shutdown stops accepting messages and waits for the queued ones to be processed.
+// When ctx is done first, the running jobs are cancelled and the context error is returned.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		for _, queue := range p.queues {
+			close(queue)
+		}
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return fmt.Errorf("%d queued messages not processed: %w", p.pending(), ctx.Err())
+	}
+}
+
+// pending returns the number of queued messages
+func (p *Pool) pending() int {
+	n := 0
+	for _, queue := range p.queues {
+		n += len(queue)
+	}
+	return n
+}
+
+// work processes the jobs of the queue until it is closed and drained. The jobs waiting
+// in the queue are taken together and processed by timestamp, since WhatsApp does not
+// guarantee that the webhooks of a sender arrive in the order the messages were sent.
+func (p *Pool) work(queue chan job) {
+	defer p.wg.Done()
+	for j := range queue {
+		batch := append([]job{j}, drain(queue)...)
+		sort.SliceStable(batch, func(a, b int) bool {
+			return batch[a].msg.Timestamp.Before(batch[b].msg.Timestamp)
+		})
+		for _, j := range batch {
+			p.run(j)
+		}
+	}
+}
+
+// drain returns the jobs already waiting in the queue without blocking
+func drain(queue chan job) []job {
+	var jobs []job
+	for {
+		select {
+		case j, ok := <-queue:
+			if !ok {
+				return jobs
+			}
+			jobs = append(jobs, j)
+		default:
+			return jobs
+		}
+	}
+}
+
+// run processes a single job, logging its errors
+func (p *Pool) run(j job) {
+	ctx, cancel := context.WithCancel(j.ctx)
+	defer cancel()
+	stop := context.AfterFunc(p.ctx, cancel)
+	defer stop()
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("Panic processing message %s from %s: %v", j.msg.ID, j.msg.From, r)
+		}
+	}()
+
+	if err := p.processor.ProcessMessage(ctx, j.msg); err != nil {
+		p.logger.Error("Error processing message %s from %s: %v", j.msg.ID, j.msg.From, err)
+	}
+}
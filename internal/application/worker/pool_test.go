@@ -0,0 +1,335 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLogger implements the logger.Logger interface for testing
+type mockLogger struct {
+	mu            sync.Mutex
+	errorMessages []string
+}
+
+func (m *mockLogger) Debug(format string, args ...interface{}) {}
+func (m *mockLogger) Info(format string, args ...interface{})  {}
+func (m *mockLogger) Warn(format string, args ...interface{})  {}
+func (m *mockLogger) Error(format string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errorMessages = append(m.errorMessages, fmt.Sprintf(format, args...))
+}
+func (m *mockLogger) Fatal(format string, args ...interface{}) {}
+
+func (m *mockLogger) errors() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.errorMessages...)
+}
+
+// fakeProcessor implements ports.MessageProcessor recording the processed messages.
+// When block is set, messages wait for it to be closed or for their context to end;
+// blockFrom restricts the wait to the messages of a single sender.
+type fakeProcessor struct {
+	mu        sync.Mutex
+	messages  []domain.InboundMessage
+	contexts  []context.Context
+	block     chan struct{}
+	blockFrom string
+	err       error
+	panic     bool
+
+	running    map[string]int
+	concurrent bool
+}
+
+func (f *fakeProcessor) ProcessMessage(ctx context.Context, msg domain.InboundMessage) error {
+	f.mu.Lock()
+	if f.running == nil {
+		f.running = map[string]int{}
+	}
+	f.running[msg.From]++
+	if f.running[msg.From] > 1 {
+		f.concurrent = true
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.running[msg.From]--
+		f.mu.Unlock()
+	}()
+
+	if f.block != nil && (f.blockFrom == "" || f.blockFrom == msg.From) {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if f.panic {
+		panic("boom")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, msg)
+	f.contexts = append(f.contexts, ctx)
+	return f.err
+}
+
+func (f *fakeProcessor) processed() []domain.InboundMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]domain.InboundMessage{}, f.messages...)
+}
+
+func (f *fakeProcessor) isRunning(from string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.running[from] > 0
+}
+
+func newMessage(id string) domain.InboundMessage {
+	return newMessageFrom("554499887766", id, time.Time{})
+}
+
+func newMessageFrom(from, id string, timestamp time.Time) domain.InboundMessage {
+	return domain.InboundMessage{ID: id, From: from, Timestamp: timestamp, Type: domain.MessageTypeText, Text: "Oi"}
+}
+
+// ids returns the IDs of the messages
+func ids(messages []domain.InboundMessage) []string {
+	result := make([]string, len(messages))
+	for i, msg := range messages {
+		result[i] = msg.ID
+	}
+	return result
+}
+
+func TestPool(t *testing.T) {
+	t.Run("should process the enqueued messages in the background", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{}
+		pool := NewPool(processor, 2, 10, &mockLogger{})
+
+		// act
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, pool.ProcessMessage(context.Background(), newMessage(fmt.Sprintf("wamid.%d", i))))
+		}
+		err := pool.Shutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, processor.processed(), 5)
+	})
+
+	t.Run("should keep the context values but not its cancellation", func(t *testing.T) {
+		// arrange
+		type key struct{}
+		processor := &fakeProcessor{}
+		pool := NewPool(processor, 1, 1, &mockLogger{})
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "request-1"))
+
+		// act
+		err := pool.ProcessMessage(ctx, newMessage("wamid.1"))
+		cancel()
+		pool.Shutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, processor.contexts, 1)
+		assert.Equal(t, "request-1", processor.contexts[0].Value(key{}))
+		assert.Len(t, processor.processed(), 1)
+	})
+
+	t.Run("should reject messages when the queue is full", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{block: make(chan struct{})}
+		pool := NewPool(processor, 1, 1, &mockLogger{})
+
+		// act
+		var errs []error
+		for i := 0; i < 5; i++ {
+			errs = append(errs, pool.ProcessMessage(context.Background(), newMessage(fmt.Sprintf("wamid.%d", i))))
+		}
+		close(processor.block)
+		pool.Shutdown(context.Background())
+
+		// assert
+		rejected := 0
+		for _, err := range errs {
+			if errors.Is(err, domain.ErrOverloaded) {
+				rejected++
+			}
+		}
+		// One message is running, one is queued and the others are rejected
+		assert.GreaterOrEqual(t, rejected, 3)
+		assert.Len(t, processor.processed(), 5-rejected)
+	})
+
+	t.Run("should use the default queue size when it is not positive", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{block: make(chan struct{})}
+		pool := NewPool(processor, 1, 0, &mockLogger{})
+
+		// act
+		var errs []error
+		for i := 0; i < 5; i++ {
+			errs = append(errs, pool.ProcessMessage(context.Background(), newMessage(fmt.Sprintf("wamid.%d", i))))
+		}
+		close(processor.block)
+		pool.Shutdown(context.Background())
+
+		// assert
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, DefaultQueueSize, cap(pool.queues[0]))
+		assert.Len(t, processor.processed(), 5)
+	})
+
+	t.Run("should reject messages after shutdown", func(t *testing.T) {
+		// arrange
+		pool := NewPool(&fakeProcessor{}, 1, 1, &mockLogger{})
+		pool.Shutdown(context.Background())
+
+		// act
+		err := pool.ProcessMessage(context.Background(), newMessage("wamid.1"))
+
+		// assert
+		assert.ErrorIs(t, err, ErrPoolClosed)
+	})
+
+	t.Run("should cancel the running messages when the shutdown period ends", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{block: make(chan struct{})}
+		logger := &mockLogger{}
+		pool := NewPool(processor, 1, 1, logger)
+		pool.ProcessMessage(context.Background(), newMessage("wamid.1"))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := pool.Shutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Eventually(t, func() bool { return len(logger.errors()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Contains(t, logger.errors()[0], "context canceled")
+	})
+
+	t.Run("should log processing errors and recover from panics", func(t *testing.T) {
+		// arrange
+		logger := &mockLogger{}
+		failing := NewPool(&fakeProcessor{err: errors.New("send failed")}, 1, 1, logger)
+		panicking := NewPool(&fakeProcessor{panic: true}, 1, 1, logger)
+
+		// act
+		failing.ProcessMessage(context.Background(), newMessage("wamid.1"))
+		panicking.ProcessMessage(context.Background(), newMessage("wamid.2"))
+		failing.Shutdown(context.Background())
+		panicking.Shutdown(context.Background())
+
+		// assert
+		assert.ElementsMatch(t, []string{
+			"Error processing message wamid.1 from 554499887766: send failed",
+			"Panic processing message wamid.2 from 554499887766: boom",
+		}, logger.errors())
+	})
+}
+
+func TestPoolOrdering(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should process the messages of a sender by timestamp", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{block: make(chan struct{})}
+		pool := NewPool(processor, 1, 10, &mockLogger{})
+		pool.ProcessMessage(context.Background(), newMessageFrom("554499887766", "wamid.0", base))
+		// Wait for the first message to be running, so the next ones are queued together
+		assert.Eventually(t, func() bool { return processor.isRunning("554499887766") }, time.Second, time.Millisecond)
+
+		// act
+		pool.ProcessMessage(context.Background(), newMessageFrom("554499887766", "wamid.2", base.Add(2*time.Second)))
+		pool.ProcessMessage(context.Background(), newMessageFrom("554499887766", "wamid.3", base.Add(3*time.Second)))
+		pool.ProcessMessage(context.Background(), newMessageFrom("554499887766", "wamid.1", base.Add(time.Second)))
+		close(processor.block)
+		pool.Shutdown(context.Background())
+
+		// assert
+		assert.Equal(t, []string{"wamid.0", "wamid.1", "wamid.2", "wamid.3"}, ids(processor.processed()))
+	})
+
+	t.Run("should keep the arrival order of messages with the same timestamp", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{}
+		pool := NewPool(processor, 4, 100, &mockLogger{})
+
+		// act
+		for i := 0; i < 20; i++ {
+			pool.ProcessMessage(context.Background(), newMessageFrom("554499887766", fmt.Sprintf("wamid.%02d", i), base))
+		}
+		pool.Shutdown(context.Background())
+
+		// assert
+		processed := ids(processor.processed())
+		assert.Len(t, processed, 20)
+		assert.IsIncreasing(t, processed)
+	})
+
+	t.Run("should never process two messages of a sender at the same time", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{}
+		pool := NewPool(processor, 8, 1000, &mockLogger{})
+
+		// act
+		var wg sync.WaitGroup
+		for s := 0; s < 4; s++ {
+			wg.Add(1)
+			go func(sender int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					from := fmt.Sprintf("5544998877%02d", sender)
+					pool.ProcessMessage(context.Background(), newMessageFrom(from, fmt.Sprintf("wamid.%d.%d", sender, i), base.Add(time.Duration(i)*time.Second)))
+				}
+			}(s)
+		}
+		wg.Wait()
+		pool.Shutdown(context.Background())
+
+		// assert
+		assert.Len(t, processor.processed(), 200)
+		assert.False(t, processor.concurrent)
+	})
+
+	t.Run("should process other senders while a sender is busy", func(t *testing.T) {
+		// arrange
+		processor := &fakeProcessor{block: make(chan struct{}), blockFrom: "554499887766"}
+		pool := NewPool(processor, 2, 10, &mockLogger{})
+		other := "554499887767"
+		for pool.shard(other) == pool.shard("554499887766") {
+			other += "1"
+		}
+
+		// act
+		pool.ProcessMessage(context.Background(), newMessageFrom("554499887766", "wamid.busy", base))
+		pool.ProcessMessage(context.Background(), newMessageFrom(other, "wamid.other", base))
+
+		// assert
+		assert.Eventually(t, func() bool {
+			return len(processor.processed()) == 1
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, "wamid.other", processor.processed()[0].ID)
+
+		close(processor.block)
+		pool.Shutdown(context.Background())
+		assert.Len(t, processor.processed(), 2)
+	})
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrAmountRequired is returned when the amount input has no number
+	ErrAmountRequired = errors.New("amount required")
+	// ErrAmountInvalid is returned when the amount input is not a valid amount in reais
+	ErrAmountInvalid = errors.New("invalid amount")
+)
+
+// amountMultipliers are the words users add to the amounts, like "30 mil" or "30k"
+var amountMultipliers = map[string]float64{
+	"mil": 1e3,
+	"k":   1e3,
+}
+
+// ParseAmount parses an amount in reais typed the usual ways in Brazil, like "R$ 30.000,00",
+// "30000", "30.000", "30,5 mil" or "30k". The dot separates the thousands and the comma the
+// cents; a dot followed by other than three digits is read as the decimal separator.
+func ParseAmount(input string) (float64, error) {
+	text := strings.ToLower(strings.TrimSpace(input))
+	text = strings.TrimPrefix(text, "r$")
+	text = strings.TrimSuffix(strings.TrimSpace(text), "reais")
+	text = strings.TrimSpace(text)
+
+	multiplier := 1.0
+	for word, value := range amountMultipliers {
+		if rest, ok := strings.CutSuffix(text, word); ok {
+			text, multiplier = strings.TrimSpace(rest), value
+			break
+		}
+	}
+	if !strings.ContainsFunc(text, unicode.IsDigit) {
+		return 0, ErrAmountRequired
+	}
+
+	number, ok := normalizeAmount(text)
+	if !ok {
+		return 0, ErrAmountInvalid
+	}
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil || amount < 0 {
+		return 0, ErrAmountInvalid
+	}
+	return amount * multiplier, nil
+}
+
+// normalizeAmount rewrites the digits and separators of the amount in the format of strconv.ParseFloat
+func normalizeAmount(text string) (string, bool) {
+	for _, r := range text {
+		if !unicode.IsDigit(r) && r != '.' && r != ',' {
+			return "", false
+		}
+	}
+
+	if integer, cents, ok := strings.Cut(text, ","); ok {
+		if strings.Contains(cents, ",") || strings.Contains(cents, ".") || !validThousands(integer) {
+			return "", false
+		}
+		return strings.ReplaceAll(integer, ".", "") + "." + cents, true
+	}
+
+	parts := strings.Split(text, ".")
+	if last := parts[len(parts)-1]; len(parts) == 2 && len(last) != 3 {
+		return text, true
+	}
+	if !validThousands(text) {
+		return "", false
+	}
+	return strings.ReplaceAll(text, ".", ""), true
+}
+
+// validThousands reports whether the dots of the integer part separate groups of three digits
+func validThousands(integer string) bool {
+	groups := strings.Split(integer, ".")
+	if len(groups) == 1 {
+		return true
+	}
+	if groups[0] == "" || len(groups[0]) > 3 {
+		return false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return false
+		}
+	}
+	return true
+}
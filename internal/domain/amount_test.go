@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	t.Run("should read the usual ways of writing an amount", func(t *testing.T) {
+		inputs := map[string]float64{
+			"30000":           30000,
+			"30.000":          30000,
+			"30.000,00":       30000,
+			"R$ 30.000,00":    30000,
+			"r$30000":         30000,
+			"30000 reais":     30000,
+			"1.234.567,89":    1234567.89,
+			"2500,50":         2500.5,
+			"2500.5":          2500.5,
+			"30 mil":          30000,
+			"30,5 mil":        30500,
+			"30k":             30000,
+			"  R$ 1.518,00  ": 1518,
+			"0":               0,
+		}
+
+		for input, expected := range inputs {
+			// act
+			amount, err := ParseAmount(input)
+
+			// assert
+			assert.NoError(t, err, input)
+			assert.InDelta(t, expected, amount, 0.001, input)
+		}
+	})
+
+	t.Run("should require a number", func(t *testing.T) {
+		for _, input := range []string{"", "R$", "mil", "não sei"} {
+			// act
+			_, err := ParseAmount(input)
+
+			// assert
+			assert.ErrorIs(t, err, ErrAmountRequired, input)
+		}
+	})
+
+	t.Run("should reject malformed amounts", func(t *testing.T) {
+		for _, input := range []string{"-3000", "30.00.00", "3.0000,00", "1,000,00", "entre 10 e 20 mil", "30000 dólares", "1.2.3"} {
+			// act
+			_, err := ParseAmount(input)
+
+			// assert
+			assert.ErrorIs(t, err, ErrAmountInvalid, input)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBusinessHours is returned when the business hours can not be parsed
+var ErrInvalidBusinessHours = errors.New("invalid business hours")
+
+// weekdays maps the abbreviations accepted in the working days to the weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// BusinessHours tells when the team answering the users works. The team does not work on the
+// holidays of the calendar observed in its municipality and state.
+type BusinessHours struct {
+	// Open and Close are the opening and closing times of the working days, since midnight
+	Open  time.Duration
+	Close time.Duration
+	Days  map[time.Weekday]bool
+	// Location is the zone of the team
+	Location *time.Location
+	Calendar *Calendar
+	// State and MunicipalityCode select the state and municipal holidays of the calendar
+	State            string
+	MunicipalityCode string
+}
+
+// ParseBusinessHours creates the business hours from the opening and closing time, like
+// "08:00-18:00", and the working days, like "mon-fri" or "mon,wed,fri", in the given zone
+func ParseBusinessHours(hours, days string, loc *time.Location) (*BusinessHours, error) {
+	b := &BusinessHours{Days: make(map[time.Weekday]bool), Location: loc, Calendar: NewCalendar()}
+
+	openText, closeText, ok := strings.Cut(hours, "-")
+	if !ok {
+		return nil, fmt.Errorf("%w: hours %q must be like 08:00-18:00", ErrInvalidBusinessHours, hours)
+	}
+	var err error
+	if b.Open, err = parseTimeOfDay(openText); err != nil {
+		return nil, err
+	}
+	if b.Close, err = parseTimeOfDay(closeText); err != nil {
+		return nil, err
+	}
+	if b.Open >= b.Close {
+		return nil, fmt.Errorf("%w: hours %q close before they open", ErrInvalidBusinessHours, hours)
+	}
+
+	for _, part := range strings.Split(strings.ToLower(days), ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			last = first
+		}
+		from, ok := weekdays[strings.TrimSpace(first)]
+		to, ok2 := weekdays[strings.TrimSpace(last)]
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("%w: days %q must be like mon-fri", ErrInvalidBusinessHours, days)
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			b.Days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+	return b, nil
+}
+
+// parseTimeOfDay parses a time like 08:00 as the duration since midnight
+func parseTimeOfDay(text string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf("%w: time %q must be like 08:00", ErrInvalidBusinessHours, text)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// HolidayOn returns the holiday observed by the team on the day of t, if any
+func (b *BusinessHours) HolidayOn(t time.Time) (Holiday, bool) {
+	return b.Calendar.HolidayOn(t.In(b.Location), b.State, b.MunicipalityCode)
+}
+
+// IsOpen reports whether the team is working at t
+func (b *BusinessHours) IsOpen(t time.Time) bool {
+	local := t.In(b.Location)
+	if !b.isWorkday(local) {
+		return false
+	}
+	since := local.Sub(b.midnight(local))
+	return since >= b.Open && since < b.Close
+}
+
+// NextOpening returns t when the team is working at t, else the time the team starts working again.
+// It returns the zero time when the team does not work in the next year.
+func (b *BusinessHours) NextOpening(t time.Time) time.Time {
+	if b.IsOpen(t) {
+		return t
+	}
+
+	today := b.midnight(t.In(b.Location))
+	for i := 0; i <= 366; i++ {
+		day := today.AddDate(0, 0, i)
+		if !b.isWorkday(day) {
+			continue
+		}
+		if opening := day.Add(b.Open); opening.After(t) {
+			return opening
+		}
+	}
+	return time.Time{}
+}
+
+// isWorkday reports whether the team works on the day of the local time
+func (b *BusinessHours) isWorkday(local time.Time) bool {
+	if !b.Days[local.Weekday()] {
+		return false
+	}
+	_, holiday := b.Calendar.HolidayOn(local, b.State, b.MunicipalityCode)
+	return !holiday
+}
+
+// midnight returns the start of the day of the local time
+func (b *BusinessHours) midnight(local time.Time) time.Time {
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, b.Location)
+}
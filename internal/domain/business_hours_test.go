@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBusinessHours(t *testing.T) {
+	loc := loadTimezone(DefaultTimezone)
+
+	t.Run("should parse the hours and a range of days", func(t *testing.T) {
+		// act
+		hours, err := ParseBusinessHours("08:30-18:00", "mon-fri", loc)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 8*time.Hour+30*time.Minute, hours.Open)
+		assert.Equal(t, 18*time.Hour, hours.Close)
+		assert.Equal(t, map[time.Weekday]bool{
+			time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true,
+		}, hours.Days)
+		assert.Equal(t, loc, hours.Location)
+	})
+
+	t.Run("should parse a list of days and ranges through the weekend", func(t *testing.T) {
+		// act
+		list, err := ParseBusinessHours("08:00-12:00", "Mon, wed,FRI", loc)
+		wrapped, err2 := ParseBusinessHours("08:00-12:00", "fri-mon", loc)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, map[time.Weekday]bool{time.Monday: true, time.Wednesday: true, time.Friday: true}, list.Days)
+		assert.NoError(t, err2)
+		assert.Equal(t, map[time.Weekday]bool{
+			time.Friday: true, time.Saturday: true, time.Sunday: true, time.Monday: true,
+		}, wrapped.Days)
+	})
+
+	for _, tt := range []struct {
+		hours string
+		days  string
+	}{
+		{hours: "08:00", days: "mon-fri"},
+		{hours: "8h-18h", days: "mon-fri"},
+		{hours: "18:00-08:00", days: "mon-fri"},
+		{hours: "08:00-25:00", days: "mon-fri"},
+		{hours: "08:00-18:00", days: "seg-sex"},
+		{hours: "08:00-18:00", days: ""},
+	} {
+		t.Run("should reject "+tt.hours+" "+tt.days, func(t *testing.T) {
+			// act
+			hours, err := ParseBusinessHours(tt.hours, tt.days, loc)
+
+			// assert
+			assert.ErrorIs(t, err, ErrInvalidBusinessHours)
+			assert.Nil(t, hours)
+		})
+	}
+}
+
+func TestBusinessHours(t *testing.T) {
+	loc := loadTimezone(DefaultTimezone)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, loc)
+	}
+	newHours := func(t *testing.T) *BusinessHours {
+		t.Helper()
+		hours, err := ParseBusinessHours("08:00-18:00", "mon-fri", loc)
+		assert.NoError(t, err)
+		hours.Calendar = NewCalendar(Holiday{Name: "Nossa Senhora da Luz dos Pinhais", Month: time.September, Day: 8, State: "PR", MunicipalityCode: "4106902"})
+		hours.State = "PR"
+		hours.MunicipalityCode = "4106902"
+		return hours
+	}
+
+	t.Run("should be open only within the hours of the working days", func(t *testing.T) {
+		// arrange
+		hours := newHours(t)
+
+		// act & assert
+		assert.True(t, hours.IsOpen(at(time.March, 10, 8, 0)))
+		assert.True(t, hours.IsOpen(at(time.March, 10, 17, 59)))
+		assert.False(t, hours.IsOpen(at(time.March, 10, 7, 59)))
+		assert.False(t, hours.IsOpen(at(time.March, 10, 18, 0)))
+		assert.False(t, hours.IsOpen(at(time.March, 15, 10, 0)))
+	})
+
+	t.Run("should read the time in the zone of the team", func(t *testing.T) {
+		// arrange
+		hours := newHours(t)
+
+		// act & assert
+		assert.True(t, hours.IsOpen(time.Date(2025, time.March, 10, 20, 0, 0, 0, time.UTC)))
+		assert.False(t, hours.IsOpen(time.Date(2025, time.March, 10, 22, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("should be closed on holidays", func(t *testing.T) {
+		// arrange
+		hours := newHours(t)
+
+		// act
+		holiday, ok := hours.HolidayOn(at(time.April, 18, 10, 0))
+
+		// assert
+		assert.False(t, hours.IsOpen(at(time.April, 18, 10, 0)))
+		assert.True(t, ok)
+		assert.Equal(t, "Sexta-feira Santa", holiday.Name)
+	})
+
+	t.Run("should tell the next opening", func(t *testing.T) {
+		// arrange
+		hours := newHours(t)
+
+		// act & assert
+		assert.Equal(t, at(time.March, 10, 10, 0), hours.NextOpening(at(time.March, 10, 10, 0)))
+		assert.Equal(t, at(time.March, 10, 8, 0), hours.NextOpening(at(time.March, 10, 6, 0)))
+		assert.Equal(t, at(time.March, 11, 8, 0), hours.NextOpening(at(time.March, 10, 18, 0)))
+		assert.Equal(t, at(time.March, 17, 8, 0), hours.NextOpening(at(time.March, 14, 19, 0)))
+	})
+
+	t.Run("should skip the holidays to the next opening", func(t *testing.T) {
+		// arrange
+		hours := newHours(t)
+
+		// act
+		afterEaster := hours.NextOpening(at(time.April, 17, 19, 0))
+		afterMunicipal := hours.NextOpening(at(time.September, 5, 19, 0))
+
+		// assert
+		assert.Equal(t, at(time.April, 22, 8, 0), afterEaster)
+		assert.Equal(t, at(time.September, 9, 8, 0), afterMunicipal)
+	})
+
+	t.Run("should not open when there are no working days", func(t *testing.T) {
+		// arrange
+		hours := newHours(t)
+		hours.Days = map[time.Weekday]bool{}
+
+		// act
+		opening := hours.NextOpening(at(time.March, 10, 10, 0))
+
+		// assert
+		assert.True(t, opening.IsZero())
+	})
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Contact is a WhatsApp user who has written to the business
+type Contact struct {
+	Phone string
+	// Name is the WhatsApp profile name of the user, which the user can change at any time
+	Name        string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// NewContact creates a contact first seen at now
+func NewContact(phone string, now time.Time) *Contact {
+	return &Contact{
+		Phone:       phone,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+}
+
+// Seen records a new message of the contact, keeping the latest known profile name
+func (c *Contact) Seen(name string, now time.Time) {
+	if name != "" {
+		c.Name = name
+	}
+	if now.After(c.LastSeenAt) {
+		c.LastSeenAt = now
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContact(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should keep the latest profile name and message time", func(t *testing.T) {
+		// arrange
+		contact := NewContact("554499887766", now)
+
+		// act
+		contact.Seen("Ana", now.Add(time.Minute))
+		contact.Seen("Dra. Ana", now.Add(2*time.Minute))
+
+		// assert
+		assert.Equal(t, "Dra. Ana", contact.Name)
+		assert.Equal(t, now, contact.FirstSeenAt)
+		assert.Equal(t, now.Add(2*time.Minute), contact.LastSeenAt)
+	})
+
+	t.Run("should keep the known name when the webhook does not inform it", func(t *testing.T) {
+		// arrange
+		contact := NewContact("554499887766", now)
+		contact.Seen("Ana", now)
+
+		// act
+		contact.Seen("", now.Add(time.Minute))
+
+		// assert
+		assert.Equal(t, "Ana", contact.Name)
+	})
+
+	t.Run("should not move the last seen time back for late messages", func(t *testing.T) {
+		// arrange
+		contact := NewContact("554499887766", now)
+
+		// act
+		contact.Seen("Ana", now.Add(-time.Minute))
+
+		// assert
+		assert.Equal(t, now, contact.LastSeenAt)
+	})
+}
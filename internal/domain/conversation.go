@@ -0,0 +1,142 @@
+package domain
+
+import "time"
+
+// ConversationStep represents the position of a conversation in the chatbot flow
+type ConversationStep string
+
+const (
+	// StepStart is the step of a conversation that has not been greeted yet
+	StepStart ConversationStep = "start"
+	// StepMainMenu waits for the user to pick one of the main menu options
+	StepMainMenu ConversationStep = "main_menu"
+	// StepCRMQuestion waits for the user to tell whether they have a CRM
+	StepCRMQuestion ConversationStep = "crm_question"
+	// StepCRMNumber waits for the CRM number and state of a user who has a CRM
+	StepCRMNumber ConversationStep = "crm_number"
+	// StepSpecialty waits for the medical specialty of the user
+	StepSpecialty ConversationStep = "specialty"
+	// StepState waits for the state (Estado) where the user works
+	StepState ConversationStep = "state"
+	// StepMunicipality waits for the municipality (Município) where the user works
+	StepMunicipality ConversationStep = "municipality"
+	// StepQuestion waits for the questions of a user, answered from the knowledge base
+	StepQuestion ConversationStep = "question"
+	// StepSimulationRevenue waits for the monthly revenue of the tax simulation
+	StepSimulationRevenue ConversationStep = "simulation_revenue"
+	// StepSimulationProLabore waits for the monthly pró-labore of the tax simulation
+	StepSimulationProLabore ConversationStep = "simulation_pro_labore"
+	// StepCompleted is the step of a conversation that reached the end of the flow
+	StepCompleted ConversationStep = "completed"
+	// StepHandoff is the step of a conversation handed off to an agent, where the bot stays silent
+	StepHandoff ConversationStep = "handoff"
+)
+
+// MenuOption represents an option of the main menu
+type MenuOption string
+
+const (
+	// OptionHasCompany - "Já tenho uma empresa médica constituída"
+	OptionHasCompany MenuOption = "has_company"
+	// OptionOpenCompany - "Quero abrir uma empresa"
+	OptionOpenCompany MenuOption = "open_company"
+	// OptionQuestions - "Gostaria de tirar dúvidas"
+	OptionQuestions MenuOption = "questions"
+	// OptionOther - "Outros"
+	OptionOther MenuOption = "other"
+)
+
+// Conversation holds the chatbot state of a single WhatsApp contact
+type Conversation struct {
+	Phone      string
+	Step       ConversationStep
+	MenuOption MenuOption
+	HasCRM     bool
+	// CRM is the registration informed by the user, and CRMVerified whether the council confirmed it
+	CRM         CRM
+	CRMVerified bool
+	// Specialty is the medical specialty informed by the user, as typed
+	Specialty string
+	// State is the code (UF) of the state, and MunicipalityCode the IBGE code of the municipality
+	// when it was found in the catalog
+	State            string
+	Municipality     string
+	MunicipalityCode string
+	// Timezone is the zone of the contact inferred from the state and municipality they informed.
+	// It is kept when the conversation restarts.
+	Timezone string
+	// Revenue is the monthly revenue informed for the tax simulation, in reais
+	Revenue float64
+	// Attempts counts the answers to the current step that could not be understood
+	Attempts int
+	// HandoffReason tells why the conversation was handed off to an agent
+	HandoffReason HandoffReason
+	StartedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewConversation creates a new conversation for the given phone number
+func NewConversation(phone string, now time.Time) *Conversation {
+	return &Conversation{
+		Phone:     phone,
+		Step:      StepStart,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Restart discards the answers collected so far and moves the conversation back to the start
+func (c *Conversation) Restart(now time.Time) {
+	c.Step = StepStart
+	c.MenuOption = ""
+	c.HasCRM = false
+	c.CRM = CRM{}
+	c.CRMVerified = false
+	c.Specialty = ""
+	c.State = ""
+	c.Municipality = ""
+	c.MunicipalityCode = ""
+	c.Revenue = 0
+	c.Attempts = 0
+	c.HandoffReason = ""
+	c.StartedAt = now
+	c.UpdatedAt = now
+}
+
+// MoveTo moves the conversation to the given step
+func (c *Conversation) MoveTo(step ConversationStep, now time.Time) {
+	c.Step = step
+	c.Attempts = 0
+	c.UpdatedAt = now
+}
+
+// HandOff pauses the bot so that an agent answers the user
+func (c *Conversation) HandOff(reason HandoffReason, now time.Time) {
+	c.HandoffReason = reason
+	c.MoveTo(StepHandoff, now)
+}
+
+// IsHandedOff reports whether an agent is answering the user instead of the bot
+func (c *Conversation) IsHandedOff() bool {
+	return c.Step == StepHandoff
+}
+
+// Locate infers the zone of the contact from the state and municipality they informed
+func (c *Conversation) Locate() {
+	c.Timezone = TimezoneName(c.Phone, c.State, c.MunicipalityCode)
+}
+
+// Location returns the zone of the contact, inferred from the area code of the phone number
+// until the contact informs their state
+func (c *Conversation) Location() *time.Location {
+	if c.Timezone != "" {
+		return loadTimezone(c.Timezone)
+	}
+	return Timezone(c.Phone, "", "")
+}
+
+// IsExpired reports whether the conversation has been idle for longer than timeout.
+// Conversations handed off to an agent do not expire, they are given back to the bot by the agent.
+func (c *Conversation) IsExpired(now time.Time, timeout time.Duration) bool {
+	return timeout > 0 && !c.IsHandedOff() && now.Sub(c.UpdatedAt) > timeout
+}
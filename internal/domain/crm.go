@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// maxCRMDigits is the maximum number of significant digits of a CRM number
+const maxCRMDigits = 7
+
+var (
+	// ErrCRMNumberRequired is returned when the CRM input has no number
+	ErrCRMNumberRequired = errors.New("CRM number required")
+	// ErrCRMNumberInvalid is returned when the CRM number is not a valid number
+	ErrCRMNumberInvalid = errors.New("invalid CRM number")
+	// ErrCRMStateRequired is returned when the CRM input has no state
+	ErrCRMStateRequired = errors.New("CRM state required")
+	// ErrCRMStateInvalid is returned when the CRM state is not a Brazilian UF
+	ErrCRMStateInvalid = errors.New("invalid CRM state")
+)
+
+// crmFillers are the words accepted around the number and the state, as in "CRM/SP nº 123456"
+var crmFillers = map[string]bool{
+	"CRM":    true,
+	"N":      true,
+	"NO":     true,
+	"Nº":     true,
+	"N°":     true,
+	"NUM":    true,
+	"NUMERO": true,
+	"NÚMERO": true,
+}
+
+// crmHyphenSuffixPattern matches a single letter hyphenated to the number, as in "123456-P",
+// but not a state, as in "123456-SP"
+var crmHyphenSuffixPattern = regexp.MustCompile(`(\d)\s*-\s*(\pL)(\PL|$)`)
+
+// CRM is the registration of a doctor in the regional medical council of a state
+type CRM struct {
+	// Number holds the digits of the registration without leading zeros
+	Number string
+	// State is the UF of the regional council
+	State string
+	// Suffix is the letter some registrations carry after the number, as in "CRM-SP 123456-P"
+	Suffix string
+}
+
+// String returns the registration in the usual "CRM-SP 123456" or "CRM-SP 123456-P" form
+func (c CRM) String() string {
+	if c.Suffix != "" {
+		return "CRM-" + c.State + " " + c.Number + "-" + c.Suffix
+	}
+	return "CRM-" + c.State + " " + c.Number
+}
+
+// ParseCRM reads a CRM registration typed by a doctor. The state may come before or after the
+// number and be glued to it, so "CRM-SP 123456", "CRM/SP nº 123.456", "123456-SP" and "sp 123456"
+// are all read as CRM-SP 123456. A letter closing the registration, glued or hyphenated to it as
+// in "CRM-SP 123456-P", "123456P/SP" or "123456/SP-P", is kept as its suffix.
+func ParseCRM(input string) (CRM, error) {
+	if rest, suffix, ok := cutCRMSuffix(input); ok {
+		if crm, err := parseCRM(rest); err == nil {
+			crm.Suffix = suffix
+			return crm, nil
+		}
+	}
+	return parseCRM(input)
+}
+
+// cutCRMSuffix splits a letter hyphenated to the end of the input, as in "123456/SP-P". Loose
+// letters, like the "e" of "123456 e SP", are left to the parser.
+func cutCRMSuffix(input string) (rest, suffix string, ok bool) {
+	runes := []rune(strings.TrimRight(strings.ToUpper(strings.TrimSpace(input)), ". "))
+	n := len(runes)
+	if n < 2 || !unicode.IsLetter(runes[n-1]) || unicode.IsLetter(runes[n-2]) {
+		return "", "", false
+	}
+
+	body := strings.TrimRight(string(runes[:n-1]), " ")
+	if !strings.HasSuffix(body, "-") {
+		return "", "", false
+	}
+	return strings.TrimSuffix(body, "-"), string(runes[n-1]), true
+}
+
+// parseCRM reads the number, the state and a suffix glued to the number of a registration
+func parseCRM(input string) (CRM, error) {
+	var crm CRM
+	var invalidState bool
+
+	// Glue a suffix hyphenated to the number, so that "123456-P" is read as "123456P"
+	input = crmHyphenSuffixPattern.ReplaceAllString(strings.ToUpper(input), "$1$2$3")
+
+	for _, token := range crmTokens(input) {
+		if crmFillers[token] {
+			continue
+		}
+		// Split a state glued to the number, as in "123456SP" or "CRMSP"
+		token = strings.TrimPrefix(token, "CRM")
+		digits, letters := splitDigits(token)
+
+		if digits != "" {
+			if crm.Number != "" || strings.IndexFunc(letters, unicode.IsDigit) >= 0 {
+				return CRM{}, ErrCRMNumberInvalid
+			}
+			if !isDigits(digits) {
+				return CRM{}, ErrCRMNumberInvalid
+			}
+			number := strings.TrimLeft(digits, "0")
+			if number == "" || len(number) > maxCRMDigits {
+				return CRM{}, ErrCRMNumberInvalid
+			}
+			crm.Number = number
+
+			// A single letter after the number is its suffix, as in "123456P"
+			if strings.HasPrefix(token, digits) && len([]rune(letters)) == 1 {
+				crm.Suffix = letters
+				continue
+			}
+		}
+
+		switch {
+		case letters == "" || crmFillers[letters]:
+		case IsValidUF(letters):
+			if crm.State != "" && crm.State != letters {
+				return CRM{}, ErrCRMStateInvalid
+			}
+			crm.State = letters
+		case len(letters) <= 2:
+			// A two letter word that is not a UF is most likely a mistyped state
+			invalidState = true
+		}
+	}
+
+	switch {
+	case crm.Number == "":
+		return CRM{}, ErrCRMNumberRequired
+	case crm.State == "" && (invalidState || crm.Suffix != ""):
+		// Without a state, a letter after the number is most likely a mistyped state
+		return CRM{}, ErrCRMStateInvalid
+	case crm.State == "":
+		return CRM{}, ErrCRMStateRequired
+	}
+	return crm, nil
+}
+
+// crmTokens splits the upper case input on separators, keeping the thousands separators of numbers
+func crmTokens(input string) []string {
+	input = strings.ToUpper(strings.TrimSpace(input))
+
+	// Drop the dots between digits so that "123.456" is read as a single number
+	runes := []rune(input)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '.' && i > 0 && i < len(runes)-1 && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return strings.FieldsFunc(b.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '°'
+	})
+}
+
+// splitDigits splits a token into its leading digits and the remaining text, or into the
+// leading text and the remaining digits, so that "123456SP" and "SP123456" are both split
+func splitDigits(token string) (digits, letters string) {
+	i := strings.IndexFunc(token, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i == 0 {
+		j := strings.IndexFunc(token, unicode.IsDigit)
+		if j < 0 {
+			return "", token
+		}
+		return token[j:], token[:j]
+	}
+	if i < 0 {
+		return token, ""
+	}
+	return token[:i], token[i:]
+}
+
+// isDigits reports whether the text only has ASCII digits
+func isDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CRMVerification is the result of checking a registration with the federal council (CFM)
+type CRMVerification string
+
+const (
+	// CRMVerified means the registration exists and is active
+	CRMVerified CRMVerification = "verified"
+	// CRMNotFound means the council has no active registration with that number and state
+	CRMNotFound CRMVerification = "not_found"
+	// CRMUnverified means the registration could not be checked
+	CRMUnverified CRMVerification = "unverified"
+)
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCRM(t *testing.T) {
+	t.Run("should normalize the usual ways of writing a CRM", func(t *testing.T) {
+		inputs := []string{
+			"CRM-SP 123456",
+			"crm/sp 123456",
+			"CRM SP 123456",
+			"CRMSP 123456",
+			"CRM/SP nº 123.456",
+			"CRM-SP N° 123456",
+			"123456-SP",
+			"123456/sp",
+			"123456SP",
+			"SP123456",
+			"sp 123456",
+			"CRM 123456 SP",
+			"CRM 0123456-SP",
+			"  CRM-SP: 123456.  ",
+		}
+
+		for _, input := range inputs {
+			// act
+			crm, err := ParseCRM(input)
+
+			// assert
+			assert.NoError(t, err, input)
+			assert.Equal(t, CRM{Number: "123456", State: "SP"}, crm, input)
+		}
+	})
+
+	t.Run("should format the CRM", func(t *testing.T) {
+		assert.Equal(t, "CRM-PR 4321", CRM{Number: "4321", State: "PR"}.String())
+		assert.Equal(t, "CRM-PR 4321-P", CRM{Number: "4321", State: "PR", Suffix: "P"}.String())
+	})
+
+	t.Run("should require the number", func(t *testing.T) {
+		for _, input := range []string{"", "CRM-SP", "SP"} {
+			_, err := ParseCRM(input)
+			assert.ErrorIs(t, err, ErrCRMNumberRequired, input)
+		}
+	})
+
+	t.Run("should reject invalid numbers", func(t *testing.T) {
+		for _, input := range []string{"CRM-SP 12345678", "CRM-SP 000", "CRM-SP 123 456", "CRM-SP 12A34", "CRM-SP ١٢٣"} {
+			_, err := ParseCRM(input)
+			assert.ErrorIs(t, err, ErrCRMNumberInvalid, input)
+		}
+	})
+
+	t.Run("should require the state", func(t *testing.T) {
+		for _, input := range []string{"123456", "CRM 123456", "CRM nº 123456"} {
+			_, err := ParseCRM(input)
+			assert.ErrorIs(t, err, ErrCRMStateRequired, input)
+		}
+	})
+
+	t.Run("should reject states that are not UFs", func(t *testing.T) {
+		for _, input := range []string{"CRM-XX 123456", "CRM-SP 123456 RJ", "123456-S"} {
+			_, err := ParseCRM(input)
+			assert.ErrorIs(t, err, ErrCRMStateInvalid, input)
+		}
+	})
+
+	t.Run("should keep the suffix of the number", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected CRM
+		}{
+			{input: "CRM-SP 123456-P", expected: CRM{Number: "123456", State: "SP", Suffix: "P"}},
+			{input: "123456/SP-E", expected: CRM{Number: "123456", State: "SP", Suffix: "E"}},
+			{input: "CRM-SP 123456P", expected: CRM{Number: "123456", State: "SP", Suffix: "P"}},
+			{input: "123456P/SP", expected: CRM{Number: "123456", State: "SP", Suffix: "P"}},
+			{input: "crm/pr 12.345 - e", expected: CRM{Number: "12345", State: "PR", Suffix: "E"}},
+		}
+
+		for _, tt := range tests {
+			// act
+			crm, err := ParseCRM(tt.input)
+
+			// assert
+			assert.NoError(t, err, tt.input)
+			assert.Equal(t, tt.expected, crm, tt.input)
+		}
+	})
+
+	t.Run("should not read loose words as a suffix", func(t *testing.T) {
+		for _, input := range []string{"123456 e SP", "CRM 123456 é SP", "SP 123456 e"} {
+			// act
+			crm, err := ParseCRM(input)
+
+			// assert
+			assert.NoError(t, err, input)
+			assert.Equal(t, CRM{Number: "123456", State: "SP"}, crm, input)
+		}
+	})
+
+	t.Run("should accept the same state twice", func(t *testing.T) {
+		// act
+		crm, err := ParseCRM("CRM-PR 123456/PR")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "PR", crm.State)
+	})
+}
+
+func TestIsValidUF(t *testing.T) {
+	t.Run("should accept the 27 UFs", func(t *testing.T) {
+		assert.Len(t, States, 27)
+		assert.True(t, IsValidUF("DF"))
+		assert.True(t, IsValidUF("TO"))
+	})
+
+	t.Run("should reject unknown and lower case codes", func(t *testing.T) {
+		assert.False(t, IsValidUF("XX"))
+		assert.False(t, IsValidUF("sp"))
+		assert.False(t, IsValidUF(""))
+	})
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrNotFound is returned by repositories when the requested entity does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by repositories when the entity was changed since it was loaded
+	ErrConflict = errors.New("conflicting change")
+
+	// ErrTemporaryFailure matches delivery errors that may succeed if retried later
+	ErrTemporaryFailure = errors.New("temporary delivery failure")
+	// ErrTemplateRequired matches delivery errors of recipients outside the customer service window
+	ErrTemplateRequired = errors.New("template message required")
+	// ErrRecipientUnavailable matches delivery errors of recipients that can not receive messages
+	ErrRecipientUnavailable = errors.New("recipient unavailable")
+
+	// ErrOverloaded is returned when an inbound message can not be accepted for processing right now
+	ErrOverloaded = errors.New("processing capacity exceeded")
+)
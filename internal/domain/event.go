@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Event is something that happened in the domain which other parts of the system may react to
+type Event interface {
+	// EventName identifies the kind of event
+	EventName() string
+	// OccurredAt is when the event happened
+	OccurredAt() time.Time
+}
+
+// LeadQualifiedEvent is the name of the LeadQualified event
+const LeadQualifiedEvent = "lead.qualified"
+
+// LeadQualified is raised when a lead answers the questions of the flow and is ready for the sales team
+type LeadQualified struct {
+	Lead Lead
+	At   time.Time
+}
+
+// EventName returns LeadQualifiedEvent
+func (e LeadQualified) EventName() string { return LeadQualifiedEvent }
+
+// OccurredAt returns when the lead was qualified
+func (e LeadQualified) OccurredAt() time.Time { return e.At }
+
+// HandoffOpenedEvent is the name of the HandoffOpened event
+const HandoffOpenedEvent = "handoff.opened"
+
+// HandoffOpened is raised when a conversation is handed off and waits for an agent
+type HandoffOpened struct {
+	Handoff Handoff
+	At      time.Time
+}
+
+// EventName returns HandoffOpenedEvent
+func (e HandoffOpened) EventName() string { return HandoffOpenedEvent }
+
+// OccurredAt returns when the handoff was opened
+func (e HandoffOpened) OccurredAt() time.Time { return e.At }
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFAQEntry is returned when an entry of the knowledge base misses a field or breaks a limit
+var ErrInvalidFAQEntry = errors.New("invalid FAQ entry")
+
+const (
+	// MaxFAQQuestionLength is the maximum length of the question of an entry
+	MaxFAQQuestionLength = 200
+	// MaxFAQAnswerLength is the maximum length of the answer of an entry, which must fit in a
+	// WhatsApp text message along with its question
+	MaxFAQAnswerLength = 3000
+)
+
+// faqIDPattern matches the IDs of the entries: lowercase words separated by hyphens, like "fator-r"
+var faqIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// FAQEntry is a question of the knowledge base with the answer sent to the users who ask it
+type FAQEntry struct {
+	// ID identifies the entry, like "fator-r"
+	ID string
+	// Category groups the entries of a subject, like "simples-nacional"
+	Category string
+	Question string
+	Answer   string
+	// Keywords are the words users may type for this entry besides the ones of the question
+	Keywords  []string
+	UpdatedAt time.Time
+	// SeedVersion identifies the content of the versioned file the entry was last seeded from,
+	// so that the entry is only replaced by the file when the file changes
+	SeedVersion string
+}
+
+// Validate checks the fields of the entry
+func (e FAQEntry) Validate() error {
+	switch {
+	case !faqIDPattern.MatchString(e.ID) || len(e.ID) > 64:
+		return fmt.Errorf("%w: id %q must be lowercase words separated by hyphens, up to 64 characters", ErrInvalidFAQEntry, e.ID)
+	case strings.TrimSpace(e.Question) == "":
+		return fmt.Errorf("%w: question is required", ErrInvalidFAQEntry)
+	case len([]rune(e.Question)) > MaxFAQQuestionLength:
+		return fmt.Errorf("%w: question longer than %d characters", ErrInvalidFAQEntry, MaxFAQQuestionLength)
+	case strings.TrimSpace(e.Answer) == "":
+		return fmt.Errorf("%w: answer is required", ErrInvalidFAQEntry)
+	case len([]rune(e.Answer)) > MaxFAQAnswerLength:
+		return fmt.Errorf("%w: answer longer than %d characters", ErrInvalidFAQEntry, MaxFAQAnswerLength)
+	}
+	return nil
+}
+
+// FAQMatch is the result of looking up a question of a user in the knowledge base.
+// Entry is set when an entry clearly answers the question, Candidates when several entries may
+// answer it. A match with neither means that the knowledge base has no answer.
+type FAQMatch struct {
+	Entry      *FAQEntry
+	Candidates []FAQEntry
+}
+
+// Found reports whether an entry clearly answers the question
+func (m FAQMatch) Found() bool {
+	return m.Entry != nil
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFAQEntry(t *testing.T) {
+	valid := FAQEntry{ID: "fator-r", Question: "O que é o Fator R?", Answer: "É a razão entre a folha e o faturamento."}
+
+	t.Run("should accept a complete entry", func(t *testing.T) {
+		assert.NoError(t, valid.Validate())
+	})
+
+	t.Run("should reject entries missing a field or breaking a limit", func(t *testing.T) {
+		cases := map[string]func(e *FAQEntry){
+			"empty id":        func(e *FAQEntry) { e.ID = "" },
+			"uppercase id":    func(e *FAQEntry) { e.ID = "Fator-R" },
+			"spaced id":       func(e *FAQEntry) { e.ID = "fator r" },
+			"trailing hyphen": func(e *FAQEntry) { e.ID = "fator-" },
+			"long id":         func(e *FAQEntry) { e.ID = strings.Repeat("a", 65) },
+			"no question":     func(e *FAQEntry) { e.Question = "  " },
+			"long question":   func(e *FAQEntry) { e.Question = strings.Repeat("?", MaxFAQQuestionLength+1) },
+			"no answer":       func(e *FAQEntry) { e.Answer = "" },
+			"long answer":     func(e *FAQEntry) { e.Answer = strings.Repeat("é", MaxFAQAnswerLength+1) },
+		}
+		for name, change := range cases {
+			// arrange
+			entry := valid
+			change(&entry)
+
+			// act
+			err := entry.Validate()
+
+			// assert
+			assert.ErrorIs(t, err, ErrInvalidFAQEntry, name)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrHandoffClosed is returned when an agent acts on a handoff already given back to the bot
+	ErrHandoffClosed = errors.New("handoff closed")
+	// ErrHandoffAssigned is returned when an agent acts on a handoff assigned to another agent
+	ErrHandoffAssigned = errors.New("handoff assigned to another agent")
+	// ErrAgentRequired is returned when an agent operation does not identify the agent
+	ErrAgentRequired = errors.New("agent required")
+)
+
+// HandoffStatus is the position of a handoff in the agent inbox
+type HandoffStatus string
+
+const (
+	// HandoffWaiting is a handoff no agent took yet
+	HandoffWaiting HandoffStatus = "waiting"
+	// HandoffAssigned is a handoff an agent is answering
+	HandoffAssigned HandoffStatus = "assigned"
+	// HandoffClosed is a handoff given back to the bot
+	HandoffClosed HandoffStatus = "closed"
+)
+
+// IsValid reports whether the status is one of the handoff statuses
+func (s HandoffStatus) IsValid() bool {
+	switch s {
+	case HandoffWaiting, HandoffAssigned, HandoffClosed:
+		return true
+	}
+	return false
+}
+
+// HandoffReason tells why a conversation was handed off to a person
+type HandoffReason string
+
+const (
+	// HandoffReasonOther is a user who chose "Outros" in the main menu
+	HandoffReasonOther HandoffReason = "other"
+	// HandoffReasonRequested is a user who asked to talk to a person
+	HandoffReasonRequested HandoffReason = "requested"
+	// HandoffReasonUnhandled is a user whose answers the bot could not understand
+	HandoffReasonUnhandled HandoffReason = "unhandled"
+	// HandoffReasonQuestion is a user whose question the knowledge base could not answer
+	HandoffReasonQuestion HandoffReason = "question"
+)
+
+// Handoff is a conversation handed off to the agents. The bot does not answer the user
+// until an agent closes the handoff. A contact has at most one handoff, replaced when
+// they are handed off again after it was closed.
+type Handoff struct {
+	Phone string
+	// Name is the WhatsApp profile name of the user
+	Name   string
+	Reason HandoffReason
+	Status HandoffStatus
+	// Agent identifies the agent answering the user
+	Agent    string
+	OpenedAt time.Time
+	// LastMessageAt is when the user last wrote, and LastReplyAt when an agent last answered
+	LastMessageAt time.Time
+	LastReplyAt   time.Time
+	UpdatedAt     time.Time
+	ClosedAt      time.Time
+	// Version counts the saves of the handoff, so that a change made to a stale copy is
+	// refused instead of overwriting the changes saved in the meantime
+	Version int
+
+	// events holds the domain events raised since the handoff was loaded
+	events []Event
+}
+
+// NewHandoff opens a handoff waiting for an agent, raising a HandoffOpened event
+func NewHandoff(phone, name string, reason HandoffReason, now time.Time) *Handoff {
+	h := &Handoff{
+		Phone:         phone,
+		Name:          name,
+		Reason:        reason,
+		Status:        HandoffWaiting,
+		OpenedAt:      now,
+		LastMessageAt: now,
+		UpdatedAt:     now,
+	}
+	h.events = append(h.events, HandoffOpened{Handoff: *h, At: now})
+	return h
+}
+
+// IsOpen reports whether the handoff was not given back to the bot yet
+func (h *Handoff) IsOpen() bool {
+	return h.Status != HandoffClosed
+}
+
+// Receive records a message of the user
+func (h *Handoff) Receive(name string, now time.Time) {
+	if name != "" {
+		h.Name = name
+	}
+	h.LastMessageAt = now
+	h.UpdatedAt = now
+}
+
+// Assign gives the handoff to an agent, transferring it when another agent has it
+func (h *Handoff) Assign(agent string, now time.Time) error {
+	if agent == "" {
+		return ErrAgentRequired
+	}
+	if !h.IsOpen() {
+		return fmt.Errorf("%w: %s", ErrHandoffClosed, h.Phone)
+	}
+
+	h.Agent = agent
+	h.Status = HandoffAssigned
+	h.UpdatedAt = now
+	return nil
+}
+
+// Reply records an answer of the agent, who takes the handoff if no agent has it.
+// Only the assigned agent may answer.
+func (h *Handoff) Reply(agent string, now time.Time) error {
+	if agent == "" {
+		return ErrAgentRequired
+	}
+	if !h.IsOpen() {
+		return fmt.Errorf("%w: %s", ErrHandoffClosed, h.Phone)
+	}
+	if h.Agent != "" && h.Agent != agent {
+		return fmt.Errorf("%w: %s is answered by %s", ErrHandoffAssigned, h.Phone, h.Agent)
+	}
+
+	h.Agent = agent
+	h.Status = HandoffAssigned
+	h.LastReplyAt = now
+	h.UpdatedAt = now
+	return nil
+}
+
+// Close gives the conversation back to the bot
+func (h *Handoff) Close(now time.Time) error {
+	if !h.IsOpen() {
+		return fmt.Errorf("%w: %s", ErrHandoffClosed, h.Phone)
+	}
+
+	h.Status = HandoffClosed
+	h.ClosedAt = now
+	h.UpdatedAt = now
+	return nil
+}
+
+// PullEvents returns the events raised by the handoff and clears them
+func (h *Handoff) PullEvents() []Event {
+	events := h.events
+	h.events = nil
+	return events
+}
+
+// HandoffFilter selects the handoffs returned by a query
+type HandoffFilter struct {
+	// Status restricts the handoffs to a status when set, else only the open handoffs are selected
+	Status HandoffStatus
+	// Agent restricts the handoffs to the ones assigned to the agent when set
+	Agent string
+	// Limit is the maximum number of handoffs returned, all of them when not positive
+	Limit int
+}
+
+// Matches reports whether the handoff is selected by the filter
+func (f HandoffFilter) Matches(h Handoff) bool {
+	if f.Status == "" && !h.IsOpen() || f.Status != "" && h.Status != f.Status {
+		return false
+	}
+	return f.Agent == "" || h.Agent == f.Agent
+}
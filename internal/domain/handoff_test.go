@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandoff(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should open a handoff waiting for an agent and raise an event", func(t *testing.T) {
+		// act
+		handoff := NewHandoff("554499887766", "Dra. Ana", HandoffReasonOther, now)
+		events := handoff.PullEvents()
+
+		// assert
+		assert.Equal(t, HandoffWaiting, handoff.Status)
+		assert.True(t, handoff.IsOpen())
+		assert.Equal(t, now, handoff.LastMessageAt)
+		assert.Len(t, events, 1)
+		assert.Equal(t, HandoffOpenedEvent, events[0].EventName())
+		assert.Equal(t, now, events[0].OccurredAt())
+		assert.Equal(t, "Dra. Ana", events[0].(HandoffOpened).Handoff.Name)
+		assert.Empty(t, handoff.PullEvents())
+	})
+
+	t.Run("should record the messages of the user", func(t *testing.T) {
+		// arrange
+		handoff := NewHandoff("554499887766", "Dra. Ana", HandoffReasonOther, now)
+
+		// act
+		handoff.Receive("", now.Add(time.Minute))
+
+		// assert
+		assert.Equal(t, "Dra. Ana", handoff.Name)
+		assert.Equal(t, now.Add(time.Minute), handoff.LastMessageAt)
+		assert.Equal(t, now.Add(time.Minute), handoff.UpdatedAt)
+	})
+
+	t.Run("should assign and transfer the handoff", func(t *testing.T) {
+		// arrange
+		handoff := NewHandoff("554499887766", "", HandoffReasonOther, now)
+
+		// act
+		assignErr := handoff.Assign("maria", now.Add(time.Minute))
+		transferErr := handoff.Assign("joao", now.Add(2*time.Minute))
+
+		// assert
+		assert.NoError(t, assignErr)
+		assert.NoError(t, transferErr)
+		assert.Equal(t, HandoffAssigned, handoff.Status)
+		assert.Equal(t, "joao", handoff.Agent)
+		assert.ErrorIs(t, handoff.Assign("", now), ErrAgentRequired)
+	})
+
+	t.Run("should let the first agent who replies take the handoff", func(t *testing.T) {
+		// arrange
+		handoff := NewHandoff("554499887766", "", HandoffReasonUnhandled, now)
+
+		// act
+		err := handoff.Reply("maria", now.Add(time.Minute))
+		otherErr := handoff.Reply("joao", now.Add(2*time.Minute))
+
+		// assert
+		assert.NoError(t, err)
+		assert.ErrorIs(t, otherErr, ErrHandoffAssigned)
+		assert.Equal(t, "maria", handoff.Agent)
+		assert.Equal(t, HandoffAssigned, handoff.Status)
+		assert.Equal(t, now.Add(time.Minute), handoff.LastReplyAt)
+	})
+
+	t.Run("should refuse to act on a closed handoff", func(t *testing.T) {
+		// arrange
+		handoff := NewHandoff("554499887766", "", HandoffReasonRequested, now)
+
+		// act
+		err := handoff.Close(now.Add(time.Hour))
+
+		// assert
+		assert.NoError(t, err)
+		assert.False(t, handoff.IsOpen())
+		assert.Equal(t, now.Add(time.Hour), handoff.ClosedAt)
+		assert.ErrorIs(t, handoff.Close(now), ErrHandoffClosed)
+		assert.ErrorIs(t, handoff.Assign("maria", now), ErrHandoffClosed)
+		assert.ErrorIs(t, handoff.Reply("maria", now), ErrHandoffClosed)
+	})
+
+	t.Run("should select the open handoffs unless a status is given", func(t *testing.T) {
+		// arrange
+		waiting := *NewHandoff("554400000001", "", HandoffReasonOther, now)
+		assigned := *NewHandoff("554400000002", "", HandoffReasonOther, now)
+		assigned.Assign("maria", now)
+		closed := *NewHandoff("554400000003", "", HandoffReasonOther, now)
+		closed.Assign("maria", now)
+		closed.Close(now)
+
+		// act & assert
+		assert.True(t, HandoffFilter{}.Matches(waiting))
+		assert.True(t, HandoffFilter{}.Matches(assigned))
+		assert.False(t, HandoffFilter{}.Matches(closed))
+		assert.True(t, HandoffFilter{Status: HandoffClosed}.Matches(closed))
+		assert.False(t, HandoffFilter{Status: HandoffWaiting}.Matches(assigned))
+		assert.True(t, HandoffFilter{Agent: "maria"}.Matches(assigned))
+		assert.False(t, HandoffFilter{Agent: "maria"}.Matches(waiting))
+	})
+
+	t.Run("should tell the valid statuses", func(t *testing.T) {
+		assert.True(t, HandoffWaiting.IsValid())
+		assert.True(t, HandoffClosed.IsValid())
+		assert.False(t, HandoffStatus("open").IsValid())
+	})
+}
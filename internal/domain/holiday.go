@@ -0,0 +1,99 @@
+package domain
+
+import "time"
+
+// Holiday is a day without business. National holidays have neither State nor MunicipalityCode,
+// state holidays only have State and municipal holidays have both.
+type Holiday struct {
+	Name string
+	// Year restricts the holiday to a single year, it is observed every year when zero
+	Year  int
+	Month time.Month
+	Day   int
+	// State is the code (UF) of the state and MunicipalityCode the IBGE code of the municipality
+	State            string
+	MunicipalityCode string
+}
+
+// nationalHolidays are the national holidays on fixed dates
+var nationalHolidays = []Holiday{
+	{Name: "Confraternização Universal", Month: time.January, Day: 1},
+	{Name: "Tiradentes", Month: time.April, Day: 21},
+	{Name: "Dia do Trabalho", Month: time.May, Day: 1},
+	{Name: "Independência do Brasil", Month: time.September, Day: 7},
+	{Name: "Nossa Senhora Aparecida", Month: time.October, Day: 12},
+	{Name: "Finados", Month: time.November, Day: 2},
+	{Name: "Proclamação da República", Month: time.November, Day: 15},
+	{Name: "Dia da Consciência Negra", Month: time.November, Day: 20},
+	{Name: "Natal", Month: time.December, Day: 25},
+}
+
+// Easter returns the date of Easter Sunday of the year in the Gregorian calendar
+// (anonymous Gregorian algorithm)
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// moveableHolidays returns the holidays of the year whose date depends on Easter.
+// Carnaval and Corpus Christi are optional days off (pontos facultativos) observed by the accounting offices.
+func moveableHolidays(year int) []Holiday {
+	easter := Easter(year)
+	holiday := func(name string, days int) Holiday {
+		date := easter.AddDate(0, 0, days)
+		return Holiday{Name: name, Year: year, Month: date.Month(), Day: date.Day()}
+	}
+	return []Holiday{
+		holiday("Carnaval", -48),
+		holiday("Carnaval", -47),
+		holiday("Sexta-feira Santa", -2),
+		holiday("Corpus Christi", 60),
+	}
+}
+
+// FallsOn reports whether the holiday is observed on the date
+func (h Holiday) FallsOn(date time.Time) bool {
+	return (h.Year == 0 || h.Year == date.Year()) && h.Month == date.Month() && h.Day == date.Day()
+}
+
+// AppliesTo reports whether the holiday is observed in the municipality of the state
+func (h Holiday) AppliesTo(state, municipalityCode string) bool {
+	if h.State != "" && h.State != state {
+		return false
+	}
+	return h.MunicipalityCode == "" || h.MunicipalityCode == municipalityCode
+}
+
+// Calendar tells the holidays of a place: the national holidays and the state and municipal
+// holidays it was created with
+type Calendar struct {
+	holidays []Holiday
+}
+
+// NewCalendar creates a calendar with the national holidays and the given ones
+func NewCalendar(holidays ...Holiday) *Calendar {
+	return &Calendar{holidays: holidays}
+}
+
+// HolidayOn returns the holiday observed on the date in the municipality of the state, if any.
+// The date is read in its own location.
+func (c *Calendar) HolidayOn(date time.Time, state, municipalityCode string) (Holiday, bool) {
+	candidates := append(append([]Holiday{}, nationalHolidays...), moveableHolidays(date.Year())...)
+	candidates = append(candidates, c.holidays...)
+	for _, h := range candidates {
+		if h.FallsOn(date) && h.AppliesTo(state, municipalityCode) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
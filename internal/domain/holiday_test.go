@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEaster(t *testing.T) {
+	tests := []struct {
+		year     int
+		expected time.Time
+	}{
+		{year: 2019, expected: time.Date(2019, time.April, 21, 0, 0, 0, 0, time.UTC)},
+		{year: 2024, expected: time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{year: 2025, expected: time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC)},
+		{year: 2026, expected: time.Date(2026, time.April, 5, 0, 0, 0, 0, time.UTC)},
+		{year: 2038, expected: time.Date(2038, time.April, 25, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run("should compute Easter of "+tt.expected.Format("2006"), func(t *testing.T) {
+			// act
+			easter := Easter(tt.year)
+
+			// assert
+			assert.Equal(t, tt.expected, easter)
+		})
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 10, 0, 0, 0, time.UTC)
+	}
+	maringa := Holiday{Name: "Aniversário de Maringá", Month: time.May, Day: 10, State: "PR", MunicipalityCode: "4115200"}
+	parana := Holiday{Name: "Emancipação do Paraná", Month: time.December, Day: 19, State: "PR"}
+	election := Holiday{Name: "Eleições", Year: 2026, Month: time.October, Day: 4}
+
+	t.Run("should know the national and moveable holidays", func(t *testing.T) {
+		// arrange
+		calendar := NewCalendar()
+
+		// act & assert
+		for _, tt := range []struct {
+			date time.Time
+			name string
+		}{
+			{date: day(2025, time.January, 1), name: "Confraternização Universal"},
+			{date: day(2025, time.March, 3), name: "Carnaval"},
+			{date: day(2025, time.March, 4), name: "Carnaval"},
+			{date: day(2025, time.April, 18), name: "Sexta-feira Santa"},
+			{date: day(2025, time.June, 19), name: "Corpus Christi"},
+			{date: day(2024, time.November, 20), name: "Dia da Consciência Negra"},
+		} {
+			holiday, ok := calendar.HolidayOn(tt.date, "SP", "")
+			assert.True(t, ok, tt.date.Format("2006-01-02"))
+			assert.Equal(t, tt.name, holiday.Name)
+		}
+	})
+
+	t.Run("should not find holidays on business days", func(t *testing.T) {
+		// arrange
+		calendar := NewCalendar(maringa, parana)
+
+		// act
+		_, ok := calendar.HolidayOn(day(2025, time.March, 10), "PR", "4115200")
+
+		// assert
+		assert.False(t, ok)
+	})
+
+	t.Run("should restrict state and municipal holidays to their place", func(t *testing.T) {
+		// arrange
+		calendar := NewCalendar(maringa, parana)
+
+		// act
+		_, inMaringa := calendar.HolidayOn(day(2025, time.May, 10), "PR", "4115200")
+		_, inCuritiba := calendar.HolidayOn(day(2025, time.May, 10), "PR", "4106902")
+		_, inParana := calendar.HolidayOn(day(2025, time.December, 19), "PR", "4106902")
+		_, inSaoPaulo := calendar.HolidayOn(day(2025, time.December, 19), "SP", "")
+
+		// assert
+		assert.True(t, inMaringa)
+		assert.False(t, inCuritiba)
+		assert.True(t, inParana)
+		assert.False(t, inSaoPaulo)
+	})
+
+	t.Run("should observe holidays of a single year only in that year", func(t *testing.T) {
+		// arrange
+		calendar := NewCalendar(election)
+
+		// act
+		_, in2026 := calendar.HolidayOn(day(2026, time.October, 4), "", "")
+		_, in2027 := calendar.HolidayOn(day(2027, time.October, 4), "", "")
+
+		// assert
+		assert.True(t, in2026)
+		assert.False(t, in2027)
+	})
+
+	t.Run("should read the date in its own location", func(t *testing.T) {
+		// arrange
+		calendar := NewCalendar()
+		loc := time.FixedZone("-03", -3*60*60)
+
+		// act
+		_, ok := calendar.HolidayOn(time.Date(2025, time.December, 24, 22, 0, 0, 0, loc), "", "")
+
+		// assert
+		assert.False(t, ok)
+	})
+}
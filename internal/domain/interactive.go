@@ -0,0 +1,37 @@
+package domain
+
+// ReplyButton is a tappable button of an interactive message
+type ReplyButton struct {
+	ID    string
+	Title string
+}
+
+// ButtonMessage is an interactive message with up to three reply buttons
+type ButtonMessage struct {
+	Header  string
+	Body    string
+	Footer  string
+	Buttons []ReplyButton
+}
+
+// ListRow is a selectable row of a list message
+type ListRow struct {
+	ID          string
+	Title       string
+	Description string
+}
+
+// ListSection groups rows of a list message
+type ListSection struct {
+	Title string
+	Rows  []ListRow
+}
+
+// ListMessage is an interactive message that opens a list of options
+type ListMessage struct {
+	Header     string
+	Body       string
+	Footer     string
+	ButtonText string
+	Sections   []ListSection
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidLeadStage is returned when a lead can not move to the requested funnel stage
+var ErrInvalidLeadStage = errors.New("invalid lead stage")
+
+// LeadStage is the position of a lead in the sales funnel
+type LeadStage string
+
+const (
+	// LeadStageNew is a prospect who chose to open a company but has not answered every question
+	LeadStageNew LeadStage = "new"
+	// LeadStageQualified is a prospect who answered the questions of the flow and awaits the sales team
+	LeadStageQualified LeadStage = "qualified"
+	// LeadStageContacted is a prospect already approached by the sales team
+	LeadStageContacted LeadStage = "contacted"
+	// LeadStageConverted is a prospect who became a client
+	LeadStageConverted LeadStage = "converted"
+	// LeadStageLost is a prospect who gave up
+	LeadStageLost LeadStage = "lost"
+)
+
+// leadTransitions lists the stages each stage can move to
+var leadTransitions = map[LeadStage][]LeadStage{
+	LeadStageNew:       {LeadStageQualified, LeadStageContacted, LeadStageLost},
+	LeadStageQualified: {LeadStageContacted, LeadStageConverted, LeadStageLost},
+	LeadStageContacted: {LeadStageConverted, LeadStageLost},
+	LeadStageLost:      {LeadStageContacted},
+}
+
+// IsValid reports whether the stage is one of the funnel stages
+func (s LeadStage) IsValid() bool {
+	switch s {
+	case LeadStageNew, LeadStageQualified, LeadStageContacted, LeadStageConverted, LeadStageLost:
+		return true
+	}
+	return false
+}
+
+// LeadSource tells where a lead came from
+type LeadSource string
+
+// LeadSourceWhatsApp is a lead captured by the WhatsApp chatbot
+const LeadSourceWhatsApp LeadSource = "whatsapp"
+
+// Lead is a doctor who wants to open a company with ContaMed
+type Lead struct {
+	Phone string
+	// Name is the WhatsApp profile name of the prospect
+	Name   string
+	HasCRM bool
+	// CRMNumber, CRMState and CRMSuffix identify the registration of the doctor in the regional
+	// medical council
+	CRMNumber string
+	CRMState  string
+	CRMSuffix string
+	// CRMVerified tells whether the federal council confirmed the registration
+	CRMVerified bool
+	// Specialty is the medical specialty of the doctor, as typed
+	Specialty string
+	// State and Municipality are where the doctor wants to open the company,
+	// and MunicipalityCode the IBGE code of the municipality when it is known
+	State            string
+	Municipality     string
+	MunicipalityCode string
+	Source           LeadSource
+	Stage            LeadStage
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	QualifiedAt      time.Time
+
+	// events holds the domain events raised since the lead was loaded
+	events []Event
+}
+
+// NewLead creates a lead in the first stage of the funnel
+func NewLead(phone string, source LeadSource, now time.Time) *Lead {
+	return &Lead{
+		Phone:     phone,
+		Source:    source,
+		Stage:     LeadStageNew,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Qualify moves a new lead to the qualified stage once the flow collected where the company
+// is to be opened, raising a LeadQualified event. Leads past the new stage are left untouched.
+func (l *Lead) Qualify(now time.Time) error {
+	if l.Stage != LeadStageNew {
+		return nil
+	}
+	if l.State == "" || l.Municipality == "" {
+		return fmt.Errorf("%w: lead %s has no state or municipality", ErrInvalidLeadStage, l.Phone)
+	}
+
+	l.Stage = LeadStageQualified
+	l.QualifiedAt = now
+	l.UpdatedAt = now
+	snapshot := *l
+	snapshot.events = nil
+	l.events = append(l.events, LeadQualified{Lead: snapshot, At: now})
+	return nil
+}
+
+// MoveTo moves the lead along the funnel as the sales team works on it
+func (l *Lead) MoveTo(stage LeadStage, now time.Time) error {
+	if stage == l.Stage {
+		return nil
+	}
+	for _, next := range leadTransitions[l.Stage] {
+		if next != stage {
+			continue
+		}
+		if stage == LeadStageQualified {
+			return l.Qualify(now)
+		}
+		l.Stage = stage
+		l.UpdatedAt = now
+		return nil
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidLeadStage, l.Stage, stage)
+}
+
+// Touch records a change of the lead data
+func (l *Lead) Touch(now time.Time) {
+	l.UpdatedAt = now
+}
+
+// Location returns the zone of the lead, for contacting them at a suitable time
+func (l *Lead) Location() *time.Location {
+	return Timezone(l.Phone, l.State, l.MunicipalityCode)
+}
+
+// PullEvents returns the events raised by the lead and clears them
+func (l *Lead) PullEvents() []Event {
+	events := l.events
+	l.events = nil
+	return events
+}
+
+// LeadFilter selects the leads returned by a query
+type LeadFilter struct {
+	// Stage restricts the leads to a funnel stage when set
+	Stage LeadStage
+	// Limit is the maximum number of leads returned, all of them when not positive
+	Limit int
+}
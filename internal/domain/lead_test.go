@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLead(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// newAnsweredLead creates a new lead with the answers needed to qualify it
+	newAnsweredLead := func() *Lead {
+		lead := NewLead("554499887766", LeadSourceWhatsApp, now)
+		lead.State = "PR"
+		lead.Municipality = "Maringá"
+		return lead
+	}
+
+	t.Run("should qualify a lead once and raise an event", func(t *testing.T) {
+		// arrange
+		lead := newAnsweredLead()
+
+		// act
+		err := lead.Qualify(now.Add(time.Minute))
+		againErr := lead.Qualify(now.Add(2 * time.Minute))
+		events := lead.PullEvents()
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, againErr)
+		assert.Equal(t, LeadStageQualified, lead.Stage)
+		assert.Equal(t, now.Add(time.Minute), lead.QualifiedAt)
+		assert.Len(t, events, 1)
+		assert.Equal(t, LeadQualifiedEvent, events[0].EventName())
+		assert.Equal(t, now.Add(time.Minute), events[0].OccurredAt())
+		assert.Equal(t, "Maringá", events[0].(LeadQualified).Lead.Municipality)
+		assert.Empty(t, lead.PullEvents())
+	})
+
+	t.Run("should not qualify a lead without state and municipality", func(t *testing.T) {
+		// arrange
+		lead := NewLead("554499887766", LeadSourceWhatsApp, now)
+		lead.State = "PR"
+
+		// act
+		err := lead.Qualify(now)
+
+		// assert
+		assert.ErrorIs(t, err, ErrInvalidLeadStage)
+		assert.Equal(t, LeadStageNew, lead.Stage)
+		assert.Empty(t, lead.PullEvents())
+	})
+
+	t.Run("should move along the funnel", func(t *testing.T) {
+		// arrange
+		lead := newAnsweredLead()
+
+		// act & assert
+		assert.NoError(t, lead.MoveTo(LeadStageQualified, now))
+		assert.NoError(t, lead.MoveTo(LeadStageContacted, now.Add(time.Hour)))
+		assert.NoError(t, lead.MoveTo(LeadStageConverted, now.Add(2*time.Hour)))
+		assert.Equal(t, LeadStageConverted, lead.Stage)
+		assert.Equal(t, now.Add(2*time.Hour), lead.UpdatedAt)
+		assert.Len(t, lead.PullEvents(), 1)
+	})
+
+	t.Run("should reject moves backwards and out of the final stages", func(t *testing.T) {
+		// arrange
+		lead := newAnsweredLead()
+		lead.MoveTo(LeadStageContacted, now)
+
+		// act & assert
+		assert.ErrorIs(t, lead.MoveTo(LeadStageNew, now), ErrInvalidLeadStage)
+		assert.NoError(t, lead.MoveTo(LeadStageLost, now))
+		assert.NoError(t, lead.MoveTo(LeadStageContacted, now))
+		assert.NoError(t, lead.MoveTo(LeadStageConverted, now))
+		assert.ErrorIs(t, lead.MoveTo(LeadStageLost, now), ErrInvalidLeadStage)
+	})
+
+	t.Run("should reject qualifying a lead past the new stage", func(t *testing.T) {
+		for _, stage := range []LeadStage{LeadStageContacted, LeadStageLost, LeadStageConverted} {
+			// arrange
+			lead := newAnsweredLead()
+			lead.Stage = stage
+
+			// act
+			err := lead.MoveTo(LeadStageQualified, now)
+
+			// assert
+			assert.ErrorIs(t, err, ErrInvalidLeadStage, stage)
+			assert.Equal(t, stage, lead.Stage)
+			assert.Empty(t, lead.PullEvents(), stage)
+		}
+	})
+
+	t.Run("should tell the valid stages", func(t *testing.T) {
+		assert.True(t, LeadStageQualified.IsValid())
+		assert.False(t, LeadStage("won").IsValid())
+		assert.False(t, LeadStage("").IsValid())
+	})
+}
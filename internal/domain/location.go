@@ -0,0 +1,41 @@
+package domain
+
+// Municipality is a Brazilian municipality as registered by the IBGE
+type Municipality struct {
+	// Code is the 7 digit IBGE code of the municipality
+	Code string
+	Name string
+	// UF is the code of the state of the municipality
+	UF string
+}
+
+// String returns the name and state of the municipality, like "Maringá/PR"
+func (m Municipality) String() string {
+	return m.Name + "/" + m.UF
+}
+
+// StateMatch is the result of looking up a state typed by a user.
+// UF is set when the text identifies a single state, Candidates when it may refer to several.
+// A match with neither means that the state was not found.
+type StateMatch struct {
+	UF         string
+	Candidates []string
+}
+
+// Found reports whether the text identified a single state
+func (m StateMatch) Found() bool {
+	return m.UF != ""
+}
+
+// MunicipalityMatch is the result of looking up a municipality typed by a user.
+// Municipality is set when the text identifies a single municipality, Candidates when it may
+// refer to several. A match with neither means that the municipality was not found.
+type MunicipalityMatch struct {
+	Municipality *Municipality
+	Candidates   []Municipality
+}
+
+// Found reports whether the text identified a single municipality
+func (m MunicipalityMatch) Found() bool {
+	return m.Municipality != nil
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Media describes a media attachment received in a WhatsApp message
+type Media struct {
+	ID       string
+	MimeType string
+	SHA256   string
+	Caption  string
+	Filename string
+}
+
+// StoredMedia describes a received media file persisted by the media store
+type StoredMedia struct {
+	MessageID string
+	From      string
+	MediaID   string
+	MimeType  string
+	SHA256    string
+	Filename  string
+	Caption   string
+	Location  string
+	Size      int64
+	StoredAt  time.Time
+}
+
+// MediaMessage is an image, document, audio or video message to send, referencing
+// either a media uploaded to WhatsApp by ID or a public URL by Link
+type MediaMessage struct {
+	Type     MessageType
+	ID       string
+	Link     string
+	Caption  string
+	Filename string
+}
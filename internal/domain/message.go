@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// MessageType represents the type of a WhatsApp message
+type MessageType string
+
+const (
+	// MessageTypeText is a plain text message
+	MessageTypeText MessageType = "text"
+	// MessageTypeInteractive is a reply to an interactive button or list message
+	MessageTypeInteractive MessageType = "interactive"
+	// MessageTypeButton is a reply to a quick reply button of a template message
+	MessageTypeButton MessageType = "button"
+	// MessageTypeImage is an image, such as a photo of a document
+	MessageTypeImage MessageType = "image"
+	// MessageTypeDocument is a document, such as a PDF
+	MessageTypeDocument MessageType = "document"
+	// MessageTypeAudio is an audio or voice message
+	MessageTypeAudio MessageType = "audio"
+	// MessageTypeVideo is a video
+	MessageTypeVideo MessageType = "video"
+	// MessageTypeSticker is a sticker
+	MessageTypeSticker MessageType = "sticker"
+	// MessageTypeTemplate is a template message, only sent by the business
+	MessageTypeTemplate MessageType = "template"
+)
+
+// InboundMessage represents a message received from a WhatsApp user
+type InboundMessage struct {
+	ID   string
+	From string
+	// ProfileName is the WhatsApp profile name of the sender, if informed by the webhook
+	ProfileName string
+	Timestamp   time.Time
+	Type        MessageType
+	Text        string
+	// ReplyID is the ID of the button or list row tapped by the user, if any
+	ReplyID string
+	// ReplyTitle is the title of the button or list row tapped by the user, if any
+	ReplyTitle string
+	// Media is the attachment of image, document, audio, video and sticker messages
+	Media *Media
+}
+
+// SendResult identifies a message accepted by WhatsApp for delivery
+type SendResult struct {
+	// MessageID is the WhatsApp message ID, also used by delivery statuses
+	MessageID string
+	// MessageStatus is set for template messages, e.g. "accepted" or "held_for_quality_assessment"
+	MessageStatus string
+	// Input is the phone number the message was sent to
+	Input string
+	// WaID is the WhatsApp ID of the recipient
+	WaID string
+}
+
+// MessageDirection tells whether a message was received or sent by the business
+type MessageDirection string
+
+const (
+	// DirectionInbound is a message received from a WhatsApp user
+	DirectionInbound MessageDirection = "inbound"
+	// DirectionOutbound is a message sent to a WhatsApp user
+	DirectionOutbound MessageDirection = "outbound"
+)
+
+// MessageRecord is an entry of the message history of a contact
+type MessageRecord struct {
+	// ID is the WhatsApp message ID
+	ID        string
+	Phone     string
+	Direction MessageDirection
+	Type      MessageType
+	// Text is the text, caption or a short description of the message
+	Text      string
+	Timestamp time.Time
+}
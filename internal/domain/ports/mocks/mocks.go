@@ -0,0 +1,1428 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ports.go
+//
+// Generated by this command:
+//
+//	mockgen -source=ports.go -destination=mocks/mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/2rprbm/conta-med-backend/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMessageProcessor is a mock of MessageProcessor interface.
+type MockMessageProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageProcessorMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageProcessorMockRecorder is the mock recorder for MockMessageProcessor.
+type MockMessageProcessorMockRecorder struct {
+	mock *MockMessageProcessor
+}
+
+// NewMockMessageProcessor creates a new mock instance.
+func NewMockMessageProcessor(ctrl *gomock.Controller) *MockMessageProcessor {
+	mock := &MockMessageProcessor{ctrl: ctrl}
+	mock.recorder = &MockMessageProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageProcessor) EXPECT() *MockMessageProcessorMockRecorder {
+	return m.recorder
+}
+
+// ProcessMessage mocks base method.
+func (m *MockMessageProcessor) ProcessMessage(ctx context.Context, msg domain.InboundMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessMessage", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessMessage indicates an expected call of ProcessMessage.
+func (mr *MockMessageProcessorMockRecorder) ProcessMessage(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessMessage", reflect.TypeOf((*MockMessageProcessor)(nil).ProcessMessage), ctx, msg)
+}
+
+// MockStatusProcessor is a mock of StatusProcessor interface.
+type MockStatusProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusProcessorMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusProcessorMockRecorder is the mock recorder for MockStatusProcessor.
+type MockStatusProcessorMockRecorder struct {
+	mock *MockStatusProcessor
+}
+
+// NewMockStatusProcessor creates a new mock instance.
+func NewMockStatusProcessor(ctrl *gomock.Controller) *MockStatusProcessor {
+	mock := &MockStatusProcessor{ctrl: ctrl}
+	mock.recorder = &MockStatusProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusProcessor) EXPECT() *MockStatusProcessorMockRecorder {
+	return m.recorder
+}
+
+// ProcessStatus mocks base method.
+func (m *MockStatusProcessor) ProcessStatus(ctx context.Context, event domain.StatusEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessStatus", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessStatus indicates an expected call of ProcessStatus.
+func (mr *MockStatusProcessorMockRecorder) ProcessStatus(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessStatus", reflect.TypeOf((*MockStatusProcessor)(nil).ProcessStatus), ctx, event)
+}
+
+// MockDeliveryTracker is a mock of DeliveryTracker interface.
+type MockDeliveryTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryTrackerMockRecorder
+	isgomock struct{}
+}
+
+// MockDeliveryTrackerMockRecorder is the mock recorder for MockDeliveryTracker.
+type MockDeliveryTrackerMockRecorder struct {
+	mock *MockDeliveryTracker
+}
+
+// NewMockDeliveryTracker creates a new mock instance.
+func NewMockDeliveryTracker(ctrl *gomock.Controller) *MockDeliveryTracker {
+	mock := &MockDeliveryTracker{ctrl: ctrl}
+	mock.recorder = &MockDeliveryTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryTracker) EXPECT() *MockDeliveryTrackerMockRecorder {
+	return m.recorder
+}
+
+// Timeline mocks base method.
+func (m *MockDeliveryTracker) Timeline(ctx context.Context, messageID string) (*domain.DeliveryTimeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeline", ctx, messageID)
+	ret0, _ := ret[0].(*domain.DeliveryTimeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Timeline indicates an expected call of Timeline.
+func (mr *MockDeliveryTrackerMockRecorder) Timeline(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeline", reflect.TypeOf((*MockDeliveryTracker)(nil).Timeline), ctx, messageID)
+}
+
+// MockMessageSender is a mock of MessageSender interface.
+type MockMessageSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageSenderMockRecorder is the mock recorder for MockMessageSender.
+type MockMessageSenderMockRecorder struct {
+	mock *MockMessageSender
+}
+
+// NewMockMessageSender creates a new mock instance.
+func NewMockMessageSender(ctrl *gomock.Controller) *MockMessageSender {
+	mock := &MockMessageSender{ctrl: ctrl}
+	mock.recorder = &MockMessageSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageSender) EXPECT() *MockMessageSenderMockRecorder {
+	return m.recorder
+}
+
+// SendButtonMessageContext mocks base method.
+func (m *MockMessageSender) SendButtonMessageContext(ctx context.Context, to string, message domain.ButtonMessage) (*domain.SendResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendButtonMessageContext", ctx, to, message)
+	ret0, _ := ret[0].(*domain.SendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendButtonMessageContext indicates an expected call of SendButtonMessageContext.
+func (mr *MockMessageSenderMockRecorder) SendButtonMessageContext(ctx, to, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendButtonMessageContext", reflect.TypeOf((*MockMessageSender)(nil).SendButtonMessageContext), ctx, to, message)
+}
+
+// SendListMessageContext mocks base method.
+func (m *MockMessageSender) SendListMessageContext(ctx context.Context, to string, message domain.ListMessage) (*domain.SendResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendListMessageContext", ctx, to, message)
+	ret0, _ := ret[0].(*domain.SendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendListMessageContext indicates an expected call of SendListMessageContext.
+func (mr *MockMessageSenderMockRecorder) SendListMessageContext(ctx, to, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendListMessageContext", reflect.TypeOf((*MockMessageSender)(nil).SendListMessageContext), ctx, to, message)
+}
+
+// SendMediaMessageContext mocks base method.
+func (m *MockMessageSender) SendMediaMessageContext(ctx context.Context, to string, message domain.MediaMessage) (*domain.SendResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMediaMessageContext", ctx, to, message)
+	ret0, _ := ret[0].(*domain.SendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMediaMessageContext indicates an expected call of SendMediaMessageContext.
+func (mr *MockMessageSenderMockRecorder) SendMediaMessageContext(ctx, to, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMediaMessageContext", reflect.TypeOf((*MockMessageSender)(nil).SendMediaMessageContext), ctx, to, message)
+}
+
+// SendTemplateMessageContext mocks base method.
+func (m *MockMessageSender) SendTemplateMessageContext(ctx context.Context, to string, template domain.TemplateMessage) (*domain.SendResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTemplateMessageContext", ctx, to, template)
+	ret0, _ := ret[0].(*domain.SendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendTemplateMessageContext indicates an expected call of SendTemplateMessageContext.
+func (mr *MockMessageSenderMockRecorder) SendTemplateMessageContext(ctx, to, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTemplateMessageContext", reflect.TypeOf((*MockMessageSender)(nil).SendTemplateMessageContext), ctx, to, template)
+}
+
+// SendTextMessageContext mocks base method.
+func (m *MockMessageSender) SendTextMessageContext(ctx context.Context, to, message string) (*domain.SendResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTextMessageContext", ctx, to, message)
+	ret0, _ := ret[0].(*domain.SendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendTextMessageContext indicates an expected call of SendTextMessageContext.
+func (mr *MockMessageSenderMockRecorder) SendTextMessageContext(ctx, to, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTextMessageContext", reflect.TypeOf((*MockMessageSender)(nil).SendTextMessageContext), ctx, to, message)
+}
+
+// MockLeadCapturer is a mock of LeadCapturer interface.
+type MockLeadCapturer struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeadCapturerMockRecorder
+	isgomock struct{}
+}
+
+// MockLeadCapturerMockRecorder is the mock recorder for MockLeadCapturer.
+type MockLeadCapturerMockRecorder struct {
+	mock *MockLeadCapturer
+}
+
+// NewMockLeadCapturer creates a new mock instance.
+func NewMockLeadCapturer(ctrl *gomock.Controller) *MockLeadCapturer {
+	mock := &MockLeadCapturer{ctrl: ctrl}
+	mock.recorder = &MockLeadCapturerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeadCapturer) EXPECT() *MockLeadCapturerMockRecorder {
+	return m.recorder
+}
+
+// CaptureLead mocks base method.
+func (m *MockLeadCapturer) CaptureLead(ctx context.Context, conversation *domain.Conversation, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureLead", ctx, conversation, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CaptureLead indicates an expected call of CaptureLead.
+func (mr *MockLeadCapturerMockRecorder) CaptureLead(ctx, conversation, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureLead", reflect.TypeOf((*MockLeadCapturer)(nil).CaptureLead), ctx, conversation, name)
+}
+
+// MockLeadManager is a mock of LeadManager interface.
+type MockLeadManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeadManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockLeadManagerMockRecorder is the mock recorder for MockLeadManager.
+type MockLeadManagerMockRecorder struct {
+	mock *MockLeadManager
+}
+
+// NewMockLeadManager creates a new mock instance.
+func NewMockLeadManager(ctrl *gomock.Controller) *MockLeadManager {
+	mock := &MockLeadManager{ctrl: ctrl}
+	mock.recorder = &MockLeadManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeadManager) EXPECT() *MockLeadManagerMockRecorder {
+	return m.recorder
+}
+
+// FindLead mocks base method.
+func (m *MockLeadManager) FindLead(ctx context.Context, phone string) (*domain.Lead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLead", ctx, phone)
+	ret0, _ := ret[0].(*domain.Lead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLead indicates an expected call of FindLead.
+func (mr *MockLeadManagerMockRecorder) FindLead(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLead", reflect.TypeOf((*MockLeadManager)(nil).FindLead), ctx, phone)
+}
+
+// ListLeads mocks base method.
+func (m *MockLeadManager) ListLeads(ctx context.Context, filter domain.LeadFilter) ([]domain.Lead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeads", ctx, filter)
+	ret0, _ := ret[0].([]domain.Lead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeads indicates an expected call of ListLeads.
+func (mr *MockLeadManagerMockRecorder) ListLeads(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeads", reflect.TypeOf((*MockLeadManager)(nil).ListLeads), ctx, filter)
+}
+
+// MoveLead mocks base method.
+func (m *MockLeadManager) MoveLead(ctx context.Context, phone string, stage domain.LeadStage) (*domain.Lead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveLead", ctx, phone, stage)
+	ret0, _ := ret[0].(*domain.Lead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveLead indicates an expected call of MoveLead.
+func (mr *MockLeadManagerMockRecorder) MoveLead(ctx, phone, stage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveLead", reflect.TypeOf((*MockLeadManager)(nil).MoveLead), ctx, phone, stage)
+}
+
+// MockHandoffRequester is a mock of HandoffRequester interface.
+type MockHandoffRequester struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandoffRequesterMockRecorder
+	isgomock struct{}
+}
+
+// MockHandoffRequesterMockRecorder is the mock recorder for MockHandoffRequester.
+type MockHandoffRequesterMockRecorder struct {
+	mock *MockHandoffRequester
+}
+
+// NewMockHandoffRequester creates a new mock instance.
+func NewMockHandoffRequester(ctrl *gomock.Controller) *MockHandoffRequester {
+	mock := &MockHandoffRequester{ctrl: ctrl}
+	mock.recorder = &MockHandoffRequesterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandoffRequester) EXPECT() *MockHandoffRequesterMockRecorder {
+	return m.recorder
+}
+
+// RequestHandoff mocks base method.
+func (m *MockHandoffRequester) RequestHandoff(ctx context.Context, conversation *domain.Conversation, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestHandoff", ctx, conversation, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestHandoff indicates an expected call of RequestHandoff.
+func (mr *MockHandoffRequesterMockRecorder) RequestHandoff(ctx, conversation, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestHandoff", reflect.TypeOf((*MockHandoffRequester)(nil).RequestHandoff), ctx, conversation, name)
+}
+
+// MockHandoffManager is a mock of HandoffManager interface.
+type MockHandoffManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandoffManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockHandoffManagerMockRecorder is the mock recorder for MockHandoffManager.
+type MockHandoffManagerMockRecorder struct {
+	mock *MockHandoffManager
+}
+
+// NewMockHandoffManager creates a new mock instance.
+func NewMockHandoffManager(ctrl *gomock.Controller) *MockHandoffManager {
+	mock := &MockHandoffManager{ctrl: ctrl}
+	mock.recorder = &MockHandoffManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandoffManager) EXPECT() *MockHandoffManagerMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockHandoffManager) Assign(ctx context.Context, phone, agent string) (*domain.Handoff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, phone, agent)
+	ret0, _ := ret[0].(*domain.Handoff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockHandoffManagerMockRecorder) Assign(ctx, phone, agent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockHandoffManager)(nil).Assign), ctx, phone, agent)
+}
+
+// Close mocks base method.
+func (m *MockHandoffManager) Close(ctx context.Context, phone string) (*domain.Handoff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, phone)
+	ret0, _ := ret[0].(*domain.Handoff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockHandoffManagerMockRecorder) Close(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockHandoffManager)(nil).Close), ctx, phone)
+}
+
+// FindHandoff mocks base method.
+func (m *MockHandoffManager) FindHandoff(ctx context.Context, phone string) (*domain.Handoff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindHandoff", ctx, phone)
+	ret0, _ := ret[0].(*domain.Handoff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHandoff indicates an expected call of FindHandoff.
+func (mr *MockHandoffManagerMockRecorder) FindHandoff(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHandoff", reflect.TypeOf((*MockHandoffManager)(nil).FindHandoff), ctx, phone)
+}
+
+// ListHandoffs mocks base method.
+func (m *MockHandoffManager) ListHandoffs(ctx context.Context, filter domain.HandoffFilter) ([]domain.Handoff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHandoffs", ctx, filter)
+	ret0, _ := ret[0].([]domain.Handoff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHandoffs indicates an expected call of ListHandoffs.
+func (mr *MockHandoffManagerMockRecorder) ListHandoffs(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHandoffs", reflect.TypeOf((*MockHandoffManager)(nil).ListHandoffs), ctx, filter)
+}
+
+// ListMessages mocks base method.
+func (m *MockHandoffManager) ListMessages(ctx context.Context, phone string, limit int) ([]domain.MessageRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", ctx, phone, limit)
+	ret0, _ := ret[0].([]domain.MessageRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockHandoffManagerMockRecorder) ListMessages(ctx, phone, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockHandoffManager)(nil).ListMessages), ctx, phone, limit)
+}
+
+// Reply mocks base method.
+func (m *MockHandoffManager) Reply(ctx context.Context, phone, agent, text string) (*domain.SendResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reply", ctx, phone, agent, text)
+	ret0, _ := ret[0].(*domain.SendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reply indicates an expected call of Reply.
+func (mr *MockHandoffManagerMockRecorder) Reply(ctx, phone, agent, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reply", reflect.TypeOf((*MockHandoffManager)(nil).Reply), ctx, phone, agent, text)
+}
+
+// MockFAQSearcher is a mock of FAQSearcher interface.
+type MockFAQSearcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockFAQSearcherMockRecorder
+	isgomock struct{}
+}
+
+// MockFAQSearcherMockRecorder is the mock recorder for MockFAQSearcher.
+type MockFAQSearcherMockRecorder struct {
+	mock *MockFAQSearcher
+}
+
+// NewMockFAQSearcher creates a new mock instance.
+func NewMockFAQSearcher(ctrl *gomock.Controller) *MockFAQSearcher {
+	mock := &MockFAQSearcher{ctrl: ctrl}
+	mock.recorder = &MockFAQSearcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFAQSearcher) EXPECT() *MockFAQSearcherMockRecorder {
+	return m.recorder
+}
+
+// FindFAQ mocks base method.
+func (m *MockFAQSearcher) FindFAQ(ctx context.Context, id string) (*domain.FAQEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFAQ", ctx, id)
+	ret0, _ := ret[0].(*domain.FAQEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFAQ indicates an expected call of FindFAQ.
+func (mr *MockFAQSearcherMockRecorder) FindFAQ(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFAQ", reflect.TypeOf((*MockFAQSearcher)(nil).FindFAQ), ctx, id)
+}
+
+// SearchFAQ mocks base method.
+func (m *MockFAQSearcher) SearchFAQ(ctx context.Context, question string) (domain.FAQMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFAQ", ctx, question)
+	ret0, _ := ret[0].(domain.FAQMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchFAQ indicates an expected call of SearchFAQ.
+func (mr *MockFAQSearcherMockRecorder) SearchFAQ(ctx, question any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFAQ", reflect.TypeOf((*MockFAQSearcher)(nil).SearchFAQ), ctx, question)
+}
+
+// MockFAQManager is a mock of FAQManager interface.
+type MockFAQManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockFAQManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockFAQManagerMockRecorder is the mock recorder for MockFAQManager.
+type MockFAQManagerMockRecorder struct {
+	mock *MockFAQManager
+}
+
+// NewMockFAQManager creates a new mock instance.
+func NewMockFAQManager(ctrl *gomock.Controller) *MockFAQManager {
+	mock := &MockFAQManager{ctrl: ctrl}
+	mock.recorder = &MockFAQManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFAQManager) EXPECT() *MockFAQManagerMockRecorder {
+	return m.recorder
+}
+
+// DeleteFAQ mocks base method.
+func (m *MockFAQManager) DeleteFAQ(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFAQ", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFAQ indicates an expected call of DeleteFAQ.
+func (mr *MockFAQManagerMockRecorder) DeleteFAQ(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFAQ", reflect.TypeOf((*MockFAQManager)(nil).DeleteFAQ), ctx, id)
+}
+
+// FindFAQ mocks base method.
+func (m *MockFAQManager) FindFAQ(ctx context.Context, id string) (*domain.FAQEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFAQ", ctx, id)
+	ret0, _ := ret[0].(*domain.FAQEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFAQ indicates an expected call of FindFAQ.
+func (mr *MockFAQManagerMockRecorder) FindFAQ(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFAQ", reflect.TypeOf((*MockFAQManager)(nil).FindFAQ), ctx, id)
+}
+
+// ListFAQ mocks base method.
+func (m *MockFAQManager) ListFAQ(ctx context.Context, category string) ([]domain.FAQEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFAQ", ctx, category)
+	ret0, _ := ret[0].([]domain.FAQEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFAQ indicates an expected call of ListFAQ.
+func (mr *MockFAQManagerMockRecorder) ListFAQ(ctx, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFAQ", reflect.TypeOf((*MockFAQManager)(nil).ListFAQ), ctx, category)
+}
+
+// SaveFAQ mocks base method.
+func (m *MockFAQManager) SaveFAQ(ctx context.Context, entry domain.FAQEntry) (*domain.FAQEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFAQ", ctx, entry)
+	ret0, _ := ret[0].(*domain.FAQEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveFAQ indicates an expected call of SaveFAQ.
+func (mr *MockFAQManagerMockRecorder) SaveFAQ(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFAQ", reflect.TypeOf((*MockFAQManager)(nil).SaveFAQ), ctx, entry)
+}
+
+// SearchFAQ mocks base method.
+func (m *MockFAQManager) SearchFAQ(ctx context.Context, question string) (domain.FAQMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFAQ", ctx, question)
+	ret0, _ := ret[0].(domain.FAQMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchFAQ indicates an expected call of SearchFAQ.
+func (mr *MockFAQManagerMockRecorder) SearchFAQ(ctx, question any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFAQ", reflect.TypeOf((*MockFAQManager)(nil).SearchFAQ), ctx, question)
+}
+
+// MockConversationRepository is a mock of ConversationRepository interface.
+type MockConversationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConversationRepositoryMockRecorder is the mock recorder for MockConversationRepository.
+type MockConversationRepositoryMockRecorder struct {
+	mock *MockConversationRepository
+}
+
+// NewMockConversationRepository creates a new mock instance.
+func NewMockConversationRepository(ctrl *gomock.Controller) *MockConversationRepository {
+	mock := &MockConversationRepository{ctrl: ctrl}
+	mock.recorder = &MockConversationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversationRepository) EXPECT() *MockConversationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByPhone mocks base method.
+func (m *MockConversationRepository) FindByPhone(ctx context.Context, phone string) (*domain.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPhone", ctx, phone)
+	ret0, _ := ret[0].(*domain.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPhone indicates an expected call of FindByPhone.
+func (mr *MockConversationRepositoryMockRecorder) FindByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockConversationRepository)(nil).FindByPhone), ctx, phone)
+}
+
+// Save mocks base method.
+func (m *MockConversationRepository) Save(ctx context.Context, conversation *domain.Conversation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, conversation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockConversationRepositoryMockRecorder) Save(ctx, conversation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockConversationRepository)(nil).Save), ctx, conversation)
+}
+
+// MockContactRepository is a mock of ContactRepository interface.
+type MockContactRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockContactRepositoryMockRecorder is the mock recorder for MockContactRepository.
+type MockContactRepositoryMockRecorder struct {
+	mock *MockContactRepository
+}
+
+// NewMockContactRepository creates a new mock instance.
+func NewMockContactRepository(ctrl *gomock.Controller) *MockContactRepository {
+	mock := &MockContactRepository{ctrl: ctrl}
+	mock.recorder = &MockContactRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactRepository) EXPECT() *MockContactRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByPhone mocks base method.
+func (m *MockContactRepository) FindByPhone(ctx context.Context, phone string) (*domain.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPhone", ctx, phone)
+	ret0, _ := ret[0].(*domain.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPhone indicates an expected call of FindByPhone.
+func (mr *MockContactRepositoryMockRecorder) FindByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockContactRepository)(nil).FindByPhone), ctx, phone)
+}
+
+// Save mocks base method.
+func (m *MockContactRepository) Save(ctx context.Context, contact *domain.Contact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockContactRepositoryMockRecorder) Save(ctx, contact any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockContactRepository)(nil).Save), ctx, contact)
+}
+
+// MockMessageRepository is a mock of MessageRepository interface.
+type MockMessageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageRepositoryMockRecorder is the mock recorder for MockMessageRepository.
+type MockMessageRepositoryMockRecorder struct {
+	mock *MockMessageRepository
+}
+
+// NewMockMessageRepository creates a new mock instance.
+func NewMockMessageRepository(ctrl *gomock.Controller) *MockMessageRepository {
+	mock := &MockMessageRepository{ctrl: ctrl}
+	mock.recorder = &MockMessageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageRepository) EXPECT() *MockMessageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByPhone mocks base method.
+func (m *MockMessageRepository) FindByPhone(ctx context.Context, phone string, limit int) ([]domain.MessageRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPhone", ctx, phone, limit)
+	ret0, _ := ret[0].([]domain.MessageRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPhone indicates an expected call of FindByPhone.
+func (mr *MockMessageRepositoryMockRecorder) FindByPhone(ctx, phone, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockMessageRepository)(nil).FindByPhone), ctx, phone, limit)
+}
+
+// Save mocks base method.
+func (m *MockMessageRepository) Save(ctx context.Context, message domain.MessageRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockMessageRepositoryMockRecorder) Save(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMessageRepository)(nil).Save), ctx, message)
+}
+
+// MockLeadRepository is a mock of LeadRepository interface.
+type MockLeadRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeadRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLeadRepositoryMockRecorder is the mock recorder for MockLeadRepository.
+type MockLeadRepositoryMockRecorder struct {
+	mock *MockLeadRepository
+}
+
+// NewMockLeadRepository creates a new mock instance.
+func NewMockLeadRepository(ctrl *gomock.Controller) *MockLeadRepository {
+	mock := &MockLeadRepository{ctrl: ctrl}
+	mock.recorder = &MockLeadRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeadRepository) EXPECT() *MockLeadRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByPhone mocks base method.
+func (m *MockLeadRepository) FindByPhone(ctx context.Context, phone string) (*domain.Lead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPhone", ctx, phone)
+	ret0, _ := ret[0].(*domain.Lead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPhone indicates an expected call of FindByPhone.
+func (mr *MockLeadRepositoryMockRecorder) FindByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockLeadRepository)(nil).FindByPhone), ctx, phone)
+}
+
+// List mocks base method.
+func (m *MockLeadRepository) List(ctx context.Context, filter domain.LeadFilter) ([]domain.Lead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]domain.Lead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockLeadRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLeadRepository)(nil).List), ctx, filter)
+}
+
+// Save mocks base method.
+func (m *MockLeadRepository) Save(ctx context.Context, lead *domain.Lead) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, lead)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockLeadRepositoryMockRecorder) Save(ctx, lead any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockLeadRepository)(nil).Save), ctx, lead)
+}
+
+// MockHandoffRepository is a mock of HandoffRepository interface.
+type MockHandoffRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandoffRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHandoffRepositoryMockRecorder is the mock recorder for MockHandoffRepository.
+type MockHandoffRepositoryMockRecorder struct {
+	mock *MockHandoffRepository
+}
+
+// NewMockHandoffRepository creates a new mock instance.
+func NewMockHandoffRepository(ctrl *gomock.Controller) *MockHandoffRepository {
+	mock := &MockHandoffRepository{ctrl: ctrl}
+	mock.recorder = &MockHandoffRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandoffRepository) EXPECT() *MockHandoffRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByPhone mocks base method.
+func (m *MockHandoffRepository) FindByPhone(ctx context.Context, phone string) (*domain.Handoff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPhone", ctx, phone)
+	ret0, _ := ret[0].(*domain.Handoff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPhone indicates an expected call of FindByPhone.
+func (mr *MockHandoffRepositoryMockRecorder) FindByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockHandoffRepository)(nil).FindByPhone), ctx, phone)
+}
+
+// List mocks base method.
+func (m *MockHandoffRepository) List(ctx context.Context, filter domain.HandoffFilter) ([]domain.Handoff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]domain.Handoff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHandoffRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHandoffRepository)(nil).List), ctx, filter)
+}
+
+// Save mocks base method.
+func (m *MockHandoffRepository) Save(ctx context.Context, handoff *domain.Handoff) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, handoff)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockHandoffRepositoryMockRecorder) Save(ctx, handoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockHandoffRepository)(nil).Save), ctx, handoff)
+}
+
+// MockFAQRepository is a mock of FAQRepository interface.
+type MockFAQRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFAQRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFAQRepositoryMockRecorder is the mock recorder for MockFAQRepository.
+type MockFAQRepositoryMockRecorder struct {
+	mock *MockFAQRepository
+}
+
+// NewMockFAQRepository creates a new mock instance.
+func NewMockFAQRepository(ctrl *gomock.Controller) *MockFAQRepository {
+	mock := &MockFAQRepository{ctrl: ctrl}
+	mock.recorder = &MockFAQRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFAQRepository) EXPECT() *MockFAQRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockFAQRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFAQRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFAQRepository)(nil).Delete), ctx, id)
+}
+
+// FindByID mocks base method.
+func (m *MockFAQRepository) FindByID(ctx context.Context, id string) (*domain.FAQEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*domain.FAQEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockFAQRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockFAQRepository)(nil).FindByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockFAQRepository) List(ctx context.Context) ([]domain.FAQEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.FAQEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFAQRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFAQRepository)(nil).List), ctx)
+}
+
+// Save mocks base method.
+func (m *MockFAQRepository) Save(ctx context.Context, entry *domain.FAQEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockFAQRepositoryMockRecorder) Save(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockFAQRepository)(nil).Save), ctx, entry)
+}
+
+// MockCRMVerifier is a mock of CRMVerifier interface.
+type MockCRMVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockCRMVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockCRMVerifierMockRecorder is the mock recorder for MockCRMVerifier.
+type MockCRMVerifierMockRecorder struct {
+	mock *MockCRMVerifier
+}
+
+// NewMockCRMVerifier creates a new mock instance.
+func NewMockCRMVerifier(ctrl *gomock.Controller) *MockCRMVerifier {
+	mock := &MockCRMVerifier{ctrl: ctrl}
+	mock.recorder = &MockCRMVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCRMVerifier) EXPECT() *MockCRMVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyCRM mocks base method.
+func (m *MockCRMVerifier) VerifyCRM(ctx context.Context, crm domain.CRM) (domain.CRMVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCRM", ctx, crm)
+	ret0, _ := ret[0].(domain.CRMVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyCRM indicates an expected call of VerifyCRM.
+func (mr *MockCRMVerifierMockRecorder) VerifyCRM(ctx, crm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCRM", reflect.TypeOf((*MockCRMVerifier)(nil).VerifyCRM), ctx, crm)
+}
+
+// MockLocationCatalog is a mock of LocationCatalog interface.
+type MockLocationCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocationCatalogMockRecorder
+	isgomock struct{}
+}
+
+// MockLocationCatalogMockRecorder is the mock recorder for MockLocationCatalog.
+type MockLocationCatalogMockRecorder struct {
+	mock *MockLocationCatalog
+}
+
+// NewMockLocationCatalog creates a new mock instance.
+func NewMockLocationCatalog(ctrl *gomock.Controller) *MockLocationCatalog {
+	mock := &MockLocationCatalog{ctrl: ctrl}
+	mock.recorder = &MockLocationCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocationCatalog) EXPECT() *MockLocationCatalogMockRecorder {
+	return m.recorder
+}
+
+// MatchMunicipality mocks base method.
+func (m *MockLocationCatalog) MatchMunicipality(uf, text string) domain.MunicipalityMatch {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchMunicipality", uf, text)
+	ret0, _ := ret[0].(domain.MunicipalityMatch)
+	return ret0
+}
+
+// MatchMunicipality indicates an expected call of MatchMunicipality.
+func (mr *MockLocationCatalogMockRecorder) MatchMunicipality(uf, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchMunicipality", reflect.TypeOf((*MockLocationCatalog)(nil).MatchMunicipality), uf, text)
+}
+
+// MatchState mocks base method.
+func (m *MockLocationCatalog) MatchState(text string) domain.StateMatch {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchState", text)
+	ret0, _ := ret[0].(domain.StateMatch)
+	return ret0
+}
+
+// MatchState indicates an expected call of MatchState.
+func (mr *MockLocationCatalogMockRecorder) MatchState(text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchState", reflect.TypeOf((*MockLocationCatalog)(nil).MatchState), text)
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(ctx context.Context, event domain.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), ctx, event)
+}
+
+// MockStatusRepository is a mock of StatusRepository interface.
+type MockStatusRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusRepositoryMockRecorder is the mock recorder for MockStatusRepository.
+type MockStatusRepositoryMockRecorder struct {
+	mock *MockStatusRepository
+}
+
+// NewMockStatusRepository creates a new mock instance.
+func NewMockStatusRepository(ctrl *gomock.Controller) *MockStatusRepository {
+	mock := &MockStatusRepository{ctrl: ctrl}
+	mock.recorder = &MockStatusRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusRepository) EXPECT() *MockStatusRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendStatus mocks base method.
+func (m *MockStatusRepository) AppendStatus(ctx context.Context, event domain.StatusEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendStatus", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendStatus indicates an expected call of AppendStatus.
+func (mr *MockStatusRepositoryMockRecorder) AppendStatus(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendStatus", reflect.TypeOf((*MockStatusRepository)(nil).AppendStatus), ctx, event)
+}
+
+// FindStatuses mocks base method.
+func (m *MockStatusRepository) FindStatuses(ctx context.Context, messageID string) ([]domain.StatusEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStatuses", ctx, messageID)
+	ret0, _ := ret[0].([]domain.StatusEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStatuses indicates an expected call of FindStatuses.
+func (mr *MockStatusRepositoryMockRecorder) FindStatuses(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStatuses", reflect.TypeOf((*MockStatusRepository)(nil).FindStatuses), ctx, messageID)
+}
+
+// MockMediaDownloader is a mock of MediaDownloader interface.
+type MockMediaDownloader struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaDownloaderMockRecorder
+	isgomock struct{}
+}
+
+// MockMediaDownloaderMockRecorder is the mock recorder for MockMediaDownloader.
+type MockMediaDownloaderMockRecorder struct {
+	mock *MockMediaDownloader
+}
+
+// NewMockMediaDownloader creates a new mock instance.
+func NewMockMediaDownloader(ctrl *gomock.Controller) *MockMediaDownloader {
+	mock := &MockMediaDownloader{ctrl: ctrl}
+	mock.recorder = &MockMediaDownloaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMediaDownloader) EXPECT() *MockMediaDownloaderMockRecorder {
+	return m.recorder
+}
+
+// DownloadMediaContext mocks base method.
+func (m *MockMediaDownloader) DownloadMediaContext(ctx context.Context, mediaID string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadMediaContext", ctx, mediaID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadMediaContext indicates an expected call of DownloadMediaContext.
+func (mr *MockMediaDownloaderMockRecorder) DownloadMediaContext(ctx, mediaID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadMediaContext", reflect.TypeOf((*MockMediaDownloader)(nil).DownloadMediaContext), ctx, mediaID)
+}
+
+// MockMediaUploader is a mock of MediaUploader interface.
+type MockMediaUploader struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaUploaderMockRecorder
+	isgomock struct{}
+}
+
+// MockMediaUploaderMockRecorder is the mock recorder for MockMediaUploader.
+type MockMediaUploaderMockRecorder struct {
+	mock *MockMediaUploader
+}
+
+// NewMockMediaUploader creates a new mock instance.
+func NewMockMediaUploader(ctrl *gomock.Controller) *MockMediaUploader {
+	mock := &MockMediaUploader{ctrl: ctrl}
+	mock.recorder = &MockMediaUploaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMediaUploader) EXPECT() *MockMediaUploaderMockRecorder {
+	return m.recorder
+}
+
+// UploadMediaContext mocks base method.
+func (m *MockMediaUploader) UploadMediaContext(ctx context.Context, filename, mimeType string, content io.Reader) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadMediaContext", ctx, filename, mimeType, content)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadMediaContext indicates an expected call of UploadMediaContext.
+func (mr *MockMediaUploaderMockRecorder) UploadMediaContext(ctx, filename, mimeType, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadMediaContext", reflect.TypeOf((*MockMediaUploader)(nil).UploadMediaContext), ctx, filename, mimeType, content)
+}
+
+// MockMediaStore is a mock of MediaStore interface.
+type MockMediaStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockMediaStoreMockRecorder is the mock recorder for MockMediaStore.
+type MockMediaStoreMockRecorder struct {
+	mock *MockMediaStore
+}
+
+// NewMockMediaStore creates a new mock instance.
+func NewMockMediaStore(ctrl *gomock.Controller) *MockMediaStore {
+	mock := &MockMediaStore{ctrl: ctrl}
+	mock.recorder = &MockMediaStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMediaStore) EXPECT() *MockMediaStoreMockRecorder {
+	return m.recorder
+}
+
+// Open mocks base method.
+func (m *MockMediaStore) Open(ctx context.Context, location string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, location)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockMediaStoreMockRecorder) Open(ctx, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockMediaStore)(nil).Open), ctx, location)
+}
+
+// Save mocks base method.
+func (m *MockMediaStore) Save(ctx context.Context, name string, content io.Reader) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, name, content)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockMediaStoreMockRecorder) Save(ctx, name, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMediaStore)(nil).Save), ctx, name, content)
+}
+
+// MockMediaIngester is a mock of MediaIngester interface.
+type MockMediaIngester struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaIngesterMockRecorder
+	isgomock struct{}
+}
+
+// MockMediaIngesterMockRecorder is the mock recorder for MockMediaIngester.
+type MockMediaIngesterMockRecorder struct {
+	mock *MockMediaIngester
+}
+
+// NewMockMediaIngester creates a new mock instance.
+func NewMockMediaIngester(ctrl *gomock.Controller) *MockMediaIngester {
+	mock := &MockMediaIngester{ctrl: ctrl}
+	mock.recorder = &MockMediaIngesterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMediaIngester) EXPECT() *MockMediaIngesterMockRecorder {
+	return m.recorder
+}
+
+// Ingest mocks base method.
+func (m *MockMediaIngester) Ingest(ctx context.Context, msg domain.InboundMessage) (*domain.StoredMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ingest", ctx, msg)
+	ret0, _ := ret[0].(*domain.StoredMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ingest indicates an expected call of Ingest.
+func (mr *MockMediaIngesterMockRecorder) Ingest(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ingest", reflect.TypeOf((*MockMediaIngester)(nil).Ingest), ctx, msg)
+}
+
+// MockDedupStore is a mock of DedupStore interface.
+type MockDedupStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDedupStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDedupStoreMockRecorder is the mock recorder for MockDedupStore.
+type MockDedupStoreMockRecorder struct {
+	mock *MockDedupStore
+}
+
+// NewMockDedupStore creates a new mock instance.
+func NewMockDedupStore(ctrl *gomock.Controller) *MockDedupStore {
+	mock := &MockDedupStore{ctrl: ctrl}
+	mock.recorder = &MockDedupStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDedupStore) EXPECT() *MockDedupStoreMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockDedupStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, key, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockDedupStoreMockRecorder) Claim(ctx, key, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockDedupStore)(nil).Claim), ctx, key, ttl)
+}
+
+// Release mocks base method.
+func (m *MockDedupStore) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockDedupStoreMockRecorder) Release(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockDedupStore)(nil).Release), ctx, key)
+}
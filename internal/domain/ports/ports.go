@@ -0,0 +1,212 @@
+package ports
+
+//go:generate mockgen -source=ports.go -destination=mocks/mocks.go -package=mocks
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+)
+
+// MessageProcessor is the primary port used by inbound adapters to hand over received messages
+type MessageProcessor interface {
+	ProcessMessage(ctx context.Context, msg domain.InboundMessage) error
+}
+
+// StatusProcessor is the primary port used by inbound adapters to hand over delivery statuses
+type StatusProcessor interface {
+	ProcessStatus(ctx context.Context, event domain.StatusEvent) error
+}
+
+// DeliveryTracker is the primary port used by the team to follow the delivery of outbound messages
+type DeliveryTracker interface {
+	// Timeline returns the delivery timeline of an outbound message or domain.ErrNotFound
+	Timeline(ctx context.Context, messageID string) (*domain.DeliveryTimeline, error)
+}
+
+// MessageSender is the secondary port used to send messages to WhatsApp users.
+// Sends are aborted when the context is done.
+type MessageSender interface {
+	SendTextMessageContext(ctx context.Context, to, message string) (*domain.SendResult, error)
+	SendButtonMessageContext(ctx context.Context, to string, message domain.ButtonMessage) (*domain.SendResult, error)
+	SendListMessageContext(ctx context.Context, to string, message domain.ListMessage) (*domain.SendResult, error)
+	SendTemplateMessageContext(ctx context.Context, to string, template domain.TemplateMessage) (*domain.SendResult, error)
+	SendMediaMessageContext(ctx context.Context, to string, message domain.MediaMessage) (*domain.SendResult, error)
+}
+
+// LeadCapturer is the primary port used by the conversation flow to keep the lead of a prospect
+// up to date with the answers of the conversation
+type LeadCapturer interface {
+	CaptureLead(ctx context.Context, conversation *domain.Conversation, name string) error
+}
+
+// LeadManager is the primary port used by the sales team to follow the leads
+type LeadManager interface {
+	// ListLeads returns the leads selected by the filter, the most recently updated first
+	ListLeads(ctx context.Context, filter domain.LeadFilter) ([]domain.Lead, error)
+	// FindLead returns the lead of the given phone number or domain.ErrNotFound
+	FindLead(ctx context.Context, phone string) (*domain.Lead, error)
+	// MoveLead moves the lead to the given funnel stage
+	MoveLead(ctx context.Context, phone string, stage domain.LeadStage) (*domain.Lead, error)
+}
+
+// HandoffRequester is the primary port used by the conversation flow to hand conversations off to the agents
+type HandoffRequester interface {
+	// RequestHandoff opens the handoff of a conversation handed off to the agents,
+	// or records a new message of the user when it is already open
+	RequestHandoff(ctx context.Context, conversation *domain.Conversation, name string) error
+}
+
+// HandoffManager is the primary port used by the agents to answer the users handed off by the bot
+type HandoffManager interface {
+	// ListHandoffs returns the handoffs selected by the filter, the longest waiting first
+	ListHandoffs(ctx context.Context, filter domain.HandoffFilter) ([]domain.Handoff, error)
+	// FindHandoff returns the handoff of the given phone number or domain.ErrNotFound
+	FindHandoff(ctx context.Context, phone string) (*domain.Handoff, error)
+	// ListMessages returns the last limit messages of the user of a handoff, or all of them when limit is not positive
+	ListMessages(ctx context.Context, phone string, limit int) ([]domain.MessageRecord, error)
+	// Reply sends a text message of the agent to the user
+	Reply(ctx context.Context, phone, agent, text string) (*domain.SendResult, error)
+	// Assign gives the handoff to the agent, transferring it from the agent who had it
+	Assign(ctx context.Context, phone, agent string) (*domain.Handoff, error)
+	// Close gives the conversation back to the bot
+	Close(ctx context.Context, phone string) (*domain.Handoff, error)
+}
+
+// FAQSearcher is the primary port used by the conversation flow to answer the questions of the users
+type FAQSearcher interface {
+	// SearchFAQ looks the question up in the knowledge base, tolerating accents, inflections and synonyms
+	SearchFAQ(ctx context.Context, question string) (domain.FAQMatch, error)
+	// FindFAQ returns the entry with the given ID or domain.ErrNotFound
+	FindFAQ(ctx context.Context, id string) (*domain.FAQEntry, error)
+}
+
+// FAQManager is the primary port used by the team to edit the knowledge base
+type FAQManager interface {
+	// ListFAQ returns the entries of the category, or all of them when category is empty, ordered by ID
+	ListFAQ(ctx context.Context, category string) ([]domain.FAQEntry, error)
+	// FindFAQ returns the entry with the given ID or domain.ErrNotFound
+	FindFAQ(ctx context.Context, id string) (*domain.FAQEntry, error)
+	// SaveFAQ creates the entry or replaces the entry with the same ID
+	SaveFAQ(ctx context.Context, entry domain.FAQEntry) (*domain.FAQEntry, error)
+	// DeleteFAQ removes the entry with the given ID or returns domain.ErrNotFound
+	DeleteFAQ(ctx context.Context, id string) error
+	// SearchFAQ looks the question up the way the conversation flow does
+	SearchFAQ(ctx context.Context, question string) (domain.FAQMatch, error)
+}
+
+// ConversationRepository is the secondary port used to persist conversations
+type ConversationRepository interface {
+	// FindByPhone returns the conversation of the given phone number or domain.ErrNotFound
+	FindByPhone(ctx context.Context, phone string) (*domain.Conversation, error)
+	Save(ctx context.Context, conversation *domain.Conversation) error
+}
+
+// ContactRepository is the secondary port used to persist the WhatsApp users who wrote to the business
+type ContactRepository interface {
+	// FindByPhone returns the contact of the given phone number or domain.ErrNotFound
+	FindByPhone(ctx context.Context, phone string) (*domain.Contact, error)
+	Save(ctx context.Context, contact *domain.Contact) error
+}
+
+// MessageRepository is the secondary port used to persist the message history of the contacts
+type MessageRepository interface {
+	// Save stores the message, replacing any message previously stored with the same ID
+	Save(ctx context.Context, message domain.MessageRecord) error
+	// FindByPhone returns the last limit messages of the contact ordered by timestamp and ID,
+	// or all of them when limit is not positive
+	FindByPhone(ctx context.Context, phone string, limit int) ([]domain.MessageRecord, error)
+}
+
+// LeadRepository is the secondary port used to persist leads. The events of the leads are not persisted.
+type LeadRepository interface {
+	// FindByPhone returns the lead of the given phone number or domain.ErrNotFound
+	FindByPhone(ctx context.Context, phone string) (*domain.Lead, error)
+	Save(ctx context.Context, lead *domain.Lead) error
+	// List returns the leads selected by the filter ordered by update time, the most recent first
+	List(ctx context.Context, filter domain.LeadFilter) ([]domain.Lead, error)
+}
+
+// HandoffRepository is the secondary port used to persist handoffs. The events of the handoffs are not persisted.
+type HandoffRepository interface {
+	// FindByPhone returns the handoff of the given phone number or domain.ErrNotFound
+	FindByPhone(ctx context.Context, phone string) (*domain.Handoff, error)
+	// Save stores the handoff and increments its version. It returns domain.ErrConflict
+	// when the stored handoff has another version, as it was saved since it was loaded.
+	Save(ctx context.Context, handoff *domain.Handoff) error
+	// List returns the handoffs selected by the filter ordered by opening time, the oldest first
+	List(ctx context.Context, filter domain.HandoffFilter) ([]domain.Handoff, error)
+}
+
+// FAQRepository is the secondary port used to persist the entries of the knowledge base
+type FAQRepository interface {
+	// FindByID returns the entry with the given ID or domain.ErrNotFound
+	FindByID(ctx context.Context, id string) (*domain.FAQEntry, error)
+	Save(ctx context.Context, entry *domain.FAQEntry) error
+	// Delete removes the entry with the given ID or returns domain.ErrNotFound
+	Delete(ctx context.Context, id string) error
+	// List returns every entry ordered by ID
+	List(ctx context.Context) ([]domain.FAQEntry, error)
+}
+
+// CRMVerifier is the secondary port used to confirm medical registrations with the federal council (CFM)
+type CRMVerifier interface {
+	// VerifyCRM checks the registration, returning domain.CRMUnverified when it can not be checked
+	VerifyCRM(ctx context.Context, crm domain.CRM) (domain.CRMVerification, error)
+}
+
+// LocationCatalog is the secondary port used to recognize the states and municipalities typed by users.
+// Both lookups tolerate accents, case, common abbreviations and typos.
+type LocationCatalog interface {
+	MatchState(text string) domain.StateMatch
+	// MatchMunicipality looks the text up among the municipalities of the state, or of the whole
+	// country when uf is empty. A state typed along with the name, like in "Maringá - PR", prevails.
+	MatchMunicipality(uf, text string) domain.MunicipalityMatch
+}
+
+// EventPublisher is the secondary port used to announce domain events
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+// StatusRepository is the secondary port used to persist the delivery statuses of outbound messages
+type StatusRepository interface {
+	// AppendStatus adds the event to the timeline of its message, ignoring repeated statuses
+	AppendStatus(ctx context.Context, event domain.StatusEvent) error
+	// FindStatuses returns the events of the message ordered by timestamp
+	FindStatuses(ctx context.Context, messageID string) ([]domain.StatusEvent, error)
+}
+
+// MediaDownloader is the secondary port used to fetch the content of received media
+type MediaDownloader interface {
+	DownloadMediaContext(ctx context.Context, mediaID string) ([]byte, error)
+}
+
+// MediaUploader is the secondary port used to upload files to be sent as media messages
+type MediaUploader interface {
+	// UploadMediaContext uploads the content and returns the media ID to reference in messages
+	UploadMediaContext(ctx context.Context, filename, mimeType string, content io.Reader) (string, error)
+}
+
+// MediaStore is the secondary port used to persist media files
+type MediaStore interface {
+	// Save stores the content under the given name and returns its location
+	Save(ctx context.Context, name string, content io.Reader) (string, error)
+	// Open returns the content stored at the given location
+	Open(ctx context.Context, location string) (io.ReadCloser, error)
+}
+
+// MediaIngester downloads, verifies and stores the media attached to inbound messages
+type MediaIngester interface {
+	Ingest(ctx context.Context, msg domain.InboundMessage) (*domain.StoredMedia, error)
+}
+
+// DedupStore is the secondary port used to remember the webhook events already handled
+type DedupStore interface {
+	// Claim records the key for the given time and reports whether it was not recorded yet
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Release forgets the key so that the event can be handled again
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,841 @@
+// Package porttest provides the contract test suites shared by the implementations of the
+// secondary ports, so that every adapter behaves the same way for the application layer.
+//
+// Each suite receives a constructor called once per subtest, which must return an empty
+// repository. Times are UTC with millisecond precision, the precision kept by MongoDB.
+package porttest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/2rprbm/conta-med-backend/internal/domain"
+	"github.com/2rprbm/conta-med-backend/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPhone = "554499887766"
+
+// baseTime is the reference time of the suites
+var baseTime = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// TestConversationRepository runs the ports.ConversationRepository contract
+func TestConversationRepository(t *testing.T, newRepository func(t *testing.T) ports.ConversationRepository) {
+	t.Run("should return not found for unknown phones", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+
+		// act
+		conversation, err := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Nil(t, conversation)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should save and find every field of a conversation", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		conversation := domain.NewConversation(testPhone, baseTime)
+		conversation.MenuOption = domain.OptionOpenCompany
+		conversation.HasCRM = true
+		conversation.CRM = domain.CRM{Number: "123456", State: "PR", Suffix: "P"}
+		conversation.CRMVerified = true
+		conversation.Specialty = "Pediatria"
+		conversation.State = "PR"
+		conversation.Municipality = "Maringá"
+		conversation.MunicipalityCode = "4115200"
+		conversation.Locate()
+		conversation.Revenue = 30000.5
+		conversation.HandOff(domain.HandoffReasonUnhandled, baseTime.Add(time.Minute))
+		conversation.Attempts = 1
+
+		// act
+		err := repo.Save(context.Background(), conversation)
+		found, findErr := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, conversation, found)
+	})
+
+	t.Run("should replace the previous version", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		conversation := domain.NewConversation(testPhone, baseTime)
+		conversation.State = "PR"
+		repo.Save(context.Background(), conversation)
+
+		// act
+		conversation.Restart(baseTime.Add(time.Hour))
+		err := repo.Save(context.Background(), conversation)
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, conversation, found)
+	})
+
+	t.Run("should not share state with the stored conversations", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		conversation := domain.NewConversation(testPhone, baseTime)
+		repo.Save(context.Background(), conversation)
+
+		// act
+		conversation.Step = domain.StepCompleted
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+		found.Step = domain.StepState
+		again, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Equal(t, domain.StepStart, again.Step)
+	})
+
+	t.Run("should keep the conversations of each phone apart", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), domain.NewConversation(testPhone, baseTime))
+
+		// act
+		_, err := repo.FindByPhone(context.Background(), "554400000000")
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+// TestContactRepository runs the ports.ContactRepository contract
+func TestContactRepository(t *testing.T, newRepository func(t *testing.T) ports.ContactRepository) {
+	t.Run("should return not found for unknown phones", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+
+		// act
+		contact, err := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Nil(t, contact)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should save and find every field of a contact", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		contact := domain.NewContact(testPhone, baseTime)
+		contact.Seen("Dra. Ana", baseTime.Add(time.Hour))
+
+		// act
+		err := repo.Save(context.Background(), contact)
+		found, findErr := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, contact, found)
+	})
+
+	t.Run("should replace the previous version", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		contact := domain.NewContact(testPhone, baseTime)
+		repo.Save(context.Background(), contact)
+
+		// act
+		contact.Seen("Ana", baseTime.Add(time.Minute))
+		err := repo.Save(context.Background(), contact)
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, contact, found)
+	})
+
+	t.Run("should not share state with the stored contacts", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		contact := domain.NewContact(testPhone, baseTime)
+		repo.Save(context.Background(), contact)
+
+		// act
+		contact.Name = "Ana"
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Empty(t, found.Name)
+	})
+}
+
+// TestMessageRepository runs the ports.MessageRepository contract
+func TestMessageRepository(t *testing.T, newRepository func(t *testing.T) ports.MessageRepository) {
+	// message creates the record of an inbound text message sent minutes after baseTime
+	message := func(id, phone string, minutes int) domain.MessageRecord {
+		return domain.MessageRecord{
+			ID:        id,
+			Phone:     phone,
+			Direction: domain.DirectionInbound,
+			Type:      domain.MessageTypeText,
+			Text:      "message " + id,
+			Timestamp: baseTime.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+	// ids returns the IDs of the messages
+	ids := func(messages []domain.MessageRecord) []string {
+		result := make([]string, len(messages))
+		for i, m := range messages {
+			result[i] = m.ID
+		}
+		return result
+	}
+
+	t.Run("should return no messages for unknown phones", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+
+		// act
+		messages, err := repo.FindByPhone(context.Background(), testPhone, 10)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("should save and find every field of a message", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		record := domain.MessageRecord{
+			ID:        "wamid.out.1",
+			Phone:     testPhone,
+			Direction: domain.DirectionOutbound,
+			Type:      domain.MessageTypeInteractive,
+			Text:      "Escolha uma opção",
+			Timestamp: baseTime,
+		}
+
+		// act
+		err := repo.Save(context.Background(), record)
+		messages, findErr := repo.FindByPhone(context.Background(), testPhone, 10)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, []domain.MessageRecord{record}, messages)
+	})
+
+	t.Run("should return the last messages ordered by timestamp", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		for _, minutes := range []int{3, 0, 4, 1, 2} {
+			repo.Save(context.Background(), message(fmt.Sprintf("wamid.%d", minutes), testPhone, minutes))
+		}
+
+		// act
+		last, err := repo.FindByPhone(context.Background(), testPhone, 3)
+		all, allErr := repo.FindByPhone(context.Background(), testPhone, 0)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, allErr)
+		assert.Equal(t, []string{"wamid.2", "wamid.3", "wamid.4"}, ids(last))
+		assert.Equal(t, []string{"wamid.0", "wamid.1", "wamid.2", "wamid.3", "wamid.4"}, ids(all))
+	})
+
+	t.Run("should order messages with the same timestamp by ID", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), message("wamid.b", testPhone, 0))
+		repo.Save(context.Background(), message("wamid.c", testPhone, 0))
+		repo.Save(context.Background(), message("wamid.a", testPhone, 0))
+
+		// act
+		messages, _ := repo.FindByPhone(context.Background(), testPhone, 2)
+
+		// assert
+		assert.Equal(t, []string{"wamid.b", "wamid.c"}, ids(messages))
+	})
+
+	t.Run("should keep the messages of each phone apart", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), message("wamid.1", testPhone, 0))
+		repo.Save(context.Background(), message("wamid.2", "554400000000", 1))
+
+		// act
+		messages, _ := repo.FindByPhone(context.Background(), testPhone, 10)
+
+		// assert
+		assert.Equal(t, []string{"wamid.1"}, ids(messages))
+	})
+
+	t.Run("should replace a message saved again with the same ID", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), message("wamid.1", testPhone, 0))
+		updated := message("wamid.1", testPhone, 1)
+		updated.Text = "edited"
+
+		// act
+		err := repo.Save(context.Background(), updated)
+		messages, _ := repo.FindByPhone(context.Background(), testPhone, 10)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.MessageRecord{updated}, messages)
+	})
+}
+
+// TestLeadRepository runs the ports.LeadRepository contract
+func TestLeadRepository(t *testing.T, newRepository func(t *testing.T) ports.LeadRepository) {
+	// lead creates a lead of the given stage updated minutes after baseTime
+	lead := func(phone string, stage domain.LeadStage, minutes int) *domain.Lead {
+		l := domain.NewLead(phone, domain.LeadSourceWhatsApp, baseTime)
+		l.Stage = stage
+		l.UpdatedAt = baseTime.Add(time.Duration(minutes) * time.Minute)
+		return l
+	}
+	// phones returns the phone numbers of the leads
+	phones := func(leads []domain.Lead) []string {
+		result := make([]string, len(leads))
+		for i, l := range leads {
+			result[i] = l.Phone
+		}
+		return result
+	}
+
+	t.Run("should return not found for unknown phones", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+
+		// act
+		found, err := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should save and find every field of a lead", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		l := domain.NewLead(testPhone, domain.LeadSourceWhatsApp, baseTime)
+		l.Name = "Dra. Ana"
+		l.HasCRM = true
+		l.CRMNumber = "123456"
+		l.CRMState = "PR"
+		l.CRMSuffix = "P"
+		l.CRMVerified = true
+		l.Specialty = "Pediatria"
+		l.State = "PR"
+		l.Municipality = "Maringá"
+		l.MunicipalityCode = "4115200"
+		l.Qualify(baseTime.Add(time.Minute))
+		l.PullEvents()
+
+		// act
+		err := repo.Save(context.Background(), l)
+		found, findErr := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, l, found)
+	})
+
+	t.Run("should not persist the events of the lead", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		l := lead(testPhone, domain.LeadStageNew, 0)
+		l.State = "PR"
+		l.Municipality = "Maringá"
+		l.Qualify(baseTime)
+
+		// act
+		repo.Save(context.Background(), l)
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Empty(t, found.PullEvents())
+		assert.Len(t, l.PullEvents(), 1)
+	})
+
+	t.Run("should replace the previous version", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		l := lead(testPhone, domain.LeadStageQualified, 0)
+		repo.Save(context.Background(), l)
+
+		// act
+		l.MoveTo(domain.LeadStageContacted, baseTime.Add(time.Hour))
+		err := repo.Save(context.Background(), l)
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.LeadStageContacted, found.Stage)
+	})
+
+	t.Run("should list the most recently updated leads first", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), lead("554400000001", domain.LeadStageNew, 1))
+		repo.Save(context.Background(), lead("554400000002", domain.LeadStageQualified, 3))
+		repo.Save(context.Background(), lead("554400000003", domain.LeadStageQualified, 2))
+		repo.Save(context.Background(), lead("554400000004", domain.LeadStageQualified, 3))
+
+		// act
+		all, err := repo.List(context.Background(), domain.LeadFilter{})
+		limited, _ := repo.List(context.Background(), domain.LeadFilter{Limit: 2})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"554400000002", "554400000004", "554400000003", "554400000001"}, phones(all))
+		assert.Equal(t, []string{"554400000002", "554400000004"}, phones(limited))
+	})
+
+	t.Run("should filter the leads by stage", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), lead("554400000001", domain.LeadStageNew, 1))
+		repo.Save(context.Background(), lead("554400000002", domain.LeadStageQualified, 2))
+
+		// act
+		qualified, err := repo.List(context.Background(), domain.LeadFilter{Stage: domain.LeadStageQualified})
+		lost, _ := repo.List(context.Background(), domain.LeadFilter{Stage: domain.LeadStageLost})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"554400000002"}, phones(qualified))
+		assert.Empty(t, lost)
+	})
+}
+
+// TestHandoffRepository runs the ports.HandoffRepository contract
+func TestHandoffRepository(t *testing.T, newRepository func(t *testing.T) ports.HandoffRepository) {
+	// handoff creates a handoff opened minutes after baseTime
+	handoff := func(phone string, minutes int) *domain.Handoff {
+		h := domain.NewHandoff(phone, "", domain.HandoffReasonOther, baseTime.Add(time.Duration(minutes)*time.Minute))
+		h.PullEvents()
+		return h
+	}
+	// phones returns the phone numbers of the handoffs
+	phones := func(handoffs []domain.Handoff) []string {
+		result := make([]string, len(handoffs))
+		for i, h := range handoffs {
+			result[i] = h.Phone
+		}
+		return result
+	}
+
+	t.Run("should return not found for unknown phones", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+
+		// act
+		found, err := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should save and find every field of a handoff", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		h := handoff(testPhone, 0)
+		h.Receive("Dra. Ana", baseTime.Add(time.Minute))
+		h.Reply("maria", baseTime.Add(2*time.Minute))
+		h.Close(baseTime.Add(3 * time.Minute))
+
+		// act
+		err := repo.Save(context.Background(), h)
+		found, findErr := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, h, found)
+	})
+
+	t.Run("should not persist the events of the handoff", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		h := domain.NewHandoff(testPhone, "", domain.HandoffReasonOther, baseTime)
+
+		// act
+		repo.Save(context.Background(), h)
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.Empty(t, found.PullEvents())
+		assert.Len(t, h.PullEvents(), 1)
+	})
+
+	t.Run("should replace the previous version", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		h := handoff(testPhone, 0)
+		repo.Save(context.Background(), h)
+
+		// act
+		h.Assign("maria", baseTime.Add(time.Hour))
+		err := repo.Save(context.Background(), h)
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, domain.HandoffAssigned, found.Status)
+		assert.Equal(t, "maria", found.Agent)
+	})
+
+	t.Run("should refuse to save a stale copy", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), handoff(testPhone, 0))
+		assigned, _ := repo.FindByPhone(context.Background(), testPhone)
+		stale, _ := repo.FindByPhone(context.Background(), testPhone)
+		assigned.Assign("maria", baseTime.Add(time.Hour))
+		assert.NoError(t, repo.Save(context.Background(), assigned))
+
+		// act
+		stale.Receive("", baseTime.Add(2*time.Hour))
+		err := repo.Save(context.Background(), stale)
+		found, _ := repo.FindByPhone(context.Background(), testPhone)
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Equal(t, "maria", found.Agent)
+		assert.Equal(t, 2, found.Version)
+		assert.Equal(t, baseTime, found.LastMessageAt)
+	})
+
+	t.Run("should refuse to save a new handoff over a stored one", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), handoff(testPhone, 0))
+
+		// act
+		err := repo.Save(context.Background(), handoff(testPhone, 5))
+
+		// assert
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("should list the open handoffs waiting the longest first", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), handoff("554400000001", 3))
+		repo.Save(context.Background(), handoff("554400000002", 1))
+		repo.Save(context.Background(), handoff("554400000003", 2))
+		repo.Save(context.Background(), handoff("554400000004", 1))
+		closed := handoff("554400000005", 0)
+		closed.Close(baseTime.Add(time.Hour))
+		repo.Save(context.Background(), closed)
+
+		// act
+		open, err := repo.List(context.Background(), domain.HandoffFilter{})
+		limited, _ := repo.List(context.Background(), domain.HandoffFilter{Limit: 2})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"554400000002", "554400000004", "554400000003", "554400000001"}, phones(open))
+		assert.Equal(t, []string{"554400000002", "554400000004"}, phones(limited))
+	})
+
+	t.Run("should filter the handoffs by status and agent", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), handoff("554400000001", 1))
+		assigned := handoff("554400000002", 2)
+		assigned.Assign("maria", baseTime.Add(time.Hour))
+		repo.Save(context.Background(), assigned)
+		other := handoff("554400000003", 3)
+		other.Assign("joao", baseTime.Add(time.Hour))
+		repo.Save(context.Background(), other)
+		closed := handoff("554400000004", 4)
+		closed.Assign("maria", baseTime.Add(time.Hour))
+		closed.Close(baseTime.Add(2 * time.Hour))
+		repo.Save(context.Background(), closed)
+
+		// act
+		waiting, err := repo.List(context.Background(), domain.HandoffFilter{Status: domain.HandoffWaiting})
+		byMaria, _ := repo.List(context.Background(), domain.HandoffFilter{Agent: "maria"})
+		closedByMaria, _ := repo.List(context.Background(), domain.HandoffFilter{Status: domain.HandoffClosed, Agent: "maria"})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"554400000001"}, phones(waiting))
+		assert.Equal(t, []string{"554400000002"}, phones(byMaria))
+		assert.Equal(t, []string{"554400000004"}, phones(closedByMaria))
+	})
+}
+
+// TestFAQRepository runs the ports.FAQRepository contract
+func TestFAQRepository(t *testing.T, newRepository func(t *testing.T) ports.FAQRepository) {
+	// entry creates an entry of the category updated at baseTime
+	entry := func(id, category string) *domain.FAQEntry {
+		return &domain.FAQEntry{
+			ID:        id,
+			Category:  category,
+			Question:  "Pergunta " + id,
+			Answer:    "Resposta " + id,
+			UpdatedAt: baseTime,
+		}
+	}
+	// ids returns the IDs of the entries
+	ids := func(entries []domain.FAQEntry) []string {
+		result := make([]string, len(entries))
+		for i, e := range entries {
+			result[i] = e.ID
+		}
+		return result
+	}
+
+	t.Run("should return not found for unknown entries", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+
+		// act
+		found, err := repo.FindByID(context.Background(), "fator-r")
+		deleteErr := repo.Delete(context.Background(), "fator-r")
+
+		// assert
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.ErrorIs(t, deleteErr, domain.ErrNotFound)
+	})
+
+	t.Run("should save and find every field of an entry", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		e := entry("fator-r", "simples-nacional")
+		e.Keywords = []string{"folha", "anexo"}
+		e.SeedVersion = "5f2b9c1d"
+
+		// act
+		err := repo.Save(context.Background(), e)
+		found, findErr := repo.FindByID(context.Background(), "fator-r")
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, e, found)
+	})
+
+	t.Run("should replace the previous version", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		e := entry("fator-r", "simples-nacional")
+		e.Keywords = []string{"folha"}
+		repo.Save(context.Background(), e)
+
+		// act
+		e.Answer = "Nova resposta"
+		e.Keywords = nil
+		err := repo.Save(context.Background(), e)
+		found, _ := repo.FindByID(context.Background(), "fator-r")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Nova resposta", found.Answer)
+		assert.Empty(t, found.Keywords)
+	})
+
+	t.Run("should not share keywords with the stored entries", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		e := entry("fator-r", "simples-nacional")
+		e.Keywords = []string{"folha"}
+		repo.Save(context.Background(), e)
+
+		// act
+		e.Keywords[0] = "changed"
+		found, _ := repo.FindByID(context.Background(), "fator-r")
+		found.Keywords[0] = "changed"
+		again, _ := repo.FindByID(context.Background(), "fator-r")
+
+		// assert
+		assert.Equal(t, []string{"folha"}, again.Keywords)
+	})
+
+	t.Run("should list every entry ordered by ID", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), entry("pro-labore", "pro-labore"))
+		repo.Save(context.Background(), entry("carne-leao", "irpf"))
+		repo.Save(context.Background(), entry("fator-r", "simples-nacional"))
+
+		// act
+		entries, err := repo.List(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"carne-leao", "fator-r", "pro-labore"}, ids(entries))
+	})
+
+	t.Run("should delete an entry", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.Save(context.Background(), entry("fator-r", "simples-nacional"))
+		repo.Save(context.Background(), entry("pro-labore", "pro-labore"))
+
+		// act
+		err := repo.Delete(context.Background(), "fator-r")
+		_, findErr := repo.FindByID(context.Background(), "fator-r")
+		entries, _ := repo.List(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.ErrorIs(t, findErr, domain.ErrNotFound)
+		assert.Equal(t, []string{"pro-labore"}, ids(entries))
+	})
+}
+
+// TestStatusRepository runs the ports.StatusRepository contract
+func TestStatusRepository(t *testing.T, newRepository func(t *testing.T) ports.StatusRepository) {
+	// event creates a status event of the message notified seconds after baseTime
+	event := func(messageID string, status domain.DeliveryStatus, seconds int) domain.StatusEvent {
+		return domain.StatusEvent{
+			MessageID:   messageID,
+			RecipientID: testPhone,
+			Status:      status,
+			Timestamp:   baseTime.Add(time.Duration(seconds) * time.Second),
+		}
+	}
+
+	t.Run("should return no statuses for unknown messages", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+
+		// act
+		events, err := repo.FindStatuses(context.Background(), "wamid.unknown")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should save and find every field of a status", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		failed := domain.StatusEvent{
+			MessageID:           "wamid.1",
+			RecipientID:         testPhone,
+			Status:              domain.StatusFailed,
+			Timestamp:           baseTime,
+			ConversationID:      "conversation-1",
+			ConversationOrigin:  "service",
+			ConversationExpires: baseTime.Add(24 * time.Hour),
+			Billable:            true,
+			PricingModel:        "CBP",
+			PricingCategory:     "service",
+			Errors:              []domain.StatusError{{Code: 131047, Title: "Re-engagement message", Message: "Message failed", Details: "More than 24 hours"}},
+		}
+
+		// act
+		err := repo.AppendStatus(context.Background(), failed)
+		events, findErr := repo.FindStatuses(context.Background(), "wamid.1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, findErr)
+		assert.Equal(t, []domain.StatusEvent{failed}, events)
+	})
+
+	t.Run("should return the statuses of the message ordered by timestamp", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.AppendStatus(context.Background(), event("wamid.1", domain.StatusRead, 2))
+		repo.AppendStatus(context.Background(), event("wamid.1", domain.StatusSent, 0))
+		repo.AppendStatus(context.Background(), event("wamid.2", domain.StatusSent, 0))
+		repo.AppendStatus(context.Background(), event("wamid.1", domain.StatusDelivered, 1))
+
+		// act
+		events, err := repo.FindStatuses(context.Background(), "wamid.1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.StatusEvent{
+			event("wamid.1", domain.StatusSent, 0),
+			event("wamid.1", domain.StatusDelivered, 1),
+			event("wamid.1", domain.StatusRead, 2),
+		}, events)
+	})
+
+	t.Run("should keep the first notification of a status", func(t *testing.T) {
+		// arrange
+		repo := newRepository(t)
+		repo.AppendStatus(context.Background(), event("wamid.1", domain.StatusSent, 0))
+
+		// act
+		err := repo.AppendStatus(context.Background(), event("wamid.1", domain.StatusSent, 5))
+		events, _ := repo.FindStatuses(context.Background(), "wamid.1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.StatusEvent{event("wamid.1", domain.StatusSent, 0)}, events)
+	})
+}
+
+// TestDedupStore runs the contract tests of ports.DedupStore against the store returned by newStore
+func TestDedupStore(t *testing.T, newStore func(t *testing.T) ports.DedupStore) {
+	t.Run("should claim a key only once", func(t *testing.T) {
+		// arrange
+		store := newStore(t)
+
+		// act
+		first, err1 := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		second, err2 := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+		other, err3 := store.Claim(context.Background(), "message:wamid.2", time.Hour)
+
+		// assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.NoError(t, err3)
+		assert.True(t, first)
+		assert.False(t, second)
+		assert.True(t, other)
+	})
+
+	t.Run("should claim the key again after it expires", func(t *testing.T) {
+		// arrange
+		store := newStore(t)
+		store.Claim(context.Background(), "message:wamid.1", time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+
+		// act
+		claimed, err := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("should claim the key again after it is released", func(t *testing.T) {
+		// arrange
+		store := newStore(t)
+		store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// act
+		err := store.Release(context.Background(), "message:wamid.1")
+		claimed, claimErr := store.Claim(context.Background(), "message:wamid.1", time.Hour)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, claimErr)
+		assert.True(t, claimed)
+	})
+
+	t.Run("should release unknown keys", func(t *testing.T) {
+		// arrange
+		store := newStore(t)
+
+		// act
+		err := store.Release(context.Background(), "message:wamid.unknown")
+
+		// assert
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// DeliveryStatus is the delivery state of an outbound message reported by WhatsApp
+type DeliveryStatus string
+
+const (
+	// StatusSent means the message was sent by WhatsApp
+	StatusSent DeliveryStatus = "sent"
+	// StatusDelivered means the message reached the device of the recipient
+	StatusDelivered DeliveryStatus = "delivered"
+	// StatusRead means the recipient opened the message
+	StatusRead DeliveryStatus = "read"
+	// StatusFailed means the message could not be delivered
+	StatusFailed DeliveryStatus = "failed"
+)
+
+// statusRank orders the statuses so that late notifications do not move a message backwards
+var statusRank = map[DeliveryStatus]int{
+	StatusSent:      1,
+	StatusDelivered: 2,
+	StatusRead:      3,
+	StatusFailed:    4,
+}
+
+// StatusEvent is a single status notification of an outbound message
+type StatusEvent struct {
+	MessageID           string
+	RecipientID         string
+	Status              DeliveryStatus
+	Timestamp           time.Time
+	ConversationID      string
+	ConversationOrigin  string
+	ConversationExpires time.Time
+	Billable            bool
+	PricingModel        string
+	PricingCategory     string
+	Errors              []StatusError
+}
+
+// StatusError describes why a message failed
+type StatusError struct {
+	Code    int
+	Title   string
+	Message string
+	Details string
+}
+
+// DeliveryTimeline is the ordered list of status events of an outbound message
+type DeliveryTimeline struct {
+	MessageID string
+	Events    []StatusEvent
+}
+
+// Current returns the most advanced status reached by the message
+func (t DeliveryTimeline) Current() DeliveryStatus {
+	var current DeliveryStatus
+	for _, event := range t.Events {
+		if statusRank[event.Status] > statusRank[current] {
+			current = event.Status
+		}
+	}
+	return current
+}
+
+// At returns when the message reached the given status, if it did
+func (t DeliveryTimeline) At(status DeliveryStatus) (time.Time, bool) {
+	for _, event := range t.Events {
+		if event.Status == status {
+			return event.Timestamp, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryTimeline(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("should return the most advanced status regardless of arrival order", func(t *testing.T) {
+		// arrange
+		timeline := DeliveryTimeline{
+			MessageID: "wamid.1",
+			Events: []StatusEvent{
+				{Status: StatusSent, Timestamp: now},
+				{Status: StatusRead, Timestamp: now.Add(2 * time.Minute)},
+				{Status: StatusDelivered, Timestamp: now.Add(time.Minute)},
+			},
+		}
+
+		// act
+		current := timeline.Current()
+		readAt, read := timeline.At(StatusRead)
+		_, failed := timeline.At(StatusFailed)
+
+		// assert
+		assert.Equal(t, StatusRead, current)
+		assert.True(t, read)
+		assert.Equal(t, now.Add(2*time.Minute), readAt)
+		assert.False(t, failed)
+	})
+
+	t.Run("should report failed messages", func(t *testing.T) {
+		// arrange
+		timeline := DeliveryTimeline{Events: []StatusEvent{{Status: StatusSent}, {Status: StatusFailed}}}
+
+		// act & assert
+		assert.Equal(t, StatusFailed, timeline.Current())
+	})
+
+	t.Run("should have no status without events", func(t *testing.T) {
+		assert.Empty(t, DeliveryTimeline{}.Current())
+	})
+}
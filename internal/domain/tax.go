@@ -0,0 +1,304 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrInvalidSimulation is returned when the figures of a tax simulation are not valid
+var ErrInvalidSimulation = errors.New("invalid tax simulation")
+
+// FatorRThreshold is the ratio of the payroll to the revenue from which the medical services
+// taxed by the Simples Nacional move from Anexo V to Anexo III
+const FatorRThreshold = 0.28
+
+// TaxRegime identifies the way a doctor pays taxes on their income
+type TaxRegime string
+
+const (
+	// TaxRegimeSimplesAnexoIII is a company in the Simples Nacional with a Fator R of at least 28%
+	TaxRegimeSimplesAnexoIII TaxRegime = "simples_anexo_iii"
+	// TaxRegimeSimplesAnexoV is a company in the Simples Nacional with a Fator R under 28%
+	TaxRegimeSimplesAnexoV TaxRegime = "simples_anexo_v"
+	// TaxRegimeLucroPresumido is a company taxed on the profit presumed from its revenue
+	TaxRegimeLucroPresumido TaxRegime = "lucro_presumido"
+	// TaxRegimeCarneLeao is a doctor receiving as a person and paying the IRPF monthly through the carnê-leão
+	TaxRegimeCarneLeao TaxRegime = "carne_leao"
+)
+
+// TaxBracket is a bracket of a progressive table: the income up to UpTo is taxed at Rate,
+// less Deduction. The brackets of a table are ordered by UpTo, the last one having no limit.
+type TaxBracket struct {
+	UpTo      float64
+	Rate      float64
+	Deduction float64
+}
+
+// TaxRules are the tables and rates of a year used to simulate the taxes of a doctor
+type TaxRules struct {
+	Year int
+	// AnexoIII and AnexoV are the tables of the Simples Nacional, by revenue of the last 12 months
+	AnexoIII []TaxBracket
+	AnexoV   []TaxBracket
+	// SimplesLimit is the yearly revenue above which a company can not opt for the Simples Nacional
+	SimplesLimit float64
+
+	// IncomeTax is the monthly table of the IRPF, and SimplifiedDiscount the monthly discount
+	// taken instead of the deductions when it is higher
+	IncomeTax          []TaxBracket
+	SimplifiedDiscount float64
+	// The IRPF of the monthly income up to ReductionExemption is reduced to zero, and the IRPF of the
+	// income up to ReductionLimit is reduced by ReductionBase less ReductionFactor times the income
+	ReductionExemption float64
+	ReductionLimit     float64
+	ReductionBase      float64
+	ReductionFactor    float64
+
+	// INSSCeiling is the monthly income above which no INSS is paid by the insured. Partners pay
+	// ProLaboreINSSRate of the pró-labore, doctors working as a person SelfEmployedINSSRate of their
+	// income, and companies out of the Simples Nacional EmployerINSSRate of the pró-labore, without ceiling.
+	INSSCeiling          float64
+	ProLaboreINSSRate    float64
+	SelfEmployedINSSRate float64
+	EmployerINSSRate     float64
+
+	// PresumedProfitRate is the share of the revenue of medical services presumed as profit. IRPJ is
+	// charged at IRPJRate on the presumed profit, plus IRPJSurchargeRate on the monthly profit above
+	// IRPJSurchargeFrom, and CSLL at CSLLRate; PIS, COFINS and ISS are charged on the revenue.
+	PresumedProfitRate float64
+	IRPJRate           float64
+	IRPJSurchargeRate  float64
+	IRPJSurchargeFrom  float64
+	CSLLRate           float64
+	PISRate            float64
+	COFINSRate         float64
+	// ISSRate is the rate of the municipal service tax. It varies from 2% to 5% with the municipality,
+	// the simulation takes the highest one.
+	ISSRate float64
+}
+
+// TaxRules2026 are the rules in force in 2026: the tables of the Simples Nacional of LC 123/2006, the
+// IRPF table with the reduction of Lei 15.270/2025 and the INSS ceiling of 2026.
+// They must be reviewed every year.
+var TaxRules2026 = TaxRules{
+	Year: 2026,
+	AnexoIII: []TaxBracket{
+		{UpTo: 180_000, Rate: 0.06, Deduction: 0},
+		{UpTo: 360_000, Rate: 0.112, Deduction: 9_360},
+		{UpTo: 720_000, Rate: 0.135, Deduction: 17_640},
+		{UpTo: 1_800_000, Rate: 0.16, Deduction: 35_640},
+		{UpTo: 3_600_000, Rate: 0.21, Deduction: 125_640},
+		{UpTo: 4_800_000, Rate: 0.33, Deduction: 648_000},
+	},
+	AnexoV: []TaxBracket{
+		{UpTo: 180_000, Rate: 0.155, Deduction: 0},
+		{UpTo: 360_000, Rate: 0.18, Deduction: 4_500},
+		{UpTo: 720_000, Rate: 0.195, Deduction: 9_900},
+		{UpTo: 1_800_000, Rate: 0.205, Deduction: 17_100},
+		{UpTo: 3_600_000, Rate: 0.23, Deduction: 62_100},
+		{UpTo: 4_800_000, Rate: 0.305, Deduction: 540_000},
+	},
+	SimplesLimit: 4_800_000,
+
+	IncomeTax: []TaxBracket{
+		{UpTo: 2_428.80, Rate: 0, Deduction: 0},
+		{UpTo: 2_826.65, Rate: 0.075, Deduction: 182.16},
+		{UpTo: 3_751.05, Rate: 0.15, Deduction: 394.16},
+		{UpTo: 4_664.68, Rate: 0.225, Deduction: 675.49},
+		{UpTo: math.Inf(1), Rate: 0.275, Deduction: 908.73},
+	},
+	SimplifiedDiscount: 607.20,
+	ReductionExemption: 5_000,
+	ReductionLimit:     7_350,
+	ReductionBase:      978.62,
+	ReductionFactor:    0.133145,
+
+	INSSCeiling:          8_475.55,
+	ProLaboreINSSRate:    0.11,
+	SelfEmployedINSSRate: 0.20,
+	EmployerINSSRate:     0.20,
+
+	PresumedProfitRate: 0.32,
+	IRPJRate:           0.15,
+	IRPJSurchargeRate:  0.10,
+	IRPJSurchargeFrom:  20_000,
+	CSLLRate:           0.09,
+	PISRate:            0.0065,
+	COFINSRate:         0.03,
+	ISSRate:            0.05,
+}
+
+// TaxEstimate is the monthly tax paid by a doctor in a regime
+type TaxEstimate struct {
+	Regime TaxRegime
+	// ProLabore is the monthly pró-labore considered, zero for the doctors working as a person
+	ProLabore float64
+	// Company are the taxes paid by the company: the DAS of the Simples Nacional, or the IRPJ, CSLL,
+	// PIS, COFINS, ISS and INSS of the Lucro Presumido
+	Company float64
+	// Personal are the INSS and IRPF paid by the doctor on the pró-labore or on their income as a person
+	Personal float64
+}
+
+// Total returns the taxes paid by the company and by the doctor
+func (e TaxEstimate) Total() float64 {
+	return e.Company + e.Personal
+}
+
+// TaxSimulation compares the monthly taxes of a doctor in each regime
+type TaxSimulation struct {
+	Year      int
+	Revenue   float64
+	ProLabore float64
+	// FatorR is the ratio of the pró-labore to the revenue
+	FatorR float64
+	// AnexoIIIRate and AnexoVRate are the effective rates of the Simples Nacional on the revenue in
+	// each annex, zero when the revenue is above the limit of the Simples Nacional
+	AnexoIIIRate float64
+	AnexoVRate   float64
+	// FatorRProLabore is the pró-labore reaching the Fator R of 28%, rounded to cents
+	FatorRProLabore float64
+	// Estimates are the taxes in each regime available, the cheapest first. A company in Anexo V is
+	// also estimated in Anexo III with the pró-labore reaching the Fator R.
+	Estimates []TaxEstimate
+}
+
+// Best returns the cheapest regime
+func (s *TaxSimulation) Best() TaxEstimate {
+	return s.Estimates[0]
+}
+
+// Simples returns the estimate of the Simples Nacional with the informed pró-labore, and false
+// when the revenue is above the limit of the Simples Nacional
+func (s *TaxSimulation) Simples() (TaxEstimate, bool) {
+	for _, estimate := range s.Estimates {
+		isSimples := estimate.Regime == TaxRegimeSimplesAnexoIII || estimate.Regime == TaxRegimeSimplesAnexoV
+		if isSimples && estimate.ProLabore == s.ProLabore {
+			return estimate, true
+		}
+	}
+	return TaxEstimate{}, false
+}
+
+// SimulateTaxes compares the monthly taxes of a doctor with the given monthly revenue and pró-labore
+// in the Simples Nacional, in the Lucro Presumido and as a person paying the carnê-leão. The revenue
+// is taken as the same every month, and the pró-labore as the whole payroll of the company.
+// Deductible expenses and the taxes on the distributed profits are not considered.
+func SimulateTaxes(revenue, proLabore float64, rules TaxRules) (*TaxSimulation, error) {
+	if revenue <= 0 {
+		return nil, fmt.Errorf("%w: revenue must be positive", ErrInvalidSimulation)
+	}
+	if proLabore < 0 || proLabore > revenue {
+		return nil, fmt.Errorf("%w: pró-labore must be between zero and the revenue", ErrInvalidSimulation)
+	}
+
+	s := &TaxSimulation{
+		Year:            rules.Year,
+		Revenue:         revenue,
+		ProLabore:       proLabore,
+		FatorR:          proLabore / revenue,
+		FatorRProLabore: roundCents(revenue * FatorRThreshold),
+	}
+
+	yearly := revenue * 12
+	if yearly <= rules.SimplesLimit {
+		s.AnexoIIIRate = simplesRate(rules.AnexoIII, yearly)
+		s.AnexoVRate = simplesRate(rules.AnexoV, yearly)
+		s.Estimates = append(s.Estimates, rules.simples(s, proLabore))
+		if proLabore < s.FatorRProLabore {
+			s.Estimates = append(s.Estimates, rules.simples(s, s.FatorRProLabore))
+		}
+	}
+	s.Estimates = append(s.Estimates, rules.lucroPresumido(revenue, proLabore), rules.carneLeao(revenue))
+
+	sort.SliceStable(s.Estimates, func(i, j int) bool {
+		return s.Estimates[i].Total() < s.Estimates[j].Total()
+	})
+	return s, nil
+}
+
+// simples estimates the taxes of a company in the Simples Nacional paying the given pró-labore.
+// The DAS includes the INSS of the company in Anexos III and V.
+func (r TaxRules) simples(s *TaxSimulation, proLabore float64) TaxEstimate {
+	regime, rate := TaxRegimeSimplesAnexoV, s.AnexoVRate
+	if proLabore >= s.FatorRProLabore {
+		regime, rate = TaxRegimeSimplesAnexoIII, s.AnexoIIIRate
+	}
+	return TaxEstimate{
+		Regime:    regime,
+		ProLabore: proLabore,
+		Company:   roundCents(s.Revenue * rate),
+		Personal:  r.proLaboreTaxes(proLabore),
+	}
+}
+
+// lucroPresumido estimates the taxes of a company in the Lucro Presumido paying the given pró-labore
+func (r TaxRules) lucroPresumido(revenue, proLabore float64) TaxEstimate {
+	profit := revenue * r.PresumedProfitRate
+	irpj := profit*r.IRPJRate + math.Max(0, profit-r.IRPJSurchargeFrom)*r.IRPJSurchargeRate
+	csll := profit * r.CSLLRate
+	sales := revenue * (r.PISRate + r.COFINSRate + r.ISSRate)
+	return TaxEstimate{
+		Regime:    TaxRegimeLucroPresumido,
+		ProLabore: proLabore,
+		Company:   roundCents(irpj + csll + sales + proLabore*r.EmployerINSSRate),
+		Personal:  r.proLaboreTaxes(proLabore),
+	}
+}
+
+// carneLeao estimates the taxes of a doctor receiving the revenue as a person
+func (r TaxRules) carneLeao(revenue float64) TaxEstimate {
+	inss := math.Min(revenue, r.INSSCeiling) * r.SelfEmployedINSSRate
+	return TaxEstimate{
+		Regime:   TaxRegimeCarneLeao,
+		Personal: roundCents(inss + r.incomeTax(revenue, inss)),
+	}
+}
+
+// proLaboreTaxes returns the INSS and IRPF paid by the partner on the pró-labore
+func (r TaxRules) proLaboreTaxes(proLabore float64) float64 {
+	inss := math.Min(proLabore, r.INSSCeiling) * r.ProLaboreINSSRate
+	return roundCents(inss + r.incomeTax(proLabore, inss))
+}
+
+// incomeTax returns the monthly IRPF of the income with the given deductions, taking the simplified
+// discount instead when it is higher
+func (r TaxRules) incomeTax(income, deductions float64) float64 {
+	base := income - math.Max(deductions, r.SimplifiedDiscount)
+	if base <= 0 {
+		return 0
+	}
+
+	var tax float64
+	for _, bracket := range r.IncomeTax {
+		if base <= bracket.UpTo {
+			tax = base*bracket.Rate - bracket.Deduction
+			break
+		}
+	}
+
+	switch {
+	case income <= r.ReductionExemption:
+		tax = 0
+	case income <= r.ReductionLimit:
+		tax -= r.ReductionBase - r.ReductionFactor*income
+	}
+	return math.Max(0, tax)
+}
+
+// simplesRate returns the effective rate of the Simples Nacional table for the revenue of the last 12 months
+func simplesRate(table []TaxBracket, yearly float64) float64 {
+	for _, bracket := range table {
+		if yearly <= bracket.UpTo {
+			return (yearly*bracket.Rate - bracket.Deduction) / yearly
+		}
+	}
+	return 0
+}
+
+// roundCents rounds the amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateTaxes(t *testing.T) {
+	t.Run("should estimate Anexo III with the pró-labore reaching the Fator R for a company in Anexo V", func(t *testing.T) {
+		// act
+		s, err := SimulateTaxes(30_000, 3_000, TaxRules2026)
+
+		// assert
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.10, s.FatorR, 0.0001)
+		assert.InDelta(t, 0.086, s.AnexoIIIRate, 0.0001)
+		assert.InDelta(t, 0.1675, s.AnexoVRate, 0.0001)
+		assert.Equal(t, 8_400.0, s.FatorRProLabore)
+		assert.Equal(t, []TaxEstimate{
+			{Regime: TaxRegimeSimplesAnexoIII, ProLabore: 8_400, Company: 2_580, Personal: 2_071.17},
+			{Regime: TaxRegimeSimplesAnexoV, ProLabore: 3_000, Company: 5_025, Personal: 330},
+			{Regime: TaxRegimeLucroPresumido, ProLabore: 3_000, Company: 5_499, Personal: 330},
+			{Regime: TaxRegimeCarneLeao, Personal: 8_570.22},
+		}, s.Estimates)
+		assert.Equal(t, TaxRegimeSimplesAnexoIII, s.Best().Regime)
+		simples, ok := s.Simples()
+		assert.True(t, ok)
+		assert.Equal(t, TaxRegimeSimplesAnexoV, simples.Regime)
+	})
+
+	t.Run("should put a company with a Fator R of 28% or more in Anexo III", func(t *testing.T) {
+		// act
+		s, err := SimulateTaxes(10_000, 5_000, TaxRules2026)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []TaxEstimate{
+			{Regime: TaxRegimeSimplesAnexoIII, ProLabore: 5_000, Company: 600, Personal: 550},
+			{Regime: TaxRegimeCarneLeao, Personal: 3_070.22},
+			{Regime: TaxRegimeLucroPresumido, ProLabore: 5_000, Company: 2_633, Personal: 550},
+		}, s.Estimates)
+		assert.Equal(t, 1_150.0, s.Best().Total())
+	})
+
+	t.Run("should reach Anexo III with exactly 28% of the revenue", func(t *testing.T) {
+		// act
+		s, err := SimulateTaxes(30_000, 8_400, TaxRules2026)
+
+		// assert
+		assert.NoError(t, err)
+		simples, _ := s.Simples()
+		assert.Equal(t, TaxRegimeSimplesAnexoIII, simples.Regime)
+		assert.Len(t, s.Estimates, 3)
+	})
+
+	t.Run("should leave the Simples Nacional out above its limit", func(t *testing.T) {
+		// act
+		s, err := SimulateTaxes(500_000, 20_000, TaxRules2026)
+
+		// assert
+		assert.NoError(t, err)
+		_, ok := s.Simples()
+		assert.False(t, ok)
+		assert.Zero(t, s.AnexoIIIRate)
+		assert.Len(t, s.Estimates, 2)
+		assert.Equal(t, TaxRegimeLucroPresumido, s.Best().Regime)
+		// IRPJ 15% of the presumed profit of 160.000 plus 10% above 20.000, CSLL, PIS, COFINS, ISS and INSS
+		assert.InDelta(t, 24_000+14_000+14_400+43_250+4_000, s.Best().Company, 0.001)
+	})
+
+	t.Run("should reject invalid figures", func(t *testing.T) {
+		for _, figures := range [][2]float64{{0, 0}, {-1_000, 0}, {10_000, -1}, {10_000, 12_000}} {
+			// act
+			_, err := SimulateTaxes(figures[0], figures[1], TaxRules2026)
+
+			// assert
+			assert.ErrorIs(t, err, ErrInvalidSimulation, figures)
+		}
+	})
+}
+
+func TestTaxRulesIncomeTax(t *testing.T) {
+	t.Run("should apply the progressive table with the highest deduction", func(t *testing.T) {
+		// act
+		simplified := TaxRules2026.incomeTax(10_000, 0)
+		deducted := TaxRules2026.incomeTax(10_000, 2_000)
+
+		// assert
+		assert.InDelta(t, 0.275*(10_000-607.20)-908.73, simplified, 0.001)
+		assert.InDelta(t, 0.275*8_000-908.73, deducted, 0.001)
+	})
+
+	t.Run("should exempt the income up to 5.000 and reduce the tax up to 7.350", func(t *testing.T) {
+		// act
+		exempt := TaxRules2026.incomeTax(5_000, 0)
+		reduced := TaxRules2026.incomeTax(6_000, 0)
+		full := TaxRules2026.incomeTax(7_350.01, 0)
+
+		// assert
+		assert.Zero(t, exempt)
+		assert.InDelta(t, 0.275*(6_000-607.20)-908.73-(978.62-0.133145*6_000), reduced, 0.001)
+		assert.InDelta(t, 0.275*(7_350.01-607.20)-908.73, full, 0.001)
+	})
+
+	t.Run("should not tax the income under the first bracket", func(t *testing.T) {
+		// act
+		tax := TaxRules2026.incomeTax(2_000, 220)
+
+		// assert
+		assert.Zero(t, tax)
+	})
+}
@@ -0,0 +1,151 @@
+package domain
+
+// TemplateComponentType is the part of a template a component fills in
+type TemplateComponentType string
+
+const (
+	// TemplateComponentHeader fills in the header of a template
+	TemplateComponentHeader TemplateComponentType = "header"
+	// TemplateComponentBody fills in the body of a template
+	TemplateComponentBody TemplateComponentType = "body"
+	// TemplateComponentButton fills in one of the buttons of a template
+	TemplateComponentButton TemplateComponentType = "button"
+)
+
+// TemplateButtonSubType is the kind of button filled in by a button component
+type TemplateButtonSubType string
+
+const (
+	// TemplateButtonQuickReply is a quick reply button
+	TemplateButtonQuickReply TemplateButtonSubType = "quick_reply"
+	// TemplateButtonURL is a call-to-action button with a dynamic URL suffix
+	TemplateButtonURL TemplateButtonSubType = "url"
+)
+
+// TemplateParameterType is the type of value of a template parameter
+type TemplateParameterType string
+
+const (
+	// TemplateParameterText is a plain text parameter
+	TemplateParameterText TemplateParameterType = "text"
+	// TemplateParameterCurrency is a localized currency amount
+	TemplateParameterCurrency TemplateParameterType = "currency"
+	// TemplateParameterDateTime is a localized date and time
+	TemplateParameterDateTime TemplateParameterType = "date_time"
+	// TemplateParameterImage is an image header
+	TemplateParameterImage TemplateParameterType = "image"
+	// TemplateParameterDocument is a document header
+	TemplateParameterDocument TemplateParameterType = "document"
+	// TemplateParameterVideo is a video header
+	TemplateParameterVideo TemplateParameterType = "video"
+	// TemplateParameterPayload is the payload of a quick reply button
+	TemplateParameterPayload TemplateParameterType = "payload"
+)
+
+// TemplateMessage is a pre-approved message template, the only kind of message
+// accepted outside the 24-hour customer service window
+type TemplateMessage struct {
+	Name         string
+	LanguageCode string
+	Components   []TemplateComponent
+}
+
+// TemplateComponent holds the parameters of a header, body or button of a template
+type TemplateComponent struct {
+	Type       TemplateComponentType
+	SubType    TemplateButtonSubType
+	Index      int
+	Parameters []TemplateParameter
+}
+
+// TemplateParameter is a typed value replacing a placeholder of a template
+type TemplateParameter struct {
+	Type     TemplateParameterType
+	Text     string
+	Payload  string
+	Currency *TemplateCurrency
+	DateTime *TemplateDateTime
+	Media    *TemplateMedia
+}
+
+// TemplateCurrency is a currency amount, Amount1000 being the amount multiplied by 1000
+type TemplateCurrency struct {
+	FallbackValue string
+	Code          string
+	Amount1000    int64
+}
+
+// TemplateDateTime is a date and time shown as FallbackValue
+type TemplateDateTime struct {
+	FallbackValue string
+}
+
+// TemplateMedia references an uploaded media by ID or a public media by link
+type TemplateMedia struct {
+	ID       string
+	Link     string
+	Filename string
+}
+
+// HeaderComponent creates a header component with the given parameters
+func HeaderComponent(parameters ...TemplateParameter) TemplateComponent {
+	return TemplateComponent{Type: TemplateComponentHeader, Parameters: parameters}
+}
+
+// BodyComponent creates a body component with the given parameters
+func BodyComponent(parameters ...TemplateParameter) TemplateComponent {
+	return TemplateComponent{Type: TemplateComponentBody, Parameters: parameters}
+}
+
+// QuickReplyButtonComponent sets the payload returned when the quick reply button at index is tapped
+func QuickReplyButtonComponent(index int, payload string) TemplateComponent {
+	return TemplateComponent{
+		Type:       TemplateComponentButton,
+		SubType:    TemplateButtonQuickReply,
+		Index:      index,
+		Parameters: []TemplateParameter{{Type: TemplateParameterPayload, Payload: payload}},
+	}
+}
+
+// URLButtonComponent sets the dynamic suffix of the URL button at index
+func URLButtonComponent(index int, suffix string) TemplateComponent {
+	return TemplateComponent{
+		Type:       TemplateComponentButton,
+		SubType:    TemplateButtonURL,
+		Index:      index,
+		Parameters: []TemplateParameter{TextParameter(suffix)},
+	}
+}
+
+// TextParameter creates a text parameter
+func TextParameter(text string) TemplateParameter {
+	return TemplateParameter{Type: TemplateParameterText, Text: text}
+}
+
+// CurrencyParameter creates a currency parameter from an amount in cents
+func CurrencyParameter(fallbackValue, code string, cents int64) TemplateParameter {
+	return TemplateParameter{
+		Type:     TemplateParameterCurrency,
+		Currency: &TemplateCurrency{FallbackValue: fallbackValue, Code: code, Amount1000: cents * 10},
+	}
+}
+
+// DateTimeParameter creates a date and time parameter
+func DateTimeParameter(fallbackValue string) TemplateParameter {
+	return TemplateParameter{Type: TemplateParameterDateTime, DateTime: &TemplateDateTime{FallbackValue: fallbackValue}}
+}
+
+// ImageParameter creates an image header parameter from a public link
+func ImageParameter(link string) TemplateParameter {
+	return TemplateParameter{Type: TemplateParameterImage, Media: &TemplateMedia{Link: link}}
+}
+
+// DocumentParameter creates a document header parameter from a public link
+func DocumentParameter(link, filename string) TemplateParameter {
+	return TemplateParameter{Type: TemplateParameterDocument, Media: &TemplateMedia{Link: link, Filename: filename}}
+}
+
+// VideoParameter creates a video header parameter from a public link
+func VideoParameter(link string) TemplateParameter {
+	return TemplateParameter{Type: TemplateParameterVideo, Media: &TemplateMedia{Link: link}}
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	// The zone database is embedded so the zones are known in containers without it
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is the official time of Brazil (horário de Brasília), assumed for the contacts
+// whose location is unknown
+const DefaultTimezone = "America/Sao_Paulo"
+
+// stateTimezones maps each state to the zone of its capital.
+// Brazil does not observe daylight saving time since 2019.
+var stateTimezones = map[string]string{
+	"AC": "America/Rio_Branco",
+	"AL": "America/Maceio",
+	"AP": "America/Belem",
+	"AM": "America/Manaus",
+	"BA": "America/Bahia",
+	"CE": "America/Fortaleza",
+	"DF": "America/Sao_Paulo",
+	"ES": "America/Sao_Paulo",
+	"GO": "America/Sao_Paulo",
+	"MA": "America/Fortaleza",
+	"MT": "America/Cuiaba",
+	"MS": "America/Campo_Grande",
+	"MG": "America/Sao_Paulo",
+	"PA": "America/Belem",
+	"PB": "America/Fortaleza",
+	"PR": "America/Sao_Paulo",
+	"PE": "America/Recife",
+	"PI": "America/Fortaleza",
+	"RJ": "America/Sao_Paulo",
+	"RN": "America/Fortaleza",
+	"RS": "America/Sao_Paulo",
+	"RO": "America/Porto_Velho",
+	"RR": "America/Boa_Vista",
+	"SC": "America/Sao_Paulo",
+	"SP": "America/Sao_Paulo",
+	"SE": "America/Maceio",
+	"TO": "America/Araguaina",
+}
+
+// municipalityTimezones maps the IBGE code of the municipalities that do not follow the zone of
+// their state to their zone. The west of Amazonas keeps the time of Acre (UTC-5).
+var municipalityTimezones = map[string]string{
+	"2605459": "America/Noronha",  // Fernando de Noronha (PE)
+	"1300201": "America/Eirunepe", // Atalaia do Norte (AM)
+	"1300607": "America/Eirunepe", // Benjamin Constant (AM)
+	"1300706": "America/Eirunepe", // Boca do Acre (AM)
+	"1301407": "America/Eirunepe", // Eirunepé (AM)
+	"1301506": "America/Eirunepe", // Envira (AM)
+	"1301654": "America/Eirunepe", // Guajará (AM)
+	"1301803": "America/Eirunepe", // Ipixuna (AM)
+	"1301951": "America/Eirunepe", // Itamarati (AM)
+	"1303502": "America/Eirunepe", // Pauini (AM)
+}
+
+// areaCodeStates maps the area codes (DDD) to their state
+var areaCodeStates = map[string]string{
+	"11": "SP", "12": "SP", "13": "SP", "14": "SP", "15": "SP", "16": "SP", "17": "SP", "18": "SP", "19": "SP",
+	"21": "RJ", "22": "RJ", "24": "RJ", "27": "ES", "28": "ES",
+	"31": "MG", "32": "MG", "33": "MG", "34": "MG", "35": "MG", "37": "MG", "38": "MG",
+	"41": "PR", "42": "PR", "43": "PR", "44": "PR", "45": "PR", "46": "PR",
+	"47": "SC", "48": "SC", "49": "SC", "51": "RS", "53": "RS", "54": "RS", "55": "RS",
+	"61": "DF", "62": "GO", "64": "GO", "63": "TO", "65": "MT", "66": "MT", "67": "MS",
+	"68": "AC", "69": "RO", "71": "BA", "73": "BA", "74": "BA", "75": "BA", "77": "BA", "79": "SE",
+	"81": "PE", "87": "PE", "82": "AL", "83": "PB", "84": "RN", "85": "CE", "88": "CE", "86": "PI", "89": "PI",
+	"91": "PA", "93": "PA", "94": "PA", "92": "AM", "97": "AM", "95": "RR", "96": "AP", "98": "MA", "99": "MA",
+}
+
+// locations caches the loaded zones by name
+var locations sync.Map
+
+// StateOfPhone returns the state of the area code (DDD) of a Brazilian phone number in
+// international format, like 5544999887766
+func StateOfPhone(phone string) (string, bool) {
+	phone = strings.TrimPrefix(phone, "+")
+	if !strings.HasPrefix(phone, "55") || len(phone) < 12 {
+		return "", false
+	}
+	state, ok := areaCodeStates[phone[2:4]]
+	return state, ok
+}
+
+// TimezoneName returns the zone of a contact: the zone of the municipality or of the state they
+// informed when known, else the zone of the area code of their phone number, else DefaultTimezone
+func TimezoneName(phone, state, municipalityCode string) string {
+	if zone, ok := municipalityTimezones[municipalityCode]; ok {
+		return zone
+	}
+	if zone, ok := stateTimezones[state]; ok {
+		return zone
+	}
+	if phoneState, ok := StateOfPhone(phone); ok {
+		return stateTimezones[phoneState]
+	}
+	return DefaultTimezone
+}
+
+// Timezone returns the location of the zone of a contact, as described by TimezoneName
+func Timezone(phone, state, municipalityCode string) *time.Location {
+	return loadTimezone(TimezoneName(phone, state, municipalityCode))
+}
+
+// loadTimezone returns the location of the zone with the given name
+func loadTimezone(name string) *time.Location {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		// Unknown zones fall back to the official time of Brazil, UTC-3
+		loc = time.FixedZone("-03", -3*60*60)
+	}
+	locations.Store(name, loc)
+	return loc
+}
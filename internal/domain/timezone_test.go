@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateOfPhone(t *testing.T) {
+	tests := []struct {
+		phone    string
+		expected string
+		ok       bool
+	}{
+		{phone: "554499887766", expected: "PR", ok: true},
+		{phone: "5511987654321", expected: "SP", ok: true},
+		{phone: "+5568999887766", expected: "AC", ok: true},
+		{phone: "5520999887766", expected: "", ok: false},
+		{phone: "14155550100", expected: "", ok: false},
+		{phone: "5544", expected: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run("should read the area code of "+tt.phone, func(t *testing.T) {
+			// act
+			state, ok := StateOfPhone(tt.phone)
+
+			// assert
+			assert.Equal(t, tt.expected, state)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+
+	t.Run("should know the state of every area code", func(t *testing.T) {
+		for code, state := range areaCodeStates {
+			assert.True(t, IsValidUF(state), code)
+		}
+	})
+}
+
+func TestTimezone(t *testing.T) {
+	t.Run("should have a zone for every state", func(t *testing.T) {
+		for uf := range States {
+			_, err := time.LoadLocation(stateTimezones[uf])
+			assert.NoError(t, err, uf)
+		}
+	})
+
+	t.Run("should prefer the municipality, then the state, then the area code", func(t *testing.T) {
+		assert.Equal(t, "America/Noronha", TimezoneName("558199887766", "PE", "2605459"))
+		assert.Equal(t, "America/Recife", TimezoneName("558199887766", "PE", "2611606"))
+		assert.Equal(t, "America/Eirunepe", TimezoneName("559799887766", "AM", "1301407"))
+		assert.Equal(t, "America/Manaus", TimezoneName("559799887766", "AM", "1302603"))
+		assert.Equal(t, "America/Cuiaba", TimezoneName("554499887766", "MT", ""))
+		assert.Equal(t, "America/Rio_Branco", TimezoneName("556899887766", "", ""))
+		assert.Equal(t, "America/Manaus", TimezoneName("559299887766", "", ""))
+	})
+
+	t.Run("should have a known zone for every municipality with its own zone", func(t *testing.T) {
+		for code, zone := range municipalityTimezones {
+			_, err := time.LoadLocation(zone)
+			assert.NoError(t, err, code)
+		}
+	})
+
+	t.Run("should assume the official time of Brazil for unknown locations", func(t *testing.T) {
+		assert.Equal(t, DefaultTimezone, TimezoneName("14155550100", "", ""))
+		assert.Equal(t, DefaultTimezone, TimezoneName("", "XX", ""))
+	})
+
+	t.Run("should load the location of the zone", func(t *testing.T) {
+		// arrange
+		at := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		// act
+		acre := Timezone("556899887766", "", "")
+		noronha := Timezone("558199887766", "PE", "2605459")
+		eirunepe := Timezone("559799887766", "AM", "1301407")
+
+		// assert
+		assert.Equal(t, "America/Rio_Branco", acre.String())
+		assert.Equal(t, 7, at.In(acre).Hour())
+		assert.Equal(t, 10, at.In(noronha).Hour())
+		assert.Equal(t, 7, at.In(eirunepe).Hour())
+		assert.Same(t, acre, Timezone("556899887766", "AC", ""))
+	})
+
+	t.Run("should fall back to UTC-3 for unknown zones", func(t *testing.T) {
+		// act
+		loc := loadTimezone("America/Atlantida")
+
+		// assert
+		assert.Equal(t, 9, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC).In(loc).Hour())
+	})
+}
+
+func TestConversationLocation(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should use the area code until the state is informed", func(t *testing.T) {
+		// arrange
+		conversation := NewConversation("556899887766", now)
+
+		// act
+		before := conversation.Location().String()
+		conversation.State = "PR"
+		conversation.Locate()
+
+		// assert
+		assert.Equal(t, "America/Rio_Branco", before)
+		assert.Equal(t, "America/Sao_Paulo", conversation.Location().String())
+	})
+
+	t.Run("should keep the zone when the conversation restarts", func(t *testing.T) {
+		// arrange
+		conversation := NewConversation("554499887766", now)
+		conversation.State = "MT"
+		conversation.Locate()
+
+		// act
+		conversation.Restart(now.Add(time.Hour))
+
+		// assert
+		assert.Empty(t, conversation.State)
+		assert.Equal(t, "America/Cuiaba", conversation.Location().String())
+	})
+}
@@ -0,0 +1,25 @@
+// Package clock provides the current time, so that the code reading it can be tested at fixed instants
+package clock
+
+import "time"
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Func adapts a function to the Clock interface
+type Func func() time.Time
+
+// Now calls the function
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// System is the clock of the host
+var System Clock = Func(time.Now)
+
+// Fixed returns a clock stopped at t
+func Fixed(t time.Time) Clock {
+	return Func(func() time.Time { return t })
+}
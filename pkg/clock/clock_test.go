@@ -0,0 +1,30 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	t.Run("should stop a fixed clock at the given time", func(t *testing.T) {
+		// arrange
+		at := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		// act
+		clock := Fixed(at)
+
+		// assert
+		assert.Equal(t, at, clock.Now())
+		assert.Equal(t, at, clock.Now())
+	})
+
+	t.Run("should read the time of the host", func(t *testing.T) {
+		// act
+		now := System.Now()
+
+		// assert
+		assert.WithinDuration(t, time.Now(), now, time.Second)
+	})
+}
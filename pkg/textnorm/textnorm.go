@@ -0,0 +1,44 @@
+// Package textnorm normalizes the Portuguese text typed by users, so that it can be compared
+// regardless of case, accents and punctuation
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// accents folds the accented letters of Portuguese into their base letter
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Normalize lowercases the text, folds its accents and replaces punctuation with single spaces,
+// so "Ji-Paraná" reads "ji parana" and "Santa Bárbara d'Oeste" reads "santa barbara d oeste"
+func Normalize(text string) string {
+	text = accents.Replace(strings.ToLower(text))
+
+	var b strings.Builder
+	separated := false
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separated = true
+			continue
+		}
+		if separated && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		separated = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Words returns the words of the normalized text
+func Words(text string) []string {
+	return strings.Fields(Normalize(text))
+}
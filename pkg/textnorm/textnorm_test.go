@@ -0,0 +1,19 @@
+package textnorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "sao jose dos campos", Normalize("  SÃO JOSÉ   dos Campos "))
+	assert.Equal(t, "santa barbara d oeste", Normalize("Santa Bárbara d'Oeste"))
+	assert.Equal(t, "ji parana", Normalize("Ji-Paraná"))
+	assert.Equal(t, "", Normalize(" - "))
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t, []string{"como", "funciona", "o", "pro", "labore"}, Words("Como funciona o pró-labore?"))
+	assert.Empty(t, Words("?!"))
+}